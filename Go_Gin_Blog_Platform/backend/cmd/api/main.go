@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -15,9 +16,13 @@ import (
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/db"
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/email"
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/logging"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/oidc"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/policy"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/ratelimit"
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
 	httptransport "github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/transport/http"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/worker"
 )
 
 func main() {
@@ -51,22 +56,89 @@ func main() {
 	postRepo := repository.NewPostRepository(store.Gorm())
 	refreshRepo := repository.NewRefreshTokenRepository(store.Gorm())
 	passwordResetRepo := repository.NewPasswordResetTokenRepository(store.Gorm())
+	emailChangeRepo := repository.NewEmailChangeTokenRepository(store.Gorm())
+	verificationRepo := repository.NewEmailVerificationTokenRepository(store.Gorm())
+	taxonomyRepo := repository.NewTaxonomyRepository(store.Gorm())
+	revisionRepo := repository.NewPostRevisionRepository(store.Gorm())
+	reviewRepo := repository.NewPostReviewRepository(store.Gorm())
+	commentRepo := repository.NewCommentRepository(store.Gorm())
+	throttleRepo := repository.NewLoginThrottleRepository(store.Gorm())
+	mfaRepo := repository.NewMFARepository(store.Gorm())
+	identityRepo := repository.NewIdentityRepository(store.Gorm())
+	patRepo := repository.NewPersonalAccessTokenRepository(store.Gorm())
+	roleRepo := repository.NewRoleRepository(store.Gorm())
+	auditRepo := repository.NewAuditRepository(store.Gorm())
+	transactor := repository.NewTransactor(store.Gorm())
+
+	authz := policy.New(policy.DefaultGrants())
+	auditService := service.NewAuditService(auditRepo, authz)
+	roleService := service.NewRoleService(roleRepo, auditService, authz, transactor)
+	if err := loadRoles(cfg, roleService); err != nil {
+		panic(fmt.Errorf("failed to load role permissions: %w", err))
+	}
+
+	jwksHandler := httptransport.NewJWKSHandler(nil)
+	var keyRefresher *worker.KeyRefresher
+	if cfg.JWTSigningAlg != auth.AlgHS256 {
+		keyRing, err := loadKeyRing(cfg, repository.NewSigningKeyRepository(store.Gorm()), transactor)
+		if err != nil {
+			panic(fmt.Errorf("failed to load signing keys: %w", err))
+		}
+		tokenManager.UseKeyRing(keyRing)
+		jwksHandler = httptransport.NewJWKSHandler(keyRing)
+		keyRefresher = worker.NewKeyRefresher(logger, keyRing, time.Duration(cfg.JWTKeyRefreshS)*time.Second)
+		logger.Info("signing access tokens with rotating keys", "alg", cfg.JWTSigningAlg)
+	}
 
 	authService := service.NewAuthService(
 		logger,
 		userRepo,
 		refreshRepo,
 		passwordResetRepo,
+		emailChangeRepo,
+		verificationRepo,
+		throttleRepo,
+		mfaRepo,
+		patRepo,
+		auditService,
+		transactor,
 		tokenManager,
 		emailSender,
 		time.Duration(cfg.PasswordResetTTLMinutes)*time.Minute,
+		time.Duration(cfg.EmailVerificationTTLHours)*time.Hour,
+		service.LockoutPolicy{
+			UserThreshold: cfg.LoginLockoutThreshold,
+			IPThreshold:   cfg.LoginIPLockoutThreshold,
+			FailureWindow: time.Duration(cfg.LoginFailureWindowMinutes) * time.Minute,
+			BaseLockout:   time.Duration(cfg.LoginLockoutBaseMinutes) * time.Minute,
+			MaxLockout:    time.Duration(cfg.LoginLockoutMaxMinutes) * time.Minute,
+		},
 		cfg.FrontendBaseURL,
+		cfg.MFAIssuer,
 	)
 	authHandler := httptransport.NewAuthHandler(authService)
-	postService := service.NewPostService(postRepo)
+	oidcService := service.NewOIDCService(authService, identityRepo, resolveOIDCProviders(cfg))
+	oidcHandler := httptransport.NewOIDCHandler(oidcService, strings.HasPrefix(cfg.APIPublicBaseURL, "https://"))
+	postService := service.NewPostService(postRepo, taxonomyRepo, revisionRepo, reviewRepo, userRepo, auditService, transactor, authz)
 	postHandler := httptransport.NewPostHandler(postService)
-	adminService := service.NewAdminService(userRepo)
+	feedHandler := httptransport.NewFeedHandler(postService, cfg.FrontendBaseURL, cfg.APIPublicBaseURL, cfg.FeedTitle)
+	commentService := service.NewCommentService(commentRepo, postRepo, auditService, transactor, authz)
+	commentHandler := httptransport.NewCommentHandler(commentService)
+	taxonomyService := service.NewTaxonomyService(taxonomyRepo, auditService, transactor)
+	taxonomyHandler := httptransport.NewTaxonomyHandler(taxonomyService)
+	adminService := service.NewAdminService(userRepo, throttleRepo, auditService, transactor, authz)
 	adminHandler := httptransport.NewAdminHandler(adminService)
+	sessionService := service.NewSessionService(userRepo, refreshRepo, patRepo, auditService, transactor)
+	sessionHandler := httptransport.NewSessionHandler(sessionService)
+	patService := service.NewPersonalAccessTokenService(userRepo, patRepo, auditService, transactor, authz)
+	tokenHandler := httptransport.NewPersonalAccessTokenHandler(patService)
+	roleHandler := httptransport.NewRoleHandler(roleService)
+	auditHandler := httptransport.NewAuditHandler(auditService)
+
+	rolePolicy := httptransport.RolePolicy{VerifiedEmailRoles: cfg.EmailVerificationRoles}
+	if cfg.MFARequiredForAdmins {
+		rolePolicy.MFAPermissions = policy.AdminPermissions()
+	}
 
 	router := httptransport.NewRouter(logger, httptransport.RouterDependencies{
 		HealthChecker:       store,
@@ -74,7 +146,34 @@ func main() {
 		AuthHandler:         authHandler,
 		PostHandler:         postHandler,
 		AdminHandler:        adminHandler,
+		TaxonomyHandler:     taxonomyHandler,
+		FeedHandler:         feedHandler,
+		CommentHandler:      commentHandler,
+		SessionHandler:      sessionHandler,
+		JWKSHandler:         jwksHandler,
+		OIDCHandler:         oidcHandler,
+		TokenHandler:        tokenHandler,
+		RoleHandler:         roleHandler,
+		AuditHandler:        auditHandler,
+		RolePolicy:          rolePolicy,
+		Authorizer:          authz,
 		AccessTokenVerifier: tokenManager,
+		TokenAuthenticator:  patService,
+		CORS: httptransport.CORSConfig{
+			AllowedOrigins:   cfg.CORSOrigins,
+			AllowCredentials: cfg.CORSAllowCredentials,
+		},
+		TrustedProxies: cfg.TrustedProxies,
+		RateLimitStore: resolveRateLimitStore(cfg, store),
+		AuthRateLimits: httptransport.AuthRateLimits{
+			LoginIP:              cfg.RateLimitLoginIP,
+			LoginAccount:         cfg.RateLimitLoginAccount,
+			RegisterIP:           cfg.RateLimitRegisterIP,
+			PasswordResetIP:      cfg.RateLimitResetIP,
+			PasswordResetAccount: cfg.RateLimitResetAccount,
+			VerifyEmailAccount:   cfg.RateLimitVerifyAccount,
+			EmailChangeAccount:   cfg.RateLimitEmailChange,
+		},
 	})
 	server := &http.Server{
 		Addr:              ":" + cfg.Port,
@@ -92,11 +191,54 @@ func main() {
 		}
 	}()
 
+	publisher := worker.NewPublisher(logger, postRepo, time.Duration(cfg.PublishIntervalS)*time.Second)
+	publisher.Start(context.Background())
+	trashPurger := worker.NewTrashPurger(logger, postRepo, time.Duration(cfg.TrashRetentionDays)*24*time.Hour)
+	trashPurger.Start(context.Background())
+	policyRefresher := worker.NewPolicyRefresher(logger, roleService, time.Duration(cfg.PolicyRefreshS)*time.Second)
+	policyRefresher.Start(context.Background())
+	throttlePruner := worker.NewThrottlePruner(logger, authService)
+	throttlePruner.Start(context.Background())
+	jobs := []backgroundJob{publisher, trashPurger, policyRefresher, throttlePruner}
+	if keyRefresher != nil {
+		keyRefresher.Start(context.Background())
+		jobs = append(jobs, keyRefresher)
+	}
+
 	logger.Info("api listening", "addr", server.Addr)
-	shutdownGracefully(server, logger)
+	shutdownGracefully(server, logger, jobs...)
+}
+
+// loadRoles replaces the built-in defaults with the grants stored in roles and
+// role_permissions.
+func loadRoles(cfg config.Config, roles *service.RoleService) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.RequestTimeoutS)*time.Second)
+	defer cancel()
+	return roles.Reload(ctx)
+}
+
+// loadKeyRing creates the first signing key on a fresh database, so switching
+// JWT_SIGNING_ALG away from HS256 needs no manual step.
+func loadKeyRing(cfg config.Config, keys repository.SigningKeyRepository, tx repository.Transactor) (*auth.KeyRing, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.RequestTimeoutS)*time.Second)
+	defer cancel()
+
+	keyService := service.NewSigningKeyService(keys, tx)
+	if err := keyService.EnsureActiveKey(ctx, cfg.JWTSigningAlg); err != nil {
+		return nil, err
+	}
+	ring := auth.NewKeyRing(keyService.Load)
+	if err := ring.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return ring, nil
+}
+
+type backgroundJob interface {
+	Stop(ctx context.Context) error
 }
 
-func shutdownGracefully(server *http.Server, logger *slog.Logger) {
+func shutdownGracefully(server *http.Server, logger *slog.Logger, jobs ...backgroundJob) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
@@ -110,6 +252,12 @@ func shutdownGracefully(server *http.Server, logger *slog.Logger) {
 		os.Exit(1)
 	}
 
+	for _, job := range jobs {
+		if err := job.Stop(ctx); err != nil {
+			logger.Error("background job shutdown failed", "error", err)
+		}
+	}
+
 	logger.Info("server stopped")
 }
 
@@ -124,3 +272,20 @@ func resolveEmailSender(cfg config.Config, logger *slog.Logger) email.Sender {
 
 	return email.NewStubSender(logger)
 }
+
+func resolveOIDCProviders(cfg config.Config) map[string]service.OIDCProvider {
+	client := &http.Client{Timeout: time.Duration(cfg.RequestTimeoutS) * time.Second}
+	providers := make(map[string]service.OIDCProvider, len(cfg.OIDCProviders))
+	for _, providerConfig := range cfg.OIDCProviders {
+		providers[providerConfig.Name] = oidc.NewProvider(providerConfig, client)
+	}
+	return providers
+}
+
+func resolveRateLimitStore(cfg config.Config, store *db.Store) ratelimit.Store {
+	if cfg.RateLimitStore == "postgres" {
+		return ratelimit.NewPostgresStore(store.Gorm())
+	}
+
+	return ratelimit.NewMemoryStore()
+}
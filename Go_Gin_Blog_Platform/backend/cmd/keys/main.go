@@ -0,0 +1,129 @@
+// Command keys manages the JWT signing keys used when JWT_SIGNING_ALG is RS256
+// or EdDSA.
+//
+//	keys list
+//	keys rotate [-alg RS256|EdDSA] [-retire-after 24h]
+//	keys retire -kid <kid>
+//
+// Running API instances pick up a rotation within JWT_KEY_REFRESH_SECONDS.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/config"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/db"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/logging"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+)
+
+const usage = "usage: keys list | rotate [-alg RS256|EdDSA] [-retire-after 24h] | retire -kid <kid>"
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	store, err := db.New(cfg.DatabaseURL, logging.NewJSONLogger(cfg.AppEnv))
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer store.Close()
+
+	keys := service.NewSigningKeyService(
+		repository.NewSigningKeyRepository(store.Gorm()),
+		repository.NewTransactor(store.Gorm()),
+	)
+	ctx := context.Background()
+
+	switch args[0] {
+	case "list":
+		return list(ctx, keys)
+	case "rotate":
+		return rotate(ctx, cfg, keys, args[1:])
+	case "retire":
+		return retire(ctx, keys, args[1:])
+	default:
+		return errors.New(usage)
+	}
+}
+
+func list(ctx context.Context, keys *service.SigningKeyService) error {
+	items, err := keys.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "KID\tALG\tSTATUS\tCREATED\tROTATED\tRETIRED")
+	for _, item := range items {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			item.ID, item.Algorithm, item.Status,
+			formatTime(&item.CreatedAt), formatTime(item.RotatedAt), formatTime(item.RetiredAt))
+	}
+	return w.Flush()
+}
+
+func rotate(ctx context.Context, cfg config.Config, keys *service.SigningKeyService, args []string) error {
+	flags := flag.NewFlagSet("rotate", flag.ContinueOnError)
+	alg := flags.String("alg", cfg.JWTSigningAlg, "algorithm of the new key (RS256 or EdDSA)")
+	retireAfter := flags.Duration("retire-after", 24*time.Hour, "retire previous keys rotated out longer ago than this")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// Retiring sooner would reject access tokens that have not expired yet.
+	accessTTL := time.Duration(cfg.JWTAccessTTLMinutes) * time.Minute
+	if *retireAfter < accessTTL {
+		return fmt.Errorf("-retire-after must be at least the access token lifetime (%s)", accessTTL)
+	}
+
+	result, err := keys.Rotate(ctx, service.RotateKeyInput{Algorithm: *alg, RetireAfter: *retireAfter})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("active key is now %s (%s); %d previous key(s) retired\n", result.KeyID, *alg, result.Retired)
+	return nil
+}
+
+func retire(ctx context.Context, keys *service.SigningKeyService, args []string) error {
+	flags := flag.NewFlagSet("retire", flag.ContinueOnError)
+	kid := flags.String("kid", "", "id of the previous key to retire")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := keys.Retire(ctx, *kid); err != nil {
+		if errors.Is(err, service.ErrSigningKeyNotFound) {
+			return fmt.Errorf("no previous key %q; the active key cannot be retired", *kid)
+		}
+		return err
+	}
+	fmt.Printf("key %s retired\n", *kid)
+	return nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
@@ -13,12 +13,29 @@ import (
 const (
 	TokenTypeAccess  = "access"
 	TokenTypeRefresh = "refresh"
+	TokenTypeMFA     = "mfa"
 )
 
+// MFAChallengeTTL is how long a user has to enter their second factor after
+// their password was accepted.
+const MFAChallengeTTL = 5 * time.Minute
+
 var ErrInvalidToken = errors.New("invalid token")
 
 type AccessClaims struct {
 	Role      string `json:"role"`
+	TokenType string `json:"token_type"`
+	// SessionID is the refresh token family the access token was issued for.
+	SessionID     string `json:"sid,omitempty"`
+	EmailVerified bool   `json:"email_verified"`
+	// MFA is set when the session was signed in with a second factor.
+	MFA bool `json:"mfa,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// MFAChallengeClaims identify a user whose password was accepted but who still
+// has to present a second factor.
+type MFAChallengeClaims struct {
 	TokenType string `json:"token_type"`
 	jwt.RegisteredClaims
 }
@@ -28,11 +45,16 @@ type RefreshClaims struct {
 	jwt.RegisteredClaims
 }
 
+// TokenManager signs access tokens with accessSecret (HS256) unless a key ring
+// is set, in which case they are signed with its active key and carry its kid.
+// Refresh and MFA challenge tokens are only ever read by this service and
+// always use the shared secrets.
 type TokenManager struct {
 	accessSecret  []byte
 	refreshSecret []byte
 	accessTTL     time.Duration
 	refreshTTL    time.Duration
+	keys          *KeyRing
 }
 
 func NewTokenManager(accessSecret, refreshSecret string, accessTTL, refreshTTL time.Duration) *TokenManager {
@@ -44,13 +66,22 @@ func NewTokenManager(accessSecret, refreshSecret string, accessTTL, refreshTTL t
 	}
 }
 
-func (m *TokenManager) GenerateAccessToken(userID, role string) (string, time.Time, error) {
+// UseKeyRing switches access tokens to asymmetric signing. HS256 access tokens
+// are no longer accepted afterwards.
+func (m *TokenManager) UseKeyRing(keys *KeyRing) {
+	m.keys = keys
+}
+
+func (m *TokenManager) GenerateAccessToken(userID, role, sessionID string, emailVerified, mfa bool) (string, time.Time, error) {
 	now := time.Now().UTC()
 	expiresAt := now.Add(m.accessTTL)
 
 	claims := AccessClaims{
-		Role:      role,
-		TokenType: TokenTypeAccess,
+		Role:          role,
+		TokenType:     TokenTypeAccess,
+		SessionID:     sessionID,
+		EmailVerified: emailVerified,
+		MFA:           mfa,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Subject:   userID,
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -59,7 +90,7 @@ func (m *TokenManager) GenerateAccessToken(userID, role string) (string, time.Ti
 		},
 	}
 
-	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.accessSecret)
+	token, err := m.signAccessToken(claims)
 	if err != nil {
 		return "", time.Time{}, fmt.Errorf("sign access token: %w", err)
 	}
@@ -67,6 +98,42 @@ func (m *TokenManager) GenerateAccessToken(userID, role string) (string, time.Ti
 	return token, expiresAt, nil
 }
 
+func (m *TokenManager) signAccessToken(claims AccessClaims) (string, error) {
+	if m.keys == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.accessSecret)
+	}
+
+	key, err := m.keys.Active()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(key.method(), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.PrivateKey)
+}
+
+// accessKey resolves the key an access token must verify against. Keys are
+// chosen by kid and the token's alg must match the key's, so a token cannot
+// pick its own algorithm.
+func (m *TokenManager) accessKey(token *jwt.Token) (any, error) {
+	if m.keys == nil {
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, ErrInvalidToken
+		}
+		return m.accessSecret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, ErrInvalidToken
+	}
+	key, ok := m.keys.Lookup(kid)
+	if !ok || token.Method.Alg() != key.method().Alg() {
+		return nil, ErrInvalidToken
+	}
+	return key.PrivateKey.Public(), nil
+}
+
 func (m *TokenManager) GenerateRefreshToken(userID string) (token string, tokenID string, expiresAt time.Time, err error) {
 	now := time.Now().UTC()
 	expiresAt = now.Add(m.refreshTTL)
@@ -90,8 +157,32 @@ func (m *TokenManager) GenerateRefreshToken(userID string) (token string, tokenI
 	return token, tokenID, expiresAt, nil
 }
 
-func (m *TokenManager) ParseAccessToken(token string) (*AccessClaims, error) {
-	parsedClaims := &AccessClaims{}
+// GenerateMFAChallengeToken signs a short-lived token that stands in for the
+// password step while the user enters their second factor.
+func (m *TokenManager) GenerateMFAChallengeToken(userID string) (string, time.Time, error) {
+	now := time.Now().UTC()
+	expiresAt := now.Add(MFAChallengeTTL)
+
+	claims := MFAChallengeClaims{
+		TokenType: TokenTypeMFA,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			ID:        generateTokenID(),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.accessSecret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("sign mfa challenge token: %w", err)
+	}
+
+	return token, expiresAt, nil
+}
+
+func (m *TokenManager) ParseMFAChallengeToken(token string) (*MFAChallengeClaims, error) {
+	parsedClaims := &MFAChallengeClaims{}
 	parsedToken, err := jwt.ParseWithClaims(token, parsedClaims, func(token *jwt.Token) (any, error) {
 		if token.Method != jwt.SigningMethodHS256 {
 			return nil, ErrInvalidToken
@@ -102,6 +193,20 @@ func (m *TokenManager) ParseAccessToken(token string) (*AccessClaims, error) {
 		return nil, ErrInvalidToken
 	}
 
+	if parsedClaims.TokenType != TokenTypeMFA || parsedClaims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+
+	return parsedClaims, nil
+}
+
+func (m *TokenManager) ParseAccessToken(token string) (*AccessClaims, error) {
+	parsedClaims := &AccessClaims{}
+	parsedToken, err := jwt.ParseWithClaims(token, parsedClaims, m.accessKey)
+	if err != nil || !parsedToken.Valid {
+		return nil, ErrInvalidToken
+	}
+
 	if parsedClaims.TokenType != TokenTypeAccess {
 		return nil, ErrInvalidToken
 	}
@@ -8,7 +8,7 @@ import (
 func TestTokenManagerAccessAndRefreshLifecycle(t *testing.T) {
 	m := NewTokenManager("access-secret", "refresh-secret", 15*time.Minute, 7*24*time.Hour)
 
-	access, accessExp, err := m.GenerateAccessToken("user-1", "author")
+	access, accessExp, err := m.GenerateAccessToken("user-1", "author", "session-1", true, true)
 	if err != nil {
 		t.Fatalf("expected access token generation to succeed: %v", err)
 	}
@@ -23,7 +23,7 @@ func TestTokenManagerAccessAndRefreshLifecycle(t *testing.T) {
 	if err != nil {
 		t.Fatalf("expected access token parsing to succeed: %v", err)
 	}
-	if accessClaims.Subject != "user-1" || accessClaims.Role != "author" {
+	if accessClaims.Subject != "user-1" || accessClaims.Role != "author" || accessClaims.SessionID != "session-1" || !accessClaims.EmailVerified || !accessClaims.MFA {
 		t.Fatalf("unexpected access token claims: %+v", accessClaims)
 	}
 
@@ -57,6 +57,34 @@ func TestTokenManagerRejectsWrongTokenType(t *testing.T) {
 	}
 }
 
+func TestMFAChallengeTokenIsNotAnAccessToken(t *testing.T) {
+	m := NewTokenManager("access-secret", "refresh-secret", 15*time.Minute, 7*24*time.Hour)
+
+	challenge, expiresAt, err := m.GenerateMFAChallengeToken("user-1")
+	if err != nil {
+		t.Fatalf("expected challenge token generation to succeed: %v", err)
+	}
+	if expiresAt.After(time.Now().UTC().Add(MFAChallengeTTL)) {
+		t.Fatalf("expected challenge token to expire within %s", MFAChallengeTTL)
+	}
+
+	claims, err := m.ParseMFAChallengeToken(challenge)
+	if err != nil || claims.Subject != "user-1" {
+		t.Fatalf("expected challenge token to parse, got %+v, %v", claims, err)
+	}
+	if _, err := m.ParseAccessToken(challenge); err == nil {
+		t.Fatalf("expected challenge token to fail access parsing")
+	}
+
+	access, _, err := m.GenerateAccessToken("user-1", "author", "session-1", true, false)
+	if err != nil {
+		t.Fatalf("expected access token generation to succeed: %v", err)
+	}
+	if _, err := m.ParseMFAChallengeToken(access); err == nil {
+		t.Fatalf("expected access token to fail challenge parsing")
+	}
+}
+
 func TestGenerateRandomToken(t *testing.T) {
 	token, err := GenerateRandomToken(24)
 	if err != nil {
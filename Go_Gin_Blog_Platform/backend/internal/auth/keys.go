@@ -0,0 +1,255 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Signing algorithms. HS256 signs with the shared JWT secrets; the others sign
+// access tokens with a key from a KeyRing.
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+const (
+	rsaKeyBits = 2048
+	// keyReloadBackoff bounds how often an unknown kid can force a reload, so
+	// forged kids cannot hammer the key store.
+	keyReloadBackoff = 10 * time.Second
+)
+
+var ErrNoActiveKey = errors.New("no active signing key")
+
+// SigningKey is one asymmetric key. Only the active key signs; the others
+// still verify tokens they signed before a rotation.
+type SigningKey struct {
+	ID         string
+	Algorithm  string
+	PrivateKey crypto.Signer
+	Active     bool
+}
+
+// GenerateSigningKey creates a key for alg (RS256 or EdDSA) with a random kid.
+func GenerateSigningKey(alg string) (*SigningKey, error) {
+	var private crypto.Signer
+	switch alg {
+	case AlgRS256:
+		key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, fmt.Errorf("generate rsa key: %w", err)
+		}
+		private = key
+	case AlgEdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("generate ed25519 key: %w", err)
+		}
+		private = key
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+
+	kid, err := GenerateRandomToken(12)
+	if err != nil {
+		return nil, err
+	}
+	return &SigningKey{ID: kid, Algorithm: alg, PrivateKey: private}, nil
+}
+
+// EncodePrivateKey returns the key as a PKCS #8 PEM block for storage.
+func (k *SigningKey) EncodePrivateKey() (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(k.PrivateKey)
+	if err != nil {
+		return "", fmt.Errorf("marshal signing key: %w", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
+
+// ParseSigningKey rebuilds a key stored with EncodePrivateKey, checking that
+// the key type matches alg.
+func ParseSigningKey(kid, alg, privatePEM string, active bool) (*SigningKey, error) {
+	block, _ := pem.Decode([]byte(privatePEM))
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s is not PEM encoded", kid)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse signing key %s: %w", kid, err)
+	}
+
+	var private crypto.Signer
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		if alg == AlgRS256 {
+			private = key
+		}
+	case ed25519.PrivateKey:
+		if alg == AlgEdDSA {
+			private = key
+		}
+	}
+	if private == nil {
+		return nil, fmt.Errorf("signing key %s does not match algorithm %s", kid, alg)
+	}
+	return &SigningKey{ID: kid, Algorithm: alg, PrivateKey: private, Active: active}, nil
+}
+
+func (k *SigningKey) method() jwt.SigningMethod {
+	if k.Algorithm == AlgEdDSA {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodRS256
+}
+
+// JWK is the public half of a signing key in RFC 7517 form.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+func (k *SigningKey) JWK() JWK {
+	jwk := JWK{Use: "sig", Algorithm: k.Algorithm, KeyID: k.ID}
+	switch public := k.PrivateKey.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+	return jwk
+}
+
+// KeyLoader returns every key that may still verify tokens, exactly one of
+// them active.
+type KeyLoader func(ctx context.Context) ([]SigningKey, error)
+
+// KeyRing holds the current signing keys in memory. Reload it periodically so
+// rotations made by other instances are picked up; a token with an unknown kid
+// also triggers a reload, since it may have been signed by a newer key.
+type KeyRing struct {
+	load KeyLoader
+	now  func() time.Time
+
+	mu         sync.RWMutex
+	active     *SigningKey
+	keys       map[string]*SigningKey
+	lastReload time.Time
+}
+
+func NewKeyRing(load KeyLoader) *KeyRing {
+	return &KeyRing{load: load, now: time.Now, keys: map[string]*SigningKey{}}
+}
+
+func (r *KeyRing) Reload(ctx context.Context) error {
+	loaded, err := r.load(ctx)
+	if err != nil {
+		return fmt.Errorf("load signing keys: %w", err)
+	}
+
+	keys := make(map[string]*SigningKey, len(loaded))
+	var active *SigningKey
+	for i := range loaded {
+		key := &loaded[i]
+		keys[key.ID] = key
+		if key.Active {
+			active = key
+		}
+	}
+	if active == nil {
+		return ErrNoActiveKey
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.active = active
+	r.keys = keys
+	r.lastReload = r.now()
+	return nil
+}
+
+func (r *KeyRing) Active() (*SigningKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.active == nil {
+		return nil, ErrNoActiveKey
+	}
+	return r.active, nil
+}
+
+// Lookup finds the key with the given kid, reloading once if it is unknown.
+func (r *KeyRing) Lookup(kid string) (*SigningKey, bool) {
+	r.mu.Lock()
+	key, ok := r.keys[kid]
+	reload := !ok && r.now().Sub(r.lastReload) >= keyReloadBackoff
+	if reload {
+		// Claim the reload so concurrent misses wait for the next window.
+		r.lastReload = r.now()
+	}
+	r.mu.Unlock()
+	if !reload {
+		return key, ok
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.Reload(ctx); err != nil {
+		return nil, false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	key, ok = r.keys[kid]
+	return key, ok
+}
+
+// JWKS returns the public keys of every key that still verifies tokens.
+func (r *KeyRing) JWKS() JWKSet {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	previous := make([]*SigningKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		if key != r.active {
+			previous = append(previous, key)
+		}
+	}
+	sort.Slice(previous, func(i, j int) bool { return previous[i].ID < previous[j].ID })
+
+	set := JWKSet{Keys: make([]JWK, 0, len(r.keys))}
+	if r.active != nil {
+		set.Keys = append(set.Keys, r.active.JWK())
+	}
+	for _, key := range previous {
+		set.Keys = append(set.Keys, key.JWK())
+	}
+	return set
+}
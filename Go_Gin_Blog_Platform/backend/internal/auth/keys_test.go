@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newTestKey(t *testing.T, alg string, active bool) SigningKey {
+	t.Helper()
+	key, err := GenerateSigningKey(alg)
+	if err != nil {
+		t.Fatalf("generate %s key: %v", alg, err)
+	}
+	key.Active = active
+	return *key
+}
+
+func staticKeys(keys ...SigningKey) KeyLoader {
+	return func(context.Context) ([]SigningKey, error) {
+		return append([]SigningKey(nil), keys...), nil
+	}
+}
+
+func TestKeyRingSignsAndVerifiesAcrossRotation(t *testing.T) {
+	for _, alg := range []string{AlgRS256, AlgEdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			old := newTestKey(t, alg, true)
+			ring := NewKeyRing(staticKeys(old))
+			if err := ring.Reload(context.Background()); err != nil {
+				t.Fatalf("reload: %v", err)
+			}
+			m := NewTokenManager("access-secret", "refresh-secret", 15*time.Minute, time.Hour)
+			m.UseKeyRing(ring)
+
+			before, _, err := m.GenerateAccessToken("user-1", "author", "s1", true, false)
+			if err != nil {
+				t.Fatalf("sign: %v", err)
+			}
+			parsed, _, err := jwt.NewParser().ParseUnverified(before, &AccessClaims{})
+			if err != nil || parsed.Header["kid"] != old.ID || parsed.Method.Alg() != alg {
+				t.Fatalf("expected a %s token with kid %s, got %v (%v)", alg, old.ID, parsed.Header, err)
+			}
+
+			old.Active = false
+			next := newTestKey(t, alg, true)
+			ring.load = staticKeys(next, old)
+			if err := ring.Reload(context.Background()); err != nil {
+				t.Fatalf("reload after rotation: %v", err)
+			}
+
+			after, _, err := m.GenerateAccessToken("user-1", "author", "s1", true, false)
+			if err != nil {
+				t.Fatalf("sign after rotation: %v", err)
+			}
+			for _, token := range []string{before, after} {
+				if _, err := m.ParseAccessToken(token); err != nil {
+					t.Fatalf("expected tokens from both keys to verify: %v", err)
+				}
+			}
+
+			ring.load = staticKeys(next)
+			if err := ring.Reload(context.Background()); err != nil {
+				t.Fatalf("reload after retirement: %v", err)
+			}
+			if _, err := m.ParseAccessToken(before); err == nil {
+				t.Fatalf("expected a token signed by a retired key to be rejected")
+			}
+		})
+	}
+}
+
+func TestKeyRingRejectsSymmetricAndMismatchedTokens(t *testing.T) {
+	ring := NewKeyRing(staticKeys(newTestKey(t, AlgRS256, true)))
+	if err := ring.Reload(context.Background()); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	hs := NewTokenManager("access-secret", "refresh-secret", 15*time.Minute, time.Hour)
+	legacy, _, err := hs.GenerateAccessToken("user-1", "admin", "s1", true, true)
+	if err != nil {
+		t.Fatalf("sign hs256: %v", err)
+	}
+
+	m := NewTokenManager("access-secret", "refresh-secret", 15*time.Minute, time.Hour)
+	m.UseKeyRing(ring)
+	if _, err := m.ParseAccessToken(legacy); err == nil {
+		t.Fatalf("expected an HS256 token to be rejected in key ring mode")
+	}
+
+	active, _ := ring.Active()
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, AccessClaims{Role: "admin", TokenType: TokenTypeAccess, RegisteredClaims: jwt.RegisteredClaims{Subject: "user-1"}})
+	forged.Header["kid"] = active.ID
+	signed, err := forged.SignedString([]byte("access-secret"))
+	if err != nil {
+		t.Fatalf("sign forged token: %v", err)
+	}
+	if _, err := m.ParseAccessToken(signed); err == nil {
+		t.Fatalf("expected a token whose alg does not match its key to be rejected")
+	}
+}
+
+func TestKeyRingReloadsOnUnknownKid(t *testing.T) {
+	first := newTestKey(t, AlgEdDSA, true)
+	ring := NewKeyRing(staticKeys(first))
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ring.now = func() time.Time { return clock }
+	if err := ring.Reload(context.Background()); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+
+	first.Active = false
+	second := newTestKey(t, AlgEdDSA, true)
+	ring.load = staticKeys(second, first)
+
+	if _, ok := ring.Lookup(second.ID); ok {
+		t.Fatalf("expected no reload within the backoff window")
+	}
+	clock = clock.Add(keyReloadBackoff)
+	if _, ok := ring.Lookup(second.ID); !ok {
+		t.Fatalf("expected an unknown kid to trigger a reload")
+	}
+}
+
+func TestKeyRingRequiresActiveKey(t *testing.T) {
+	ring := NewKeyRing(staticKeys(newTestKey(t, AlgRS256, false)))
+	if err := ring.Reload(context.Background()); !errors.Is(err, ErrNoActiveKey) {
+		t.Fatalf("expected ErrNoActiveKey, got %v", err)
+	}
+}
+
+func TestSigningKeyRoundTripAndJWK(t *testing.T) {
+	for _, alg := range []string{AlgRS256, AlgEdDSA} {
+		key := newTestKey(t, alg, true)
+		encoded, err := key.EncodePrivateKey()
+		if err != nil {
+			t.Fatalf("encode %s: %v", alg, err)
+		}
+		parsed, err := ParseSigningKey(key.ID, alg, encoded, true)
+		if err != nil {
+			t.Fatalf("parse %s: %v", alg, err)
+		}
+		if parsed.JWK() != key.JWK() {
+			t.Fatalf("expected %s key to survive encoding", alg)
+		}
+
+		other := AlgEdDSA
+		if alg == AlgEdDSA {
+			other = AlgRS256
+		}
+		if _, err := ParseSigningKey(key.ID, other, encoded, true); err == nil {
+			t.Fatalf("expected a %s key stored as %s to be rejected", alg, other)
+		}
+	}
+
+	rsaKey := newTestKey(t, AlgRS256, true)
+	rsaJWK := rsaKey.JWK()
+	if rsaJWK.KeyType != "RSA" || rsaJWK.E != "AQAB" || rsaJWK.N == "" || rsaJWK.Use != "sig" {
+		t.Fatalf("unexpected RSA JWK %+v", rsaJWK)
+	}
+	edKey := newTestKey(t, AlgEdDSA, true)
+	edJWK := edKey.JWK()
+	if edJWK.KeyType != "OKP" || edJWK.Curve != "Ed25519" || edJWK.X == "" || edJWK.Algorithm != AlgEdDSA {
+		t.Fatalf("unexpected Ed25519 JWK %+v", edJWK)
+	}
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 seed from RFC 6238 appendix B, base32 encoded.
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPCodeMatchesRFC6238Vectors(t *testing.T) {
+	// The RFC lists 8 digit codes; a 6 digit code is their last six digits.
+	cases := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tc := range cases {
+		got, err := TOTPCode(rfc6238Secret, TOTPStep(time.Unix(tc.unix, 0)))
+		if err != nil {
+			t.Fatalf("TOTPCode(%d): %v", tc.unix, err)
+		}
+		if got != tc.want {
+			t.Fatalf("TOTPCode(%d) = %s, want %s", tc.unix, got, tc.want)
+		}
+	}
+}
+
+func TestValidateTOTPAllowsOneStepOfDrift(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := TOTPStep(now)
+
+	previous, _ := TOTPCode(rfc6238Secret, step-1)
+	if matched, ok := ValidateTOTP(rfc6238Secret, previous, now); !ok || matched != step-1 {
+		t.Fatalf("expected previous step code to validate, got %d, %v", matched, ok)
+	}
+
+	stale, _ := TOTPCode(rfc6238Secret, step-2)
+	if _, ok := ValidateTOTP(rfc6238Secret, stale, now); ok {
+		t.Fatalf("expected code two steps old to be rejected")
+	}
+
+	if _, ok := ValidateTOTP(rfc6238Secret, "12345", now); ok {
+		t.Fatalf("expected short code to be rejected")
+	}
+}
+
+func TestGenerateTOTPSecretAndURI(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateTOTPSecret: %v", err)
+	}
+	if _, err := TOTPCode(secret, 1); err != nil {
+		t.Fatalf("expected generated secret to decode: %v", err)
+	}
+
+	uri, err := url.Parse(TOTPURI("Blog", "alice@example.com", secret))
+	if err != nil {
+		t.Fatalf("parse otpauth uri: %v", err)
+	}
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/Blog:alice@example.com" {
+		t.Fatalf("unexpected otpauth uri: %s", uri)
+	}
+	if uri.Query().Get("secret") != secret || uri.Query().Get("issuer") != "Blog" {
+		t.Fatalf("unexpected otpauth query: %s", uri.RawQuery)
+	}
+}
+
+func TestRecoveryCodeRoundTrip(t *testing.T) {
+	code, err := GenerateRecoveryCode()
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCode: %v", err)
+	}
+	if len(code) != 11 || code[5] != '-' {
+		t.Fatalf("unexpected recovery code format: %q", code)
+	}
+
+	typed := " " + strings.ToUpper(strings.ReplaceAll(code, "-", "")) + " "
+	if NormalizeRecoveryCode(typed) != code {
+		t.Fatalf("expected %q to normalize to %q", typed, code)
+	}
+}
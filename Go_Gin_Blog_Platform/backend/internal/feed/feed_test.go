@@ -0,0 +1,119 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func sampleFeed() Feed {
+	published := time.Date(2026, 2, 3, 9, 30, 0, 0, time.UTC)
+	return Feed{
+		Title:       "Example Blog",
+		Description: "Latest posts from Example Blog",
+		SiteURL:     "https://blog.example.com",
+		FeedURL:     "https://blog.example.com/api/v1/feeds/rss.xml",
+		Updated:     published.Add(2 * time.Hour),
+		Items: []Item{
+			{
+				ID:          "7b0f7c1e-4a59-4d55-9d3c-1f2a3b4c5d6e",
+				Title:       "Tables & <code> in posts",
+				URL:         "https://blog.example.com/posts/tables-code-in-posts",
+				ContentHTML: "<p>Use <code>|</code> for tables &amp; more.</p>\n",
+				Published:   published,
+				Updated:     published.Add(2 * time.Hour),
+				Tags:        []string{"go", "markdown"},
+			},
+			{
+				ID:          "0c9d8e7f-6a5b-4c3d-2e1f-0a9b8c7d6e5f",
+				Title:       "Hello world",
+				URL:         "https://blog.example.com/posts/hello-world",
+				ContentHTML: "<p>First post.</p>\n",
+				Published:   published.Add(-24 * time.Hour),
+				Updated:     published.Add(-24 * time.Hour),
+			},
+		},
+	}
+}
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("write golden file: %v", err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("%s does not match golden file\n--- got\n%s\n--- want\n%s", name, got, want)
+	}
+}
+
+func assertWellFormedXML(t *testing.T, body []byte) {
+	t.Helper()
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		if _, err := dec.Token(); err != nil {
+			if err == io.EOF {
+				return
+			}
+			t.Fatalf("feed is not well-formed XML: %v", err)
+		}
+	}
+}
+
+func TestRSSMatchesGolden(t *testing.T) {
+	body, err := RSS(sampleFeed())
+	if err != nil {
+		t.Fatalf("render rss: %v", err)
+	}
+	assertWellFormedXML(t, body)
+	assertGolden(t, "rss.xml.golden", body)
+}
+
+func TestAtomMatchesGolden(t *testing.T) {
+	f := sampleFeed()
+	f.FeedURL = "https://blog.example.com/api/v1/feeds/atom.xml"
+	body, err := Atom(f)
+	if err != nil {
+		t.Fatalf("render atom: %v", err)
+	}
+	assertWellFormedXML(t, body)
+	assertGolden(t, "atom.xml.golden", body)
+}
+
+func TestJSONMatchesGolden(t *testing.T) {
+	f := sampleFeed()
+	f.FeedURL = "https://blog.example.com/api/v1/feeds/feed.json"
+	body, err := JSON(f)
+	if err != nil {
+		t.Fatalf("render json feed: %v", err)
+	}
+	if !json.Valid(body) {
+		t.Fatalf("json feed is not valid JSON")
+	}
+	assertGolden(t, "feed.json.golden", body)
+}
+
+func TestEmptyFeedsRender(t *testing.T) {
+	f := Feed{Title: "Empty", SiteURL: "https://blog.example.com", FeedURL: "https://blog.example.com/feed"}
+	for name, render := range map[string]func(Feed) ([]byte, error){"rss": RSS, "atom": Atom} {
+		body, err := render(f)
+		if err != nil {
+			t.Fatalf("render empty %s: %v", name, err)
+		}
+		assertWellFormedXML(t, body)
+	}
+}
@@ -1,55 +1,313 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Role string
 
 type PostStatus string
 
 const (
-	RoleAdmin  Role = "admin"
-	RoleAuthor Role = "author"
-	RoleReader Role = "reader"
+	RoleAdmin       Role = "admin"
+	RoleEditor      Role = "editor"
+	RoleAuthor      Role = "author"
+	RoleContributor Role = "contributor"
+	RoleReader      Role = "reader"
 )
 
 const (
 	PostStatusDraft     PostStatus = "draft"
+	PostStatusInReview  PostStatus = "in_review"
+	PostStatusApproved  PostStatus = "approved"
 	PostStatusPublished PostStatus = "published"
+	PostStatusScheduled PostStatus = "scheduled"
+)
+
+type CommentStatus string
+
+const (
+	CommentStatusPending  CommentStatus = "pending"
+	CommentStatusApproved CommentStatus = "approved"
+	CommentStatusSpam     CommentStatus = "spam"
+	CommentStatusRejected CommentStatus = "rejected"
+)
+
+type SigningKeyStatus string
+
+const (
+	SigningKeyActive   SigningKeyStatus = "active"
+	SigningKeyPrevious SigningKeyStatus = "previous"
+	SigningKeyRetired  SigningKeyStatus = "retired"
 )
 
 type User struct {
-	ID           string    `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	Email        string    `gorm:"uniqueIndex;not null"`
-	PasswordHash string    `gorm:"not null"`
-	Role         Role      `gorm:"type:text;not null"`
-	CreatedAt    time.Time `gorm:"not null;default:now()"`
-	UpdatedAt    time.Time `gorm:"not null;default:now()"`
+	ID              string `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Email           string `gorm:"uniqueIndex;not null"`
+	PasswordHash    string `gorm:"not null"`
+	Role            Role   `gorm:"type:text;not null"`
+	EmailVerifiedAt *time.Time
+	CreatedAt       time.Time `gorm:"not null;default:now()"`
+	UpdatedAt       time.Time `gorm:"not null;default:now()"`
+}
+
+// RoleDefinition is a role users can be given and the permissions it grants.
+// Built-in roles can be edited but not deleted.
+type RoleDefinition struct {
+	Name        string           `gorm:"primaryKey"`
+	BuiltIn     bool             `gorm:"not null;default:false"`
+	CreatedAt   time.Time        `gorm:"not null;default:now()"`
+	Permissions []RolePermission `gorm:"foreignKey:Role;references:Name"`
+}
+
+func (RoleDefinition) TableName() string {
+	return "roles"
+}
+
+type RolePermission struct {
+	Role       string `gorm:"primaryKey"`
+	Permission string `gorm:"primaryKey"`
 }
 
 type Post struct {
+	ID          string     `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	AuthorID    string     `gorm:"type:uuid;not null;index"`
+	Title       string     `gorm:"not null"`
+	Slug        string     `gorm:"uniqueIndex;not null"`
+	Content     string     `gorm:"not null"`
+	ContentHTML string     `gorm:"column:content_html;not null;default:''"`
+	Status      PostStatus `gorm:"type:text;not null;default:published"`
+	PublishAt   *time.Time
+	SubmittedAt *time.Time
+	CategoryID  *string   `gorm:"type:uuid;index"`
+	Category    *Category `gorm:"foreignKey:CategoryID"`
+	ReviewerID  *string   `gorm:"type:uuid;index"`
+	Tags        []Tag     `gorm:"many2many:post_tags"`
+	CreatedAt   time.Time `gorm:"not null;default:now()"`
+	UpdatedAt   time.Time `gorm:"not null;default:now()"`
+	DeletedAt   gorm.DeletedAt
+}
+
+// PostReviewEvent records one step of a post through the editorial workflow,
+// with the comment the reviewer left on it.
+type PostReviewEvent struct {
+	ID         string     `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	PostID     string     `gorm:"type:uuid;not null;index"`
+	ActorID    *string    `gorm:"type:uuid"`
+	Action     string     `gorm:"not null"`
+	FromStatus PostStatus `gorm:"type:text;not null"`
+	ToStatus   PostStatus `gorm:"type:text;not null"`
+	ReviewerID *string    `gorm:"type:uuid"`
+	Comment    string     `gorm:"not null;default:''"`
+	CreatedAt  time.Time  `gorm:"not null;default:now()"`
+}
+
+type PostRevision struct {
 	ID        string     `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	AuthorID  string     `gorm:"type:uuid;not null;index"`
+	PostID    string     `gorm:"type:uuid;not null;index"`
+	Revision  int        `gorm:"not null"`
+	EditorID  *string    `gorm:"type:uuid"`
 	Title     string     `gorm:"not null"`
 	Content   string     `gorm:"not null"`
-	Status    PostStatus `gorm:"type:text;not null;default:published"`
+	Status    PostStatus `gorm:"type:text;not null"`
 	CreatedAt time.Time  `gorm:"not null;default:now()"`
-	UpdatedAt time.Time  `gorm:"not null;default:now()"`
 }
 
+type Tag struct {
+	ID        string    `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Name      string    `gorm:"not null"`
+	Slug      string    `gorm:"uniqueIndex;not null"`
+	CreatedAt time.Time `gorm:"not null;default:now()"`
+}
+
+type Category struct {
+	ID        string    `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	ParentID  *string   `gorm:"type:uuid;index"`
+	Name      string    `gorm:"not null"`
+	Slug      string    `gorm:"uniqueIndex;not null"`
+	CreatedAt time.Time `gorm:"not null;default:now()"`
+}
+
+type PostSlugHistory struct {
+	ID        string    `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	PostID    string    `gorm:"type:uuid;not null;index"`
+	Slug      string    `gorm:"uniqueIndex;not null"`
+	CreatedAt time.Time `gorm:"not null;default:now()"`
+}
+
+func (PostSlugHistory) TableName() string {
+	return "post_slug_history"
+}
+
+// RefreshToken is one link in a rotation chain. Every token rotated from the
+// same login shares a FamilyID, so a replayed token can revoke the whole chain.
+// A family is what users see as a session; its live token records the client
+// that last refreshed it.
 type RefreshToken struct {
+	ID         string    `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID     string    `gorm:"type:uuid;not null;index"`
+	FamilyID   string    `gorm:"type:uuid;not null;default:gen_random_uuid();index"`
+	TokenHash  string    `gorm:"not null"`
+	UserAgent  string    `gorm:"not null;default:''"`
+	IPAddress  string    `gorm:"not null;default:''"`
+	LastUsedAt time.Time `gorm:"not null;default:now()"`
+	// MFA records that the session was signed in with a second factor; it is
+	// carried across rotations.
+	MFA       bool       `gorm:"not null;default:false"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	RevokedAt *time.Time `gorm:"index"`
+	CreatedAt time.Time  `gorm:"not null;default:now()"`
+}
+
+type PasswordResetToken struct {
 	ID        string     `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	UserID    string     `gorm:"type:uuid;not null;index"`
 	TokenHash string     `gorm:"not null"`
 	ExpiresAt time.Time  `gorm:"not null"`
-	RevokedAt *time.Time `gorm:"index"`
+	UsedAt    *time.Time `gorm:"index"`
 	CreatedAt time.Time  `gorm:"not null;default:now()"`
 }
 
-type PasswordResetToken struct {
+type EmailVerificationToken struct {
+	ID        string     `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID    string     `gorm:"type:uuid;not null;index"`
+	TokenHash string     `gorm:"not null"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time `gorm:"index"`
+	CreatedAt time.Time  `gorm:"not null;default:now()"`
+}
+
+// EmailChangeToken confirms that the user controls NewEmail before it replaces
+// their current address.
+type EmailChangeToken struct {
 	ID        string     `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	UserID    string     `gorm:"type:uuid;not null;index"`
+	NewEmail  string     `gorm:"not null"`
 	TokenHash string     `gorm:"not null"`
 	ExpiresAt time.Time  `gorm:"not null"`
 	UsedAt    *time.Time `gorm:"index"`
 	CreatedAt time.Time  `gorm:"not null;default:now()"`
 }
+
+// TOTPCredential holds a user's authenticator secret. It is pending until
+// EnabledAt is set by confirming a first code.
+type TOTPCredential struct {
+	UserID    string `gorm:"type:uuid;primaryKey"`
+	Secret    string `gorm:"not null"`
+	EnabledAt *time.Time
+	// LastUsedStep is the time step of the last accepted code, so a code
+	// cannot be replayed within its validity window.
+	LastUsedStep int64     `gorm:"not null;default:0"`
+	CreatedAt    time.Time `gorm:"not null;default:now()"`
+	UpdatedAt    time.Time `gorm:"not null;default:now()"`
+}
+
+type RecoveryCode struct {
+	ID        string     `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID    string     `gorm:"type:uuid;not null;index"`
+	CodeHash  string     `gorm:"not null"`
+	UsedAt    *time.Time `gorm:"index"`
+	CreatedAt time.Time  `gorm:"not null;default:now()"`
+}
+
+// Identity links a user to their account at an external OpenID provider.
+// Subject is the provider's stable id for that account; Email is what the
+// provider reported when the link was made.
+type Identity struct {
+	ID          string    `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID      string    `gorm:"type:uuid;not null;index"`
+	Provider    string    `gorm:"not null"`
+	Subject     string    `gorm:"not null"`
+	Email       string    `gorm:"not null;default:''"`
+	CreatedAt   time.Time `gorm:"not null;default:now()"`
+	LastLoginAt *time.Time
+}
+
+// OIDCLoginState is a provider sign-in in progress, keyed by the hash of the
+// state parameter. It keeps the nonce and PKCE verifier until the callback.
+type OIDCLoginState struct {
+	StateHash    string    `gorm:"primaryKey"`
+	Provider     string    `gorm:"not null"`
+	Nonce        string    `gorm:"not null"`
+	CodeVerifier string    `gorm:"not null"`
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time `gorm:"not null;default:now()"`
+}
+
+func (OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
+
+// PersonalAccessToken is a long-lived credential a user mints for scripts and
+// CI. Only the hash of the token is stored; Prefix is kept so users can tell
+// their tokens apart. Scopes is a space separated list.
+type PersonalAccessToken struct {
+	ID        string `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID    string `gorm:"type:uuid;not null;index"`
+	Name      string `gorm:"not null"`
+	TokenHash string `gorm:"uniqueIndex;not null"`
+	Prefix    string `gorm:"not null"`
+	Scopes    string `gorm:"not null;default:''"`
+	// MFA records that the token was minted from a session signed in with a
+	// second factor.
+	MFA        bool      `gorm:"not null;default:false"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time `gorm:"not null;default:now()"`
+}
+
+type Comment struct {
+	ID        string        `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	PostID    string        `gorm:"type:uuid;not null;index"`
+	ParentID  *string       `gorm:"type:uuid;index"`
+	AuthorID  string        `gorm:"type:uuid;not null"`
+	Body      string        `gorm:"not null"`
+	Status    CommentStatus `gorm:"type:text;not null;default:pending"`
+	CreatedAt time.Time     `gorm:"not null;default:now()"`
+	UpdatedAt time.Time     `gorm:"not null;default:now()"`
+	// DeletedAt marks a tombstone: a deleted comment kept so its replies stay
+	// threaded. It is deliberately not gorm.DeletedAt, which would hide it.
+	DeletedAt *time.Time
+}
+
+// SigningKey is an asymmetric access token key, identified by its kid. A
+// rotation demotes the active key to previous, where it still verifies tokens,
+// and retiring it removes it from the key set.
+type SigningKey struct {
+	ID         string           `gorm:"primaryKey"`
+	Algorithm  string           `gorm:"not null"`
+	PrivateKey string           `gorm:"not null"`
+	Status     SigningKeyStatus `gorm:"type:text;not null;default:active"`
+	CreatedAt  time.Time        `gorm:"not null;default:now()"`
+	RotatedAt  *time.Time
+	RetiredAt  *time.Time
+}
+
+// LoginThrottle tracks failed sign-ins for one subject: a user ("user:<id>") or
+// a client IP ("ip:<addr>").
+type LoginThrottle struct {
+	Key           string `gorm:"primaryKey"`
+	Failures      int    `gorm:"not null;default:0"`
+	Lockouts      int    `gorm:"not null;default:0"`
+	LockedUntil   *time.Time
+	LastFailureAt time.Time `gorm:"not null;default:now()"`
+}
+
+// AuditEvent is one entry in the append-only audit log: who did what to which
+// record, with the record's state before and after as JSON.
+type AuditEvent struct {
+	ID         string          `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	ActorID    *string         `gorm:"type:uuid"`
+	Action     string          `gorm:"not null"`
+	TargetType string          `gorm:"not null"`
+	TargetID   string          `gorm:"not null"`
+	Before     json.RawMessage `gorm:"type:jsonb"`
+	After      json.RawMessage `gorm:"type:jsonb"`
+	IPAddress  string          `gorm:"not null;default:''"`
+	RequestID  string          `gorm:"not null;default:''"`
+	CreatedAt  time.Time       `gorm:"not null;default:now()"`
+}
@@ -0,0 +1,131 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/oidc/oidctest"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const redirectURL = "https://blog.example.com/auth/oidc/test/callback"
+
+func newProvider(t *testing.T) (*Provider, *oidctest.Server) {
+	t.Helper()
+	server := oidctest.NewServer("blog-client", "blog-secret")
+	t.Cleanup(server.Close)
+	server.SetUser(oidctest.User{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true, Name: "Alice"})
+
+	provider := NewProvider(Config{
+		Name:         "test",
+		Issuer:       server.Issuer(),
+		ClientID:     "blog-client",
+		ClientSecret: "blog-secret",
+		RedirectURL:  redirectURL,
+		Scopes:       []string{"email", "profile"},
+	}, server.Client())
+	return provider, server
+}
+
+// startFlow sends the browser to the provider and returns the code it came
+// back with, plus the verifier the client kept.
+func startFlow(t *testing.T, provider *Provider, server *oidctest.Server, nonce string) (code, verifier string) {
+	t.Helper()
+	verifier, err := NewCodeVerifier()
+	if err != nil {
+		t.Fatalf("code verifier: %v", err)
+	}
+	authURL, err := provider.AuthCodeURL(context.Background(), "state-1", nonce, CodeChallenge(verifier))
+	if err != nil {
+		t.Fatalf("auth code url: %v", err)
+	}
+	parsed, _ := url.Parse(authURL)
+	if scope := parsed.Query().Get("scope"); scope != "openid email profile" {
+		t.Fatalf("expected openid to be requested, got scope %q", scope)
+	}
+
+	code, state, err := server.Authorize(authURL)
+	if err != nil || state != "state-1" {
+		t.Fatalf("authorize: state %q, %v", state, err)
+	}
+	return code, verifier
+}
+
+func TestProviderAuthenticatesWithPKCE(t *testing.T) {
+	provider, server := newProvider(t)
+	code, verifier := startFlow(t, provider, server, "nonce-1")
+
+	identity, err := provider.Authenticate(context.Background(), code, verifier, "nonce-1")
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	want := Identity{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true, Name: "Alice"}
+	if identity != want {
+		t.Fatalf("unexpected identity %+v", identity)
+	}
+
+	if _, err := provider.Authenticate(context.Background(), code, verifier, "nonce-1"); !errors.Is(err, ErrExchangeFailed) {
+		t.Fatalf("expected a used code to be refused, got %v", err)
+	}
+}
+
+func TestProviderRejectsBadExchangesAndTokens(t *testing.T) {
+	cases := map[string]struct {
+		claims        func(jwt.MapClaims)
+		wrongVerifier bool
+		nonce         string
+		want          error
+	}{
+		"wrong verifier":  {wrongVerifier: true, want: ErrExchangeFailed},
+		"nonce mismatch":  {nonce: "other-nonce", want: ErrInvalidIDToken},
+		"other audience":  {claims: func(c jwt.MapClaims) { c["aud"] = "someone-else" }, want: ErrInvalidIDToken},
+		"other issuer":    {claims: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }, want: ErrInvalidIDToken},
+		"expired":         {claims: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }, want: ErrInvalidIDToken},
+		"foreign azp":     {claims: func(c jwt.MapClaims) { c["aud"] = []string{"blog-client", "other"}; c["azp"] = "other" }, want: ErrInvalidIDToken},
+		"missing subject": {claims: func(c jwt.MapClaims) { delete(c, "sub") }, want: ErrInvalidIDToken},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			provider, server := newProvider(t)
+			server.Claims = tc.claims
+			code, verifier := startFlow(t, provider, server, "nonce-1")
+			if tc.wrongVerifier {
+				verifier, _ = NewCodeVerifier()
+			}
+			nonce := "nonce-1"
+			if tc.nonce != "" {
+				nonce = tc.nonce
+			}
+
+			if _, err := provider.Authenticate(context.Background(), code, verifier, nonce); !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestProviderRefetchesKeysAfterRotation(t *testing.T) {
+	provider, server := newProvider(t)
+	clock := time.Now()
+	provider.now = func() time.Time { return clock }
+
+	code, verifier := startFlow(t, provider, server, "n1")
+	if _, err := provider.Authenticate(context.Background(), code, verifier, "n1"); err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+
+	server.RotateKey()
+	code, verifier = startFlow(t, provider, server, "n2")
+	if _, err := provider.Authenticate(context.Background(), code, verifier, "n2"); !errors.Is(err, ErrInvalidIDToken) {
+		t.Fatalf("expected the new key to stay unknown within the refetch backoff, got %v", err)
+	}
+
+	clock = clock.Add(keyRefreshBackoff)
+	code, verifier = startFlow(t, provider, server, "n3")
+	if _, err := provider.Authenticate(context.Background(), code, verifier, "n3"); err != nil {
+		t.Fatalf("expected an unknown kid to refetch the provider keys: %v", err)
+	}
+}
@@ -0,0 +1,208 @@
+// Package policy decides what users may do. Roles hold named permissions, and
+// every authorization decision goes through Policy.Authorize.
+package policy
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+type Permission string
+
+const (
+	PostCreate      Permission = "post.create"
+	PostPublish     Permission = "post.publish"
+	PostEditAny     Permission = "post.edit.any"
+	PostReadAny     Permission = "post.read.any"
+	PostReview      Permission = "post.review"
+	PostPurge       Permission = "post.purge"
+	CommentModerate Permission = "comment.moderate"
+	TaxonomyManage  Permission = "taxonomy.manage"
+	UserManage      Permission = "user.manage"
+	UserRoleAssign  Permission = "user.role.assign"
+	RoleManage      Permission = "role.manage"
+	AuditRead       Permission = "audit.read"
+)
+
+// SuperuserRole holds every permission, whatever its stored grants say, so
+// that editing roles can never lock every admin out.
+const SuperuserRole = "admin"
+
+var all = []Permission{
+	PostCreate,
+	PostPublish,
+	PostEditAny,
+	PostReadAny,
+	PostReview,
+	PostPurge,
+	CommentModerate,
+	TaxonomyManage,
+	UserManage,
+	UserRoleAssign,
+	RoleManage,
+	AuditRead,
+}
+
+// ownerFallback gives, for permissions over other users' resources, what an
+// actor needs instead when the resource is their own; "" means owning it is
+// enough.
+var ownerFallback = map[Permission]Permission{
+	PostEditAny:     PostCreate,
+	PostReadAny:     "",
+	CommentModerate: "",
+}
+
+// Actor is who is asking.
+type Actor struct {
+	ID   string
+	Role string
+}
+
+// Resource is what an action is taken on.
+type Resource struct {
+	OwnerID string
+}
+
+// Grants maps role names to the permissions they hold.
+type Grants map[string][]Permission
+
+// All returns every known permission.
+func All() []Permission {
+	return append([]Permission(nil), all...)
+}
+
+func IsKnown(permission Permission) bool {
+	for _, known := range all {
+		if permission == known {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultGrants is the built-in role mapping, which the roles migration also
+// seeds.
+func DefaultGrants() Grants {
+	return Grants{
+		SuperuserRole: All(),
+		"editor":      {PostCreate, PostPublish, PostEditAny, PostReadAny, PostReview, CommentModerate, TaxonomyManage},
+		"author":      {PostCreate, PostPublish},
+		"contributor": {PostCreate},
+		"reader":      {},
+	}
+}
+
+// AdminPermissions are the permissions over other accounts, roles and the audit
+// trail. Whichever roles hold one of them are administrative, whatever they
+// are called.
+func AdminPermissions() []Permission {
+	return []Permission{UserManage, UserRoleAssign, RoleManage, AuditRead}
+}
+
+// BuiltInRoles lists the roles in DefaultGrants, which cannot be deleted.
+func BuiltInRoles() []string {
+	roles := make([]string, 0, len(DefaultGrants()))
+	for role := range DefaultGrants() {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// Policy holds the current grants in memory. It is safe for concurrent use;
+// Replace swaps in new grants when roles are edited.
+type Policy struct {
+	mu    sync.RWMutex
+	roles map[string]map[Permission]struct{}
+}
+
+func New(grants Grants) *Policy {
+	p := &Policy{}
+	p.Replace(grants)
+	return p
+}
+
+func (p *Policy) Replace(grants Grants) {
+	roles := make(map[string]map[Permission]struct{}, len(grants)+1)
+	for role, permissions := range grants {
+		set := make(map[Permission]struct{}, len(permissions))
+		for _, permission := range permissions {
+			set[permission] = struct{}{}
+		}
+		roles[normalizeRole(role)] = set
+	}
+	roles[SuperuserRole] = make(map[Permission]struct{}, len(all))
+	for _, permission := range all {
+		roles[SuperuserRole][permission] = struct{}{}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.roles = roles
+}
+
+// Authorize reports whether actor may take action on resource. resource may be
+// nil for actions that are not about one resource; when the actor owns it,
+// some permissions fall back to a weaker one, such as post.edit.any to
+// post.create.
+func (p *Policy) Authorize(actor Actor, action Permission, resource *Resource) bool {
+	if p.has(actor.Role, action) {
+		return true
+	}
+	if resource == nil || !owns(actor, *resource) {
+		return false
+	}
+	fallback, ok := ownerFallback[action]
+	if !ok {
+		return false
+	}
+	return fallback == "" || p.has(actor.Role, fallback)
+}
+
+func (p *Policy) RoleExists(role string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	_, ok := p.roles[normalizeRole(role)]
+	return ok
+}
+
+// Covers reports whether role holds every permission other does, which is
+// what it takes to hand out other without gaining anything.
+func (p *Policy) Covers(role, other string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	held := p.roles[normalizeRole(role)]
+	for permission := range p.roles[normalizeRole(other)] {
+		if _, ok := held[permission]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Holds reports whether role holds every one of permissions.
+func (p *Policy) Holds(role string, permissions ...Permission) bool {
+	for _, permission := range permissions {
+		if !p.has(role, permission) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Policy) has(role string, permission Permission) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	_, ok := p.roles[normalizeRole(role)][permission]
+	return ok
+}
+
+func owns(actor Actor, resource Resource) bool {
+	id := strings.TrimSpace(actor.ID)
+	return id != "" && id == strings.TrimSpace(resource.OwnerID)
+}
+
+func normalizeRole(role string) string {
+	return strings.ToLower(strings.TrimSpace(role))
+}
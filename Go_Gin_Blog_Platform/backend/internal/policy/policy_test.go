@@ -0,0 +1,59 @@
+package policy
+
+import "testing"
+
+func TestAuthorizeUsesGrantsAndOwnership(t *testing.T) {
+	p := New(DefaultGrants())
+	own := &Resource{OwnerID: "u1"}
+	other := &Resource{OwnerID: "u2"}
+
+	cases := []struct {
+		role     string
+		action   Permission
+		resource *Resource
+		want     bool
+	}{
+		{"contributor", PostCreate, nil, true},
+		{"contributor", PostPublish, nil, false},
+		{"contributor", PostEditAny, own, true},
+		{"contributor", PostEditAny, other, false},
+		{"reader", PostEditAny, own, false},
+		{"reader", PostReadAny, own, true},
+		{"reader", CommentModerate, own, true},
+		{"author", CommentModerate, other, false},
+		{"Editor", PostEditAny, other, true},
+		{"editor", UserRoleAssign, nil, false},
+		{"admin", RoleManage, nil, true},
+		{"unknown", PostCreate, nil, false},
+	}
+	for _, tc := range cases {
+		if got := p.Authorize(Actor{ID: "u1", Role: tc.role}, tc.action, tc.resource); got != tc.want {
+			t.Fatalf("%s %s on %+v: expected %v, got %v", tc.role, tc.action, tc.resource, tc.want, got)
+		}
+	}
+
+	if p.Authorize(Actor{Role: "reader"}, PostReadAny, &Resource{}) {
+		t.Fatalf("expected an anonymous actor not to own an unowned resource")
+	}
+}
+
+func TestReplaceKeepsSuperuser(t *testing.T) {
+	p := New(DefaultGrants())
+	p.Replace(Grants{"author": {PostCreate}, "admin": {}})
+
+	if p.Authorize(Actor{Role: "author"}, PostPublish, nil) {
+		t.Fatalf("expected replaced grants to take effect")
+	}
+	if p.RoleExists("editor") || !p.RoleExists("author") {
+		t.Fatalf("expected roles to follow the replaced grants")
+	}
+	if !p.Authorize(Actor{Role: "admin"}, RoleManage, nil) || !p.Covers("admin", "author") {
+		t.Fatalf("expected admin to keep every permission")
+	}
+	if p.Covers("author", "admin") {
+		t.Fatalf("expected author not to cover admin")
+	}
+	if !p.Holds("author", PostCreate) || p.Holds("author", PostCreate, RoleManage) {
+		t.Fatalf("expected Holds to need every permission")
+	}
+}
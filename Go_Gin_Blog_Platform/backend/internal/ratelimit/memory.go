@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryBucket struct {
+	tokens  float64
+	updated time.Time
+	window  time.Duration
+}
+
+// MemoryStore keeps buckets in process memory. Limits are only enforced per
+// instance, so use it when running a single replica.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	calls   int
+	now     func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*memoryBucket),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	if !limit.valid() {
+		return Result{Allowed: true}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.calls++
+	if s.calls%pruneEvery == 0 {
+		s.prune(now)
+	}
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: float64(limit.Requests), updated: now}
+		s.buckets[key] = bucket
+	}
+
+	tokens, result := take(bucket.tokens, now.Sub(bucket.updated), limit)
+	bucket.tokens = tokens
+	bucket.updated = now
+	bucket.window = limit.Window
+	return result, nil
+}
+
+// prune drops buckets that have been idle for a full window; they would be
+// full again, which is the same as not existing.
+func (s *MemoryStore) prune(now time.Time) {
+	for key, bucket := range s.buckets {
+		if now.Sub(bucket.updated) >= bucket.window {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PostgresStore keeps buckets in the rate_limit_buckets table so every replica
+// shares them. Each Take locks its bucket row, and elapsed time is measured
+// with the database clock so replicas with skewed clocks agree.
+type PostgresStore struct {
+	db *gorm.DB
+
+	mu        sync.Mutex
+	calls     int
+	maxWindow time.Duration
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+type bucketRow struct {
+	Tokens    float64
+	UpdatedAt time.Time
+	Now       time.Time
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	if !limit.valid() {
+		return Result{Allowed: true}, nil
+	}
+
+	var result Result
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(
+			`INSERT INTO rate_limit_buckets (key, tokens, updated_at) VALUES (?, ?, now()) ON CONFLICT (key) DO NOTHING`,
+			key, float64(limit.Requests),
+		).Error
+		if err != nil {
+			return fmt.Errorf("ensure rate limit bucket: %w", err)
+		}
+
+		var row bucketRow
+		err = tx.Raw(
+			`SELECT tokens, updated_at, now() AS now FROM rate_limit_buckets WHERE key = ? FOR UPDATE`,
+			key,
+		).Scan(&row).Error
+		if err != nil {
+			return fmt.Errorf("lock rate limit bucket: %w", err)
+		}
+		if row.Now.IsZero() {
+			return errors.New("lock rate limit bucket: row missing")
+		}
+
+		var tokens float64
+		tokens, result = take(row.Tokens, row.Now.Sub(row.UpdatedAt), limit)
+		err = tx.Exec(
+			`UPDATE rate_limit_buckets SET tokens = ?, updated_at = ? WHERE key = ?`,
+			tokens, row.Now, key,
+		).Error
+		if err != nil {
+			return fmt.Errorf("update rate limit bucket: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	if s.shouldPrune(limit.Window) {
+		s.prune(ctx)
+	}
+	return result, nil
+}
+
+func (s *PostgresStore) shouldPrune(window time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if window > s.maxWindow {
+		s.maxWindow = window
+	}
+	s.calls++
+	return s.calls%pruneEvery == 0
+}
+
+// prune deletes buckets idle for longer than the longest window seen. Failures
+// are ignored; the next sweep will retry.
+func (s *PostgresStore) prune(ctx context.Context) {
+	s.mu.Lock()
+	idle := s.maxWindow
+	s.mu.Unlock()
+
+	s.db.WithContext(ctx).Exec(
+		`DELETE FROM rate_limit_buckets WHERE updated_at < now() - make_interval(secs => ?)`,
+		idle.Seconds(),
+	)
+}
@@ -0,0 +1,100 @@
+// Package ratelimit implements token bucket rate limiting over pluggable
+// stores: an in-memory store for a single instance and a PostgreSQL store that
+// is shared by every replica.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pruneEvery is how many Take calls a store handles between sweeps of idle
+// buckets.
+const pruneEvery = 1000
+
+// Limit allows Requests per Window, refilled continuously, with bursts of up to
+// Requests.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// ParseLimit reads limits written as "<requests>/<window>", for example "5/1m"
+// or "100/1h". A bare unit such as "10/m" means one of that unit.
+func ParseLimit(raw string) (Limit, error) {
+	count, window, ok := strings.Cut(strings.TrimSpace(raw), "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("rate limit %q must look like <requests>/<window>", raw)
+	}
+
+	requests, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || requests <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q must allow a positive number of requests", raw)
+	}
+
+	window = strings.TrimSpace(window)
+	if window != "" && (window[0] < '0' || window[0] > '9') {
+		window = "1" + window
+	}
+	duration, err := time.ParseDuration(window)
+	if err != nil || duration <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q must have a positive window", raw)
+	}
+
+	return Limit{Requests: requests, Window: duration}, nil
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Window)
+}
+
+func (l Limit) valid() bool {
+	return l.Requests > 0 && l.Window > 0
+}
+
+// Result describes the bucket after a Take.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until the next request would be allowed; zero
+	// when Allowed.
+	RetryAfter time.Duration
+	// ResetAfter is how long until the bucket is full again.
+	ResetAfter time.Duration
+}
+
+// Store takes one token from the bucket identified by key.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// take refills a bucket holding tokens as of elapsed ago, then tries to spend
+// one token. It is shared by every store so they behave identically.
+func take(tokens float64, elapsed time.Duration, limit Limit) (float64, Result) {
+	capacity := float64(limit.Requests)
+	perSecond := capacity / limit.Window.Seconds()
+
+	if elapsed > 0 {
+		tokens = math.Min(capacity, tokens+elapsed.Seconds()*perSecond)
+	}
+
+	result := Result{Limit: limit.Requests}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - tokens) / perSecond)
+	}
+	result.Remaining = int(math.Floor(tokens))
+	result.ResetAfter = secondsToDuration((capacity - tokens) / perSecond)
+	return tokens, result
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
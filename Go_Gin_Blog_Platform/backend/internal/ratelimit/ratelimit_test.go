@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	cases := map[string]Limit{
+		"5/1m":     {Requests: 5, Window: time.Minute},
+		" 100/1h ": {Requests: 100, Window: time.Hour},
+		"10/m":     {Requests: 10, Window: time.Minute},
+		"3/30s":    {Requests: 3, Window: 30 * time.Second},
+	}
+	for raw, want := range cases {
+		got, err := ParseLimit(raw)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", raw, err)
+		}
+		if got != want {
+			t.Fatalf("%q: expected %+v, got %+v", raw, want, got)
+		}
+	}
+
+	for _, raw := range []string{"", "5", "0/1m", "-1/1m", "5/0s", "five/1m", "5/forever"} {
+		if _, err := ParseLimit(raw); err == nil {
+			t.Fatalf("%q: expected an error", raw)
+		}
+	}
+}
+
+func TestMemoryStoreAllowsBurstThenRefills(t *testing.T) {
+	store := NewMemoryStore()
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return clock }
+	limit := Limit{Requests: 3, Window: 3 * time.Second}
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		result, err := store.Take(ctx, "ip:1", limit)
+		if err != nil || !result.Allowed {
+			t.Fatalf("request %d: expected to be allowed, got %+v, %v", i+1, result, err)
+		}
+		if result.Remaining != 2-i {
+			t.Fatalf("request %d: expected %d remaining, got %d", i+1, 2-i, result.Remaining)
+		}
+	}
+
+	denied, _ := store.Take(ctx, "ip:1", limit)
+	if denied.Allowed {
+		t.Fatalf("expected fourth request to be denied")
+	}
+	if denied.RetryAfter != time.Second || denied.ResetAfter != 3*time.Second {
+		t.Fatalf("expected retry after 1s and reset after 3s, got %+v", denied)
+	}
+
+	other, _ := store.Take(ctx, "ip:2", limit)
+	if !other.Allowed {
+		t.Fatalf("expected buckets to be independent per key")
+	}
+
+	clock = clock.Add(time.Second)
+	refilled, _ := store.Take(ctx, "ip:1", limit)
+	if !refilled.Allowed || refilled.Remaining != 0 {
+		t.Fatalf("expected one token after a second, got %+v", refilled)
+	}
+}
+
+func TestMemoryStorePrunesIdleBuckets(t *testing.T) {
+	store := NewMemoryStore()
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return clock }
+	limit := Limit{Requests: 1, Window: time.Minute}
+	ctx := context.Background()
+
+	store.Take(ctx, "idle", limit)
+	clock = clock.Add(2 * time.Minute)
+	for i := 0; i < pruneEvery; i++ {
+		store.Take(ctx, "busy", limit)
+	}
+
+	if _, ok := store.buckets["idle"]; ok {
+		t.Fatalf("expected idle bucket to be pruned")
+	}
+	if _, ok := store.buckets["busy"]; !ok {
+		t.Fatalf("expected busy bucket to be kept")
+	}
+}
@@ -0,0 +1,105 @@
+package render
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var (
+	scriptTag     = regexp.MustCompile(`(?i)<\s*script`)
+	eventHandler  = regexp.MustCompile(`(?i)<[^>]*\son[a-z]+\s*=`)
+	dangerousURL  = regexp.MustCompile(`(?i)(href|src|action|background|data)\s*=\s*"?\s*(javascript|vbscript|data):`)
+	forbiddenTags = regexp.MustCompile(`(?i)<\s*(iframe|object|embed|form|style|meta|link|base|svg|math|body)\b`)
+	styleAttr     = regexp.MustCompile(`(?i)<[^>]*\sstyle\s*=`)
+	srcdocAttr    = regexp.MustCompile(`(?i)<[^>]*\ssrcdoc\s*=`)
+)
+
+func TestMarkdownSanitizesXSSCorpus(t *testing.T) {
+	file, err := os.Open("testdata/xss_corpus.txt")
+	if err != nil {
+		t.Fatalf("open corpus: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	var payloads []string
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.TrimSpace(line) != "" {
+			payloads = append(payloads, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("read corpus: %v", err)
+	}
+	// Also render the whole corpus as one document so payloads can interact.
+	payloads = append(payloads, strings.Join(payloads, "\n"))
+
+	checks := map[string]*regexp.Regexp{
+		"script tag":    scriptTag,
+		"event handler": eventHandler,
+		"dangerous url": dangerousURL,
+		"forbidden tag": forbiddenTags,
+		"style attr":    styleAttr,
+		"srcdoc attr":   srcdocAttr,
+	}
+
+	for _, payload := range payloads {
+		out, err := Markdown(payload)
+		if err != nil {
+			t.Fatalf("render %q: %v", payload, err)
+		}
+		for name, pattern := range checks {
+			if pattern.MatchString(out) {
+				t.Errorf("%s survived sanitization\ninput:  %q\noutput: %q", name, payload, out)
+			}
+		}
+	}
+}
+
+func TestMarkdownRendersGFMFeatures(t *testing.T) {
+	source := strings.Join([]string{
+		"| a | b |",
+		"|---|---|",
+		"| 1 | 2 |",
+		"",
+		"```go",
+		"fmt.Println(\"<hi>\")",
+		"```",
+		"",
+		"Claim[^1] ~~old~~",
+		"",
+		"[^1]: Source.",
+	}, "\n")
+
+	out, err := Markdown(source)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+
+	for _, want := range []string{
+		"<table>",
+		"<td>1</td>",
+		`<code class="language-go">`,
+		"fmt.Println(&#34;&lt;hi&gt;&#34;)",
+		`class="footnote-ref"`,
+		`<li id="fn:1">`,
+		"<del>old</del>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q\noutput: %s", want, out)
+		}
+	}
+}
+
+func TestMarkdownKeepsSafeLinks(t *testing.T) {
+	out, err := Markdown("[docs](https://example.com/docs)")
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(out, `href="https://example.com/docs"`) || !strings.Contains(out, `rel="nofollow"`) {
+		t.Fatalf("expected safe link with nofollow, got %s", out)
+	}
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type AuditFilter struct {
+	ActorID string
+	Action  string
+	// From is inclusive and To exclusive; either may be nil.
+	From *time.Time
+	To   *time.Time
+	// After continues a listing below the given event.
+	After *AuditCursor
+}
+
+// AuditCursor points at an event in the newest first order of the log.
+type AuditCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, event *models.AuditEvent) error
+	// List returns up to limit events matching filter, newest first.
+	List(ctx context.Context, filter AuditFilter, limit int) ([]models.AuditEvent, error)
+}
+
+type GormAuditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) *GormAuditRepository {
+	return &GormAuditRepository{db: db}
+}
+
+func (r *GormAuditRepository) Create(ctx context.Context, event *models.AuditEvent) error {
+	if err := conn(ctx, r.db).Create(event).Error; err != nil {
+		return fmt.Errorf("create audit event: %w", err)
+	}
+	return nil
+}
+
+func (r *GormAuditRepository) List(ctx context.Context, filter AuditFilter, limit int) ([]models.AuditEvent, error) {
+	query := conn(ctx, r.db).Model(&models.AuditEvent{})
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	if filter.After != nil {
+		query = query.Where("(created_at, id) < (?, ?)", filter.After.CreatedAt, filter.After.ID)
+	}
+
+	var events []models.AuditEvent
+	err := query.
+		Order("created_at desc, id desc").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("list audit events: %w", err)
+	}
+	return events, nil
+}
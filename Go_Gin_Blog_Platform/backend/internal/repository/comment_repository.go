@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type CommentRepository interface {
+	Create(ctx context.Context, comment *models.Comment) error
+	GetByID(ctx context.Context, id string) (*models.Comment, error)
+	List(ctx context.Context, filter CommentListFilter, limit, offset int) ([]models.Comment, int64, error)
+	Update(ctx context.Context, id string, updates map[string]any) error
+	HasReplies(ctx context.Context, id string) (bool, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type CommentListFilter struct {
+	PostID   string
+	Statuses []models.CommentStatus
+	// IncludeAuthorID also matches that author's comments whatever their
+	// status, so commenters can see their own pending comments.
+	IncludeAuthorID string
+	// ExcludeDeleted leaves out tombstoned comments.
+	ExcludeDeleted bool
+}
+
+type GormCommentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) *GormCommentRepository {
+	return &GormCommentRepository{db: db}
+}
+
+func (r *GormCommentRepository) Create(ctx context.Context, comment *models.Comment) error {
+	if err := conn(ctx, r.db).Create(comment).Error; err != nil {
+		return fmt.Errorf("create comment: %w", err)
+	}
+	return nil
+}
+
+func (r *GormCommentRepository) GetByID(ctx context.Context, id string) (*models.Comment, error) {
+	var comment models.Comment
+	err := conn(ctx, r.db).Where("id = ?", id).First(&comment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get comment by id: %w", err)
+	}
+	return &comment, nil
+}
+
+// List returns matching comments oldest first, which is both thread order and
+// moderation queue order.
+func (r *GormCommentRepository) List(ctx context.Context, filter CommentListFilter, limit, offset int) ([]models.Comment, int64, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := conn(ctx, r.db).Model(&models.Comment{})
+	if filter.PostID != "" {
+		query = query.Where("post_id = ?", filter.PostID)
+	}
+	if len(filter.Statuses) > 0 {
+		if filter.IncludeAuthorID != "" {
+			query = query.Where("(status IN ? OR author_id = ?)", filter.Statuses, filter.IncludeAuthorID)
+		} else {
+			query = query.Where("status IN ?", filter.Statuses)
+		}
+	}
+	if filter.ExcludeDeleted {
+		query = query.Where("deleted_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count comments: %w", err)
+	}
+
+	var comments []models.Comment
+	err := query.Order("created_at asc").Limit(limit).Offset(offset).Find(&comments).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("list comments: %w", err)
+	}
+	return comments, total, nil
+}
+
+func (r *GormCommentRepository) Update(ctx context.Context, id string, updates map[string]any) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	updates["updated_at"] = time.Now().UTC()
+
+	result := conn(ctx, r.db).Model(&models.Comment{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("update comment: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *GormCommentRepository) HasReplies(ctx context.Context, id string) (bool, error) {
+	var count int64
+	if err := conn(ctx, r.db).Model(&models.Comment{}).Where("parent_id = ?", id).Limit(1).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("count comment replies: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *GormCommentRepository) Delete(ctx context.Context, id string) error {
+	result := conn(ctx, r.db).Where("id = ?", id).Delete(&models.Comment{})
+	if result.Error != nil {
+		return fmt.Errorf("delete comment: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -1,6 +1,14 @@
 package repository
 
-import "errors"
+import (
+	"errors"
+	"strings"
+)
 
 var ErrNotFound = errors.New("not found")
 var ErrDuplicate = errors.New("duplicate")
+
+func isDuplicateError(err error) bool {
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "duplicate") || strings.Contains(message, "unique")
+}
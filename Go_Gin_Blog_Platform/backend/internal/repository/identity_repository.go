@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdentityRepository interface {
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*models.Identity, error)
+	// Create links a provider account, returning ErrDuplicate if it is already
+	// linked to a user.
+	Create(ctx context.Context, identity *models.Identity) error
+	TouchLogin(ctx context.Context, id string, at time.Time) error
+	SaveLoginState(ctx context.Context, state *models.OIDCLoginState) error
+	// ConsumeLoginState deletes the state with the given hash and returns it,
+	// or ErrNotFound if there is none or it expired before now.
+	ConsumeLoginState(ctx context.Context, stateHash string, now time.Time) (*models.OIDCLoginState, error)
+	DeleteExpiredLoginStates(ctx context.Context, before time.Time) (int64, error)
+}
+
+type GormIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewIdentityRepository(db *gorm.DB) *GormIdentityRepository {
+	return &GormIdentityRepository{db: db}
+}
+
+func (r *GormIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*models.Identity, error) {
+	var identity models.Identity
+	err := conn(ctx, r.db).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get identity: %w", err)
+	}
+	return &identity, nil
+}
+
+func (r *GormIdentityRepository) Create(ctx context.Context, identity *models.Identity) error {
+	if err := conn(ctx, r.db).Create(identity).Error; err != nil {
+		if isDuplicateError(err) {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("create identity: %w", err)
+	}
+	return nil
+}
+
+func (r *GormIdentityRepository) TouchLogin(ctx context.Context, id string, at time.Time) error {
+	err := conn(ctx, r.db).
+		Model(&models.Identity{}).
+		Where("id = ?", id).
+		Update("last_login_at", at).Error
+	if err != nil {
+		return fmt.Errorf("touch identity login: %w", err)
+	}
+	return nil
+}
+
+func (r *GormIdentityRepository) SaveLoginState(ctx context.Context, state *models.OIDCLoginState) error {
+	if err := conn(ctx, r.db).Create(state).Error; err != nil {
+		return fmt.Errorf("save oidc login state: %w", err)
+	}
+	return nil
+}
+
+func (r *GormIdentityRepository) ConsumeLoginState(ctx context.Context, stateHash string, now time.Time) (*models.OIDCLoginState, error) {
+	var states []models.OIDCLoginState
+	// Deleting with RETURNING makes the state single use even when the
+	// callback is replayed concurrently.
+	result := conn(ctx, r.db).
+		Clauses(clause.Returning{}).
+		Where("state_hash = ?", stateHash).
+		Delete(&states)
+	if result.Error != nil {
+		return nil, fmt.Errorf("consume oidc login state: %w", result.Error)
+	}
+	if len(states) == 0 || !states[0].ExpiresAt.After(now) {
+		return nil, ErrNotFound
+	}
+	return &states[0], nil
+}
+
+func (r *GormIdentityRepository) DeleteExpiredLoginStates(ctx context.Context, before time.Time) (int64, error) {
+	result := conn(ctx, r.db).Where("expires_at <= ?", before).Delete(&models.OIDCLoginState{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("delete expired oidc login states: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type LoginThrottleRepository interface {
+	Get(ctx context.Context, key string) (*models.LoginThrottle, error)
+	// RecordFailure counts a failed sign-in at now. Failures older than
+	// failuresSince no longer count, and previous lockouts older than
+	// lockoutsSince are forgotten.
+	RecordFailure(ctx context.Context, key string, now, failuresSince, lockoutsSince time.Time) (*models.LoginThrottle, error)
+	// Lock locks the subject until the given time, bumping its lockout count
+	// and starting a fresh failure count.
+	Lock(ctx context.Context, key string, until time.Time) error
+	Clear(ctx context.Context, key string) error
+	// DeleteIdle removes subjects whose last failure is before idleSince and
+	// that are not locked at now.
+	DeleteIdle(ctx context.Context, idleSince, now time.Time) (int64, error)
+}
+
+type GormLoginThrottleRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginThrottleRepository(db *gorm.DB) *GormLoginThrottleRepository {
+	return &GormLoginThrottleRepository{db: db}
+}
+
+func (r *GormLoginThrottleRepository) Get(ctx context.Context, key string) (*models.LoginThrottle, error) {
+	var throttle models.LoginThrottle
+	err := conn(ctx, r.db).Where("key = ?", key).First(&throttle).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get login throttle: %w", err)
+	}
+	return &throttle, nil
+}
+
+func (r *GormLoginThrottleRepository) RecordFailure(ctx context.Context, key string, now, failuresSince, lockoutsSince time.Time) (*models.LoginThrottle, error) {
+	var throttle models.LoginThrottle
+	err := conn(ctx, r.db).Raw(`
+		INSERT INTO login_throttles (key, failures, lockouts, last_failure_at)
+		VALUES (@key, 1, 0, @now)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_throttles.last_failure_at < @failures_since THEN 1 ELSE login_throttles.failures + 1 END,
+			lockouts = CASE WHEN login_throttles.last_failure_at < @lockouts_since THEN 0 ELSE login_throttles.lockouts END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING key, failures, lockouts, locked_until, last_failure_at`,
+		map[string]any{
+			"key":            key,
+			"now":            now,
+			"failures_since": failuresSince,
+			"lockouts_since": lockoutsSince,
+		},
+	).Scan(&throttle).Error
+	if err != nil {
+		return nil, fmt.Errorf("record login failure: %w", err)
+	}
+	return &throttle, nil
+}
+
+func (r *GormLoginThrottleRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	result := conn(ctx, r.db).
+		Model(&models.LoginThrottle{}).
+		Where("key = ?", key).
+		Updates(map[string]any{
+			"locked_until": until,
+			"lockouts":     gorm.Expr("lockouts + 1"),
+			"failures":     0,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("lock login: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *GormLoginThrottleRepository) Clear(ctx context.Context, key string) error {
+	if err := conn(ctx, r.db).Where("key = ?", key).Delete(&models.LoginThrottle{}).Error; err != nil {
+		return fmt.Errorf("clear login throttle: %w", err)
+	}
+	return nil
+}
+
+func (r *GormLoginThrottleRepository) DeleteIdle(ctx context.Context, idleSince, now time.Time) (int64, error) {
+	result := conn(ctx, r.db).
+		Where("last_failure_at < ? AND (locked_until IS NULL OR locked_until <= ?)", idleSince, now).
+		Delete(&models.LoginThrottle{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("delete idle login throttles: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MFARepository interface {
+	GetTOTP(ctx context.Context, userID string) (*models.TOTPCredential, error)
+	// SavePendingTOTP stores a secret that is not yet enabled, replacing any
+	// earlier pending one. It returns ErrDuplicate if TOTP is already enabled.
+	SavePendingTOTP(ctx context.Context, userID, secret string) error
+	// EnableTOTP turns on the pending secret, recording step as used.
+	EnableTOTP(ctx context.Context, userID string, step int64, at time.Time) error
+	// ConsumeTOTPStep records step as used, returning ErrNotFound if a code
+	// from that step or a later one was already accepted.
+	ConsumeTOTPStep(ctx context.Context, userID string, step int64) error
+	DeleteTOTP(ctx context.Context, userID string) error
+	// ReplaceRecoveryCodes drops the user's recovery codes and stores the given
+	// hashes in their place.
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error
+	// UseRecoveryCode marks an unused code as used, returning ErrNotFound if
+	// the user has no such unused code.
+	UseRecoveryCode(ctx context.Context, userID, codeHash string, at time.Time) error
+	CountUnusedRecoveryCodes(ctx context.Context, userID string) (int64, error)
+	DeleteRecoveryCodes(ctx context.Context, userID string) error
+}
+
+type GormMFARepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) *GormMFARepository {
+	return &GormMFARepository{db: db}
+}
+
+func (r *GormMFARepository) GetTOTP(ctx context.Context, userID string) (*models.TOTPCredential, error) {
+	var credential models.TOTPCredential
+	err := conn(ctx, r.db).Where("user_id = ?", userID).First(&credential).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get totp credential: %w", err)
+	}
+	return &credential, nil
+}
+
+func (r *GormMFARepository) SavePendingTOTP(ctx context.Context, userID, secret string) error {
+	now := time.Now().UTC()
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]any{"secret": secret, "last_used_step": 0, "updated_at": now}),
+			Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "totp_credentials.enabled_at IS NULL"}}},
+		}).
+		Create(&models.TOTPCredential{UserID: userID, Secret: secret, CreatedAt: now, UpdatedAt: now})
+	if result.Error != nil {
+		return fmt.Errorf("save pending totp credential: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrDuplicate
+	}
+	return nil
+}
+
+func (r *GormMFARepository) EnableTOTP(ctx context.Context, userID string, step int64, at time.Time) error {
+	result := conn(ctx, r.db).
+		Model(&models.TOTPCredential{}).
+		Where("user_id = ?", userID).
+		Where("enabled_at IS NULL").
+		Updates(map[string]any{"enabled_at": at, "last_used_step": step, "updated_at": at})
+	if result.Error != nil {
+		return fmt.Errorf("enable totp credential: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *GormMFARepository) ConsumeTOTPStep(ctx context.Context, userID string, step int64) error {
+	result := conn(ctx, r.db).
+		Model(&models.TOTPCredential{}).
+		Where("user_id = ?", userID).
+		Where("enabled_at IS NOT NULL").
+		Where("last_used_step < ?", step).
+		Updates(map[string]any{"last_used_step": step, "updated_at": time.Now().UTC()})
+	if result.Error != nil {
+		return fmt.Errorf("consume totp step: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *GormMFARepository) DeleteTOTP(ctx context.Context, userID string) error {
+	result := conn(ctx, r.db).Where("user_id = ?", userID).Delete(&models.TOTPCredential{})
+	if result.Error != nil {
+		return fmt.Errorf("delete totp credential: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *GormMFARepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	db := conn(ctx, r.db)
+	if err := db.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return fmt.Errorf("delete recovery codes: %w", err)
+	}
+	if len(codeHashes) == 0 {
+		return nil
+	}
+
+	codes := make([]models.RecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, models.RecoveryCode{UserID: userID, CodeHash: hash})
+	}
+	if err := db.Create(&codes).Error; err != nil {
+		return fmt.Errorf("create recovery codes: %w", err)
+	}
+	return nil
+}
+
+func (r *GormMFARepository) UseRecoveryCode(ctx context.Context, userID, codeHash string, at time.Time) error {
+	result := conn(ctx, r.db).
+		Model(&models.RecoveryCode{}).
+		Where("user_id = ?", userID).
+		Where("code_hash = ?", codeHash).
+		Where("used_at IS NULL").
+		Updates(map[string]any{"used_at": at})
+	if result.Error != nil {
+		return fmt.Errorf("use recovery code: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *GormMFARepository) CountUnusedRecoveryCodes(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).
+		Model(&models.RecoveryCode{}).
+		Where("user_id = ?", userID).
+		Where("used_at IS NULL").
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("count recovery codes: %w", err)
+	}
+	return count, nil
+}
+
+func (r *GormMFARepository) DeleteRecoveryCodes(ctx context.Context, userID string) error {
+	if err := conn(ctx, r.db).Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return fmt.Errorf("delete recovery codes: %w", err)
+	}
+	return nil
+}
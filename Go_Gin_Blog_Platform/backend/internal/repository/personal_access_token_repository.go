@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type PersonalAccessTokenRepository interface {
+	Create(ctx context.Context, token *models.PersonalAccessToken) error
+	// ListByUser returns the user's tokens that have not been revoked, newest
+	// first. Expired tokens are included so users can see why they stopped
+	// working.
+	ListByUser(ctx context.Context, userID string) ([]models.PersonalAccessToken, error)
+	// GetActiveByHash returns the unrevoked token with the given hash, or
+	// ErrNotFound if there is none or it expired before now.
+	GetActiveByHash(ctx context.Context, tokenHash string, now time.Time) (*models.PersonalAccessToken, error)
+	Revoke(ctx context.Context, id string, at time.Time) error
+	// RevokeAllForUser revokes every unrevoked token the user holds and returns
+	// how many there were.
+	RevokeAllForUser(ctx context.Context, userID string, at time.Time) (int64, error)
+	// TouchLastUsed records a use at the given time unless one was already
+	// recorded after staleBefore, so busy tokens are not written on every
+	// request.
+	TouchLastUsed(ctx context.Context, id string, at, staleBefore time.Time) error
+}
+
+type GormPersonalAccessTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewPersonalAccessTokenRepository(db *gorm.DB) *GormPersonalAccessTokenRepository {
+	return &GormPersonalAccessTokenRepository{db: db}
+}
+
+func (r *GormPersonalAccessTokenRepository) Create(ctx context.Context, token *models.PersonalAccessToken) error {
+	if err := conn(ctx, r.db).Create(token).Error; err != nil {
+		if isDuplicateError(err) {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("create personal access token: %w", err)
+	}
+	return nil
+}
+
+func (r *GormPersonalAccessTokenRepository) ListByUser(ctx context.Context, userID string) ([]models.PersonalAccessToken, error) {
+	var tokens []models.PersonalAccessToken
+	err := conn(ctx, r.db).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Order("created_at DESC").
+		Find(&tokens).Error
+	if err != nil {
+		return nil, fmt.Errorf("list personal access tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+func (r *GormPersonalAccessTokenRepository) GetActiveByHash(ctx context.Context, tokenHash string, now time.Time) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	err := conn(ctx, r.db).
+		Where("token_hash = ?", tokenHash).
+		Where("revoked_at IS NULL").
+		Where("expires_at > ?", now).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get personal access token by hash: %w", err)
+	}
+	return &token, nil
+}
+
+func (r *GormPersonalAccessTokenRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	result := conn(ctx, r.db).
+		Model(&models.PersonalAccessToken{}).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return fmt.Errorf("revoke personal access token: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *GormPersonalAccessTokenRepository) RevokeAllForUser(ctx context.Context, userID string, at time.Time) (int64, error) {
+	result := conn(ctx, r.db).
+		Model(&models.PersonalAccessToken{}).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return 0, fmt.Errorf("revoke personal access tokens for user: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *GormPersonalAccessTokenRepository) TouchLastUsed(ctx context.Context, id string, at, staleBefore time.Time) error {
+	err := conn(ctx, r.db).
+		Model(&models.PersonalAccessToken{}).
+		Where("id = ?", id).
+		Where("last_used_at IS NULL OR last_used_at < ?", staleBefore).
+		Update("last_used_at", at).Error
+	if err != nil {
+		return fmt.Errorf("touch personal access token: %w", err)
+	}
+	return nil
+}
@@ -8,14 +8,40 @@ import (
 
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PostRepository interface {
 	Create(ctx context.Context, post *models.Post) error
 	GetByID(ctx context.Context, id string) (*models.Post, error)
-	List(ctx context.Context, limit, offset int) ([]models.Post, int64, error)
+	// GetByIDForUpdate loads a post and locks its row until the surrounding
+	// transaction ends, without its tags and category.
+	GetByIDForUpdate(ctx context.Context, id string) (*models.Post, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Post, error)
+	GetByHistoricalSlug(ctx context.Context, slug string) (*models.Post, error)
+	SlugTaken(ctx context.Context, slug, excludePostID string) (bool, error)
+	List(ctx context.Context, filter PostListFilter, limit, offset int) ([]models.Post, int64, error)
+	Search(ctx context.Context, query string, filter PostListFilter, limit, offset int) ([]PostSearchResult, int64, error)
 	Update(ctx context.Context, id string, updates map[string]any) error
 	Delete(ctx context.Context, id string) error
+	GetTrashedByID(ctx context.Context, id string) (*models.Post, error)
+	Restore(ctx context.Context, id string) error
+	Purge(ctx context.Context, id string) error
+}
+
+type PostListFilter struct {
+	AuthorID     string
+	Statuses     []models.PostStatus
+	TagSlug      string
+	CategorySlug string
+	// Trashed lists soft-deleted posts instead of live ones.
+	Trashed bool
+	// ReviewerID keeps posts assigned to one reviewer; Unassigned keeps posts
+	// with no reviewer.
+	ReviewerID string
+	Unassigned bool
+	// ReviewOrder lists the longest waiting submission first.
+	ReviewOrder bool
 }
 
 type GormPostRepository struct {
@@ -27,7 +53,15 @@ func NewPostRepository(db *gorm.DB) *GormPostRepository {
 }
 
 func (r *GormPostRepository) Create(ctx context.Context, post *models.Post) error {
-	if err := r.db.WithContext(ctx).Create(post).Error; err != nil {
+	// The nested transaction becomes a savepoint when the caller already holds a
+	// transaction, so a duplicate slug can be retried without aborting it.
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		return tx.Omit(clause.Associations).Create(post).Error
+	})
+	if err != nil {
+		if isDuplicateError(err) {
+			return fmt.Errorf("create post: %w", ErrDuplicate)
+		}
 		return fmt.Errorf("create post: %w", err)
 	}
 	return nil
@@ -35,7 +69,7 @@ func (r *GormPostRepository) Create(ctx context.Context, post *models.Post) erro
 
 func (r *GormPostRepository) GetByID(ctx context.Context, id string) (*models.Post, error) {
 	var post models.Post
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&post).Error
+	err := r.withTaxonomy(ctx).Where("id = ?", id).First(&post).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrNotFound
@@ -45,7 +79,76 @@ func (r *GormPostRepository) GetByID(ctx context.Context, id string) (*models.Po
 	return &post, nil
 }
 
-func (r *GormPostRepository) List(ctx context.Context, limit, offset int) ([]models.Post, int64, error) {
+func (r *GormPostRepository) GetByIDForUpdate(ctx context.Context, id string) (*models.Post, error) {
+	var post models.Post
+	err := conn(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&post).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get post by id for update: %w", err)
+	}
+	return &post, nil
+}
+
+func (r *GormPostRepository) GetBySlug(ctx context.Context, slug string) (*models.Post, error) {
+	var post models.Post
+	err := r.withTaxonomy(ctx).Where("slug = ?", slug).First(&post).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get post by slug: %w", err)
+	}
+	return &post, nil
+}
+
+func (r *GormPostRepository) GetByHistoricalSlug(ctx context.Context, slug string) (*models.Post, error) {
+	var post models.Post
+	err := r.withTaxonomy(ctx).
+		Joins("JOIN post_slug_history h ON h.post_id = posts.id").
+		Where("h.slug = ?", slug).
+		First(&post).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get post by historical slug: %w", err)
+	}
+	return &post, nil
+}
+
+// SlugTaken reports whether slug is in use as a current or historical slug of
+// any post other than excludePostID, so a post may reclaim its own old slugs.
+// Trashed posts keep their slugs so they can be restored.
+func (r *GormPostRepository) SlugTaken(ctx context.Context, slug, excludePostID string) (bool, error) {
+	var current int64
+	query := conn(ctx, r.db).Unscoped().Model(&models.Post{}).Where("slug = ?", slug)
+	if excludePostID != "" {
+		query = query.Where("id <> ?", excludePostID)
+	}
+	if err := query.Count(&current).Error; err != nil {
+		return false, fmt.Errorf("check post slug: %w", err)
+	}
+	if current > 0 {
+		return true, nil
+	}
+
+	var historical int64
+	query = conn(ctx, r.db).Model(&models.PostSlugHistory{}).Where("slug = ?", slug)
+	if excludePostID != "" {
+		query = query.Where("post_id <> ?", excludePostID)
+	}
+	if err := query.Count(&historical).Error; err != nil {
+		return false, fmt.Errorf("check historical post slug: %w", err)
+	}
+	return historical > 0, nil
+}
+
+func (r *GormPostRepository) List(ctx context.Context, filter PostListFilter, limit, offset int) ([]models.Post, int64, error) {
 	if limit <= 0 {
 		limit = 10
 	}
@@ -57,13 +160,23 @@ func (r *GormPostRepository) List(ctx context.Context, limit, offset int) ([]mod
 	}
 
 	var total int64
-	if err := r.db.WithContext(ctx).Model(&models.Post{}).Count(&total).Error; err != nil {
+	if err := r.filtered(ctx, filter).Model(&models.Post{}).Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("count posts: %w", err)
 	}
 
+	order := "created_at desc"
+	switch {
+	case filter.Trashed:
+		order = "deleted_at desc"
+	case filter.ReviewOrder:
+		order = "submitted_at asc, created_at asc"
+	}
+
 	var posts []models.Post
-	err := r.db.WithContext(ctx).
-		Order("created_at desc").
+	err := r.filtered(ctx, filter).
+		Preload("Tags").
+		Preload("Category").
+		Order(order).
 		Limit(limit).
 		Offset(offset).
 		Find(&posts).Error
@@ -74,19 +187,105 @@ func (r *GormPostRepository) List(ctx context.Context, limit, offset int) ([]mod
 	return posts, total, nil
 }
 
+func (r *GormPostRepository) filtered(ctx context.Context, filter PostListFilter) *gorm.DB {
+	query := conn(ctx, r.db)
+	if filter.Trashed {
+		query = query.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+	if filter.AuthorID != "" {
+		query = query.Where("author_id = ?", filter.AuthorID)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if filter.ReviewerID != "" {
+		query = query.Where("reviewer_id = ?", filter.ReviewerID)
+	}
+	if filter.Unassigned {
+		query = query.Where("reviewer_id IS NULL")
+	}
+	if filter.TagSlug != "" {
+		query = query.Where(`EXISTS (
+			SELECT 1 FROM post_tags JOIN tags ON tags.id = post_tags.tag_id
+			WHERE post_tags.post_id = posts.id AND tags.slug = ?)`, filter.TagSlug)
+	}
+	if filter.CategorySlug != "" {
+		// A category filter also matches posts filed under any descendant category.
+		query = query.Where(`category_id IN (
+			WITH RECURSIVE tree AS (
+				SELECT id FROM categories WHERE slug = ?
+				UNION ALL
+				SELECT categories.id FROM categories JOIN tree ON categories.parent_id = tree.id
+			)
+			SELECT id FROM tree)`, filter.CategorySlug)
+	}
+	return query
+}
+
+func (r *GormPostRepository) withTaxonomy(ctx context.Context) *gorm.DB {
+	return conn(ctx, r.db).Preload("Tags").Preload("Category")
+}
+
 func (r *GormPostRepository) Update(ctx context.Context, id string, updates map[string]any) error {
 	if len(updates) == 0 {
 		return nil
 	}
 	updates["updated_at"] = time.Now().UTC()
 
-	result := r.db.WithContext(ctx).
-		Model(&models.Post{}).
-		Where("id = ?", id).
-		Updates(updates)
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if slug, ok := updates["slug"].(string); ok {
+			if err := recordSlugChange(tx, id, slug); err != nil {
+				return err
+			}
+		}
+
+		result := tx.Model(&models.Post{}).
+			Where("id = ?", id).
+			Updates(updates)
+
+		if result.Error != nil {
+			if isDuplicateError(result.Error) {
+				return fmt.Errorf("update post: %w", ErrDuplicate)
+			}
+			return fmt.Errorf("update post: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+// recordSlugChange keeps the post's current slug resolvable after it is
+// replaced by newSlug.
+func recordSlugChange(tx *gorm.DB, postID, newSlug string) error {
+	var post models.Post
+	if err := tx.Select("id", "slug").Where("id = ?", postID).First(&post).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("load current post slug: %w", err)
+	}
+	if post.Slug == newSlug {
+		return nil
+	}
 
+	if err := tx.Where("post_id = ? AND slug = ?", postID, newSlug).Delete(&models.PostSlugHistory{}).Error; err != nil {
+		return fmt.Errorf("release reclaimed post slug: %w", err)
+	}
+
+	history := &models.PostSlugHistory{PostID: postID, Slug: post.Slug}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(history).Error; err != nil {
+		return fmt.Errorf("record post slug history: %w", err)
+	}
+	return nil
+}
+
+// Delete moves a post to the trash; it stays restorable until purged.
+func (r *GormPostRepository) Delete(ctx context.Context, id string) error {
+	result := conn(ctx, r.db).Where("id = ?", id).Delete(&models.Post{})
 	if result.Error != nil {
-		return fmt.Errorf("update post: %w", result.Error)
+		return fmt.Errorf("delete post: %w", result.Error)
 	}
 	if result.RowsAffected == 0 {
 		return ErrNotFound
@@ -94,13 +293,83 @@ func (r *GormPostRepository) Update(ctx context.Context, id string, updates map[
 	return nil
 }
 
-func (r *GormPostRepository) Delete(ctx context.Context, id string) error {
-	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Post{})
+func (r *GormPostRepository) GetTrashedByID(ctx context.Context, id string) (*models.Post, error) {
+	var post models.Post
+	err := r.withTaxonomy(ctx).
+		Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&post).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get trashed post by id: %w", err)
+	}
+	return &post, nil
+}
+
+func (r *GormPostRepository) Restore(ctx context.Context, id string) error {
+	result := conn(ctx, r.db).
+		Unscoped().
+		Model(&models.Post{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]any{"deleted_at": nil, "updated_at": time.Now().UTC()})
 	if result.Error != nil {
-		return fmt.Errorf("delete post: %w", result.Error)
+		return fmt.Errorf("restore post: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Purge permanently removes a trashed post together with its revisions, tags
+// and slug history.
+func (r *GormPostRepository) Purge(ctx context.Context, id string) error {
+	result := conn(ctx, r.db).
+		Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Delete(&models.Post{})
+	if result.Error != nil {
+		return fmt.Errorf("purge post: %w", result.Error)
 	}
 	if result.RowsAffected == 0 {
 		return ErrNotFound
 	}
 	return nil
 }
+
+// PurgeTrashedBefore permanently removes every post trashed before cutoff and
+// returns how many were removed.
+func (r *GormPostRepository) PurgeTrashedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := conn(ctx, r.db).
+		Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Delete(&models.Post{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("purge trashed posts: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// PublishDue flips up to limit scheduled posts whose publish_at has passed to
+// published and returns their IDs. Rows already locked by another replica are
+// skipped, so concurrent publishers never pick up the same post.
+func (r *GormPostRepository) PublishDue(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	var ids []string
+	err := conn(ctx, r.db).Raw(`UPDATE posts SET status = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM posts
+			WHERE status = ? AND publish_at <= ? AND deleted_at IS NULL
+			ORDER BY publish_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id`,
+		models.PostStatusPublished, now, models.PostStatusScheduled, now, limit,
+	).Scan(&ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("publish due posts: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type PostReviewRepository interface {
+	Create(ctx context.Context, event *models.PostReviewEvent) error
+	// ListByPost returns a post's review history, oldest first.
+	ListByPost(ctx context.Context, postID string) ([]models.PostReviewEvent, error)
+}
+
+type GormPostReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewPostReviewRepository(db *gorm.DB) *GormPostReviewRepository {
+	return &GormPostReviewRepository{db: db}
+}
+
+func (r *GormPostReviewRepository) Create(ctx context.Context, event *models.PostReviewEvent) error {
+	if err := conn(ctx, r.db).Create(event).Error; err != nil {
+		return fmt.Errorf("create post review event: %w", err)
+	}
+	return nil
+}
+
+func (r *GormPostReviewRepository) ListByPost(ctx context.Context, postID string) ([]models.PostReviewEvent, error) {
+	var events []models.PostReviewEvent
+	err := conn(ctx, r.db).
+		Where("post_id = ?", postID).
+		Order("created_at asc").
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("list post review events: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type PostRevisionRepository interface {
+	Create(ctx context.Context, revision *models.PostRevision) error
+	ListByPost(ctx context.Context, postID string) ([]models.PostRevision, error)
+	Get(ctx context.Context, postID string, revision int) (*models.PostRevision, error)
+}
+
+type GormPostRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewPostRevisionRepository(db *gorm.DB) *GormPostRevisionRepository {
+	return &GormPostRevisionRepository{db: db}
+}
+
+// Create assigns the next revision number for the post. Two concurrent edits
+// computing the same number are rejected by the unique index as ErrDuplicate.
+func (r *GormPostRevisionRepository) Create(ctx context.Context, revision *models.PostRevision) error {
+	db := conn(ctx, r.db)
+
+	var next int
+	err := db.Model(&models.PostRevision{}).
+		Select("COALESCE(MAX(revision), 0) + 1").
+		Where("post_id = ?", revision.PostID).
+		Scan(&next).Error
+	if err != nil {
+		return fmt.Errorf("next post revision number: %w", err)
+	}
+	revision.Revision = next
+
+	if err := db.Create(revision).Error; err != nil {
+		if isDuplicateError(err) {
+			return fmt.Errorf("create post revision: %w", ErrDuplicate)
+		}
+		return fmt.Errorf("create post revision: %w", err)
+	}
+	return nil
+}
+
+func (r *GormPostRevisionRepository) ListByPost(ctx context.Context, postID string) ([]models.PostRevision, error) {
+	var revisions []models.PostRevision
+	err := conn(ctx, r.db).
+		Where("post_id = ?", postID).
+		Order("revision desc").
+		Find(&revisions).Error
+	if err != nil {
+		return nil, fmt.Errorf("list post revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+func (r *GormPostRevisionRepository) Get(ctx context.Context, postID string, revision int) (*models.PostRevision, error) {
+	var stored models.PostRevision
+	err := conn(ctx, r.db).
+		Where("post_id = ? AND revision = ?", postID, revision).
+		First(&stored).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get post revision: %w", err)
+	}
+	return &stored, nil
+}
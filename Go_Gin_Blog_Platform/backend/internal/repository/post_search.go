@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+)
+
+// Snippets mark matched terms with these control characters rather than HTML so
+// callers can escape the surrounding post text before adding highlight markup.
+const (
+	SnippetStartMarker = "\x02"
+	SnippetStopMarker  = "\x03"
+)
+
+const headlineOptions = "StartSel=" + SnippetStartMarker + ", StopSel=" + SnippetStopMarker +
+	", MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=\" … \""
+
+type PostSearchResult struct {
+	Post    models.Post
+	Rank    float64
+	Snippet string
+}
+
+type searchHit struct {
+	ID      string
+	Rank    float64
+	Snippet string
+}
+
+func (r *GormPostRepository) Search(ctx context.Context, query string, filter PostListFilter, limit, offset int) ([]PostSearchResult, int64, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	const tsQuery = "websearch_to_tsquery('english', ?)"
+
+	var total int64
+	err := r.filtered(ctx, filter).
+		Model(&models.Post{}).
+		Where("search_vector @@ "+tsQuery, query).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("count post search results: %w", err)
+	}
+	if total == 0 {
+		return []PostSearchResult{}, 0, nil
+	}
+
+	var hits []searchHit
+	err = r.filtered(ctx, filter).
+		Model(&models.Post{}).
+		Select("posts.id, ts_rank(search_vector, "+tsQuery+") AS rank, ts_headline('english', content, "+tsQuery+", ?) AS snippet",
+			query, query, headlineOptions).
+		Where("search_vector @@ "+tsQuery, query).
+		Order("rank desc, created_at desc").
+		Limit(limit).
+		Offset(offset).
+		Scan(&hits).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("search posts: %w", err)
+	}
+
+	ids := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+
+	var posts []models.Post
+	if err := r.withTaxonomy(ctx).Where("id IN ?", ids).Find(&posts).Error; err != nil {
+		return nil, 0, fmt.Errorf("load post search results: %w", err)
+	}
+	byID := make(map[string]models.Post, len(posts))
+	for _, post := range posts {
+		byID[post.ID] = post
+	}
+
+	results := make([]PostSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		post, ok := byID[hit.ID]
+		if !ok {
+			continue
+		}
+		results = append(results, PostSearchResult{Post: post, Rank: hit.Rank, Snippet: hit.Snippet})
+	}
+	return results, total, nil
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+)
+
+const memorySnippetRadius = 60
+
+// SearchPostsInMemory approximates the PostgreSQL search for tests and fakes:
+// only posts passing filter are searched, every query term must appear, title
+// matches weigh more than content matches, and snippets use the same markers
+// as ts_headline.
+func SearchPostsInMemory(posts []models.Post, query string, filter PostListFilter, limit, offset int) ([]PostSearchResult, int64) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return []PostSearchResult{}, 0
+	}
+
+	var matches []PostSearchResult
+	for _, post := range posts {
+		if !matchesFilterInMemory(post, filter) {
+			continue
+		}
+
+		title := strings.ToLower(post.Title)
+		content := strings.ToLower(post.Content)
+
+		rank := 0.0
+		matchedAll := true
+		for _, term := range terms {
+			titleHits := strings.Count(title, term)
+			contentHits := strings.Count(content, term)
+			if titleHits+contentHits == 0 {
+				matchedAll = false
+				break
+			}
+			rank += float64(titleHits)*1.0 + float64(contentHits)*0.4
+		}
+		if !matchedAll {
+			continue
+		}
+
+		matches = append(matches, PostSearchResult{Post: post, Rank: rank, Snippet: memorySnippet(post.Content, terms)})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Rank != matches[j].Rank {
+			return matches[i].Rank > matches[j].Rank
+		}
+		return matches[i].Post.CreatedAt.After(matches[j].Post.CreatedAt)
+	})
+
+	total := int64(len(matches))
+	if offset >= len(matches) {
+		return []PostSearchResult{}, total
+	}
+	end := len(matches)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return matches[offset:end], total
+}
+
+// matchesFilterInMemory applies filter the way GormPostRepository.filtered
+// does, except that a category filter only matches the post's own category.
+func matchesFilterInMemory(post models.Post, filter PostListFilter) bool {
+	if post.DeletedAt.Valid != filter.Trashed {
+		return false
+	}
+	if filter.AuthorID != "" && post.AuthorID != filter.AuthorID {
+		return false
+	}
+	if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, post.Status) {
+		return false
+	}
+	if filter.ReviewerID != "" && (post.ReviewerID == nil || *post.ReviewerID != filter.ReviewerID) {
+		return false
+	}
+	if filter.Unassigned && post.ReviewerID != nil {
+		return false
+	}
+	if filter.TagSlug != "" && !slices.ContainsFunc(post.Tags, func(tag models.Tag) bool { return tag.Slug == filter.TagSlug }) {
+		return false
+	}
+	if filter.CategorySlug != "" && (post.Category == nil || post.Category.Slug != filter.CategorySlug) {
+		return false
+	}
+	return true
+}
+
+func searchTerms(query string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return fields
+}
+
+func memorySnippet(content string, terms []string) string {
+	// ASCII-only folding keeps byte offsets aligned with content.
+	lower := strings.Map(func(r rune) rune {
+		if r < utf8.RuneSelf {
+			return unicode.ToLower(r)
+		}
+		return r
+	}, content)
+	first := -1
+	for _, term := range terms {
+		if idx := strings.Index(lower, term); idx >= 0 && (first < 0 || idx < first) {
+			first = idx
+		}
+	}
+	if first < 0 {
+		first = 0
+	}
+
+	start := first - memorySnippetRadius
+	if start < 0 {
+		start = 0
+	}
+	end := first + memorySnippetRadius
+	if end > len(content) {
+		end = len(content)
+	}
+	for start > 0 && !utf8.RuneStart(content[start]) {
+		start--
+	}
+	for end < len(content) && !utf8.RuneStart(content[end]) {
+		end++
+	}
+	window := content[start:end]
+	lowerWindow := lower[start:end]
+
+	var b strings.Builder
+	for i := 0; i < len(window); {
+		matched := ""
+		for _, term := range terms {
+			if strings.HasPrefix(lowerWindow[i:], term) && len(term) > len(matched) {
+				matched = term
+			}
+		}
+		if matched == "" {
+			b.WriteByte(window[i])
+			i++
+			continue
+		}
+		b.WriteString(SnippetStartMarker)
+		b.WriteString(window[i : i+len(matched)])
+		b.WriteString(SnippetStopMarker)
+		i += len(matched)
+	}
+	return b.String()
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+)
+
+func TestSearchPostsInMemoryRequiresAllTerms(t *testing.T) {
+	posts := []models.Post{
+		{ID: "p1", Title: "Go tips", Content: "Error handling in Go"},
+		{ID: "p2", Title: "Rust tips", Content: "Ownership and borrowing"},
+	}
+
+	results, total := SearchPostsInMemory(posts, "go error", PostListFilter{}, 10, 0)
+	if total != 1 || len(results) != 1 || results[0].Post.ID != "p1" {
+		t.Fatalf("expected only p1 to match, got %d results", total)
+	}
+
+	want := SnippetStartMarker + "Error" + SnippetStopMarker + " handling in " + SnippetStartMarker + "Go" + SnippetStopMarker
+	if results[0].Snippet != want {
+		t.Fatalf("unexpected snippet %q", results[0].Snippet)
+	}
+}
+
+func TestSearchPostsInMemoryAppliesFilter(t *testing.T) {
+	reviewer := "r1"
+	posts := []models.Post{
+		{ID: "p1", AuthorID: "u1", Title: "go", Status: models.PostStatusPublished, Tags: []models.Tag{{Slug: "web"}}},
+		{ID: "p2", AuthorID: "u1", Title: "go", Status: models.PostStatusDraft},
+		{ID: "p3", AuthorID: "u2", Title: "go", Status: models.PostStatusPublished},
+		{ID: "p4", AuthorID: "u1", Title: "go", Status: models.PostStatusInReview, ReviewerID: &reviewer},
+	}
+
+	cases := []struct {
+		filter PostListFilter
+		want   []string
+	}{
+		{PostListFilter{Statuses: []models.PostStatus{models.PostStatusPublished}}, []string{"p1", "p3"}},
+		{PostListFilter{AuthorID: "u1", Statuses: []models.PostStatus{models.PostStatusPublished}}, []string{"p1"}},
+		{PostListFilter{TagSlug: "web"}, []string{"p1"}},
+		{PostListFilter{ReviewerID: "r1"}, []string{"p4"}},
+	}
+	for _, tc := range cases {
+		results, total := SearchPostsInMemory(posts, "go", tc.filter, 10, 0)
+		var got []string
+		for _, result := range results {
+			got = append(got, result.Post.ID)
+		}
+		if total != int64(len(tc.want)) || strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Fatalf("%+v: expected %v, got %v", tc.filter, tc.want, got)
+		}
+	}
+}
+
+func TestSearchPostsInMemoryPaginates(t *testing.T) {
+	posts := []models.Post{
+		{ID: "p1", Title: "go", Content: "go"},
+		{ID: "p2", Title: "go", Content: ""},
+		{ID: "p3", Title: "", Content: "go"},
+	}
+
+	results, total := SearchPostsInMemory(posts, "go", PostListFilter{}, 1, 1)
+	if total != 3 || len(results) != 1 || results[0].Post.ID != "p2" {
+		t.Fatalf("expected second-ranked p2 on page two, got %+v (total %d)", results, total)
+	}
+}
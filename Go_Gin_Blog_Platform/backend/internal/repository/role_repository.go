@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	// List returns every role with its permissions, ordered by name.
+	List(ctx context.Context) ([]models.RoleDefinition, error)
+	Get(ctx context.Context, name string) (*models.RoleDefinition, error)
+	// Create stores a role and its permissions, returning ErrDuplicate if the
+	// name is taken.
+	Create(ctx context.Context, role *models.RoleDefinition) error
+	ReplacePermissions(ctx context.Context, name string, permissions []string) error
+	Delete(ctx context.Context, name string) error
+	CountUsers(ctx context.Context, name string) (int64, error)
+}
+
+type GormRoleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) *GormRoleRepository {
+	return &GormRoleRepository{db: db}
+}
+
+func (r *GormRoleRepository) List(ctx context.Context) ([]models.RoleDefinition, error) {
+	var roles []models.RoleDefinition
+	err := conn(ctx, r.db).
+		Preload("Permissions", func(db *gorm.DB) *gorm.DB { return db.Order("permission ASC") }).
+		Order("name ASC").
+		Find(&roles).Error
+	if err != nil {
+		return nil, fmt.Errorf("list roles: %w", err)
+	}
+	return roles, nil
+}
+
+func (r *GormRoleRepository) Get(ctx context.Context, name string) (*models.RoleDefinition, error) {
+	var role models.RoleDefinition
+	err := conn(ctx, r.db).
+		Preload("Permissions", func(db *gorm.DB) *gorm.DB { return db.Order("permission ASC") }).
+		Where("name = ?", name).
+		First(&role).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get role: %w", err)
+	}
+	return &role, nil
+}
+
+func (r *GormRoleRepository) Create(ctx context.Context, role *models.RoleDefinition) error {
+	if err := conn(ctx, r.db).Create(role).Error; err != nil {
+		if isDuplicateError(err) {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("create role: %w", err)
+	}
+	return nil
+}
+
+func (r *GormRoleRepository) ReplacePermissions(ctx context.Context, name string, permissions []string) error {
+	db := conn(ctx, r.db)
+	if err := db.Where("role = ?", name).Delete(&models.RolePermission{}).Error; err != nil {
+		return fmt.Errorf("clear role permissions: %w", err)
+	}
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	rows := make([]models.RolePermission, 0, len(permissions))
+	for _, permission := range permissions {
+		rows = append(rows, models.RolePermission{Role: name, Permission: permission})
+	}
+	if err := db.Create(&rows).Error; err != nil {
+		return fmt.Errorf("add role permissions: %w", err)
+	}
+	return nil
+}
+
+func (r *GormRoleRepository) Delete(ctx context.Context, name string) error {
+	result := conn(ctx, r.db).Where("name = ?", name).Delete(&models.RoleDefinition{})
+	if result.Error != nil {
+		return fmt.Errorf("delete role: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *GormRoleRepository) CountUsers(ctx context.Context, name string) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.db).Model(&models.User{}).Where("role = ?", name).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("count role users: %w", err)
+	}
+	return count, nil
+}
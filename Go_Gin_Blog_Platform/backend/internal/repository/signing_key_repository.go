@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type SigningKeyRepository interface {
+	// ListUsable returns the active and previous keys, newest first.
+	ListUsable(ctx context.Context) ([]models.SigningKey, error)
+	// List returns every key including retired ones, newest first.
+	List(ctx context.Context) ([]models.SigningKey, error)
+	// Create stores a key. Creating a second active key returns ErrDuplicate.
+	Create(ctx context.Context, key *models.SigningKey) error
+	// DemoteActive moves the active key, if any, to previous.
+	DemoteActive(ctx context.Context, at time.Time) error
+	// RetirePreviousBefore retires previous keys rotated out before cutoff.
+	RetirePreviousBefore(ctx context.Context, cutoff, at time.Time) (int64, error)
+	// Retire retires one previous key, returning ErrNotFound if there is no
+	// previous key with that id.
+	Retire(ctx context.Context, id string, at time.Time) error
+}
+
+type GormSigningKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewSigningKeyRepository(db *gorm.DB) *GormSigningKeyRepository {
+	return &GormSigningKeyRepository{db: db}
+}
+
+func (r *GormSigningKeyRepository) ListUsable(ctx context.Context) ([]models.SigningKey, error) {
+	var keys []models.SigningKey
+	err := conn(ctx, r.db).
+		Where("status IN ?", []models.SigningKeyStatus{models.SigningKeyActive, models.SigningKeyPrevious}).
+		Order("created_at DESC").
+		Find(&keys).Error
+	if err != nil {
+		return nil, fmt.Errorf("list usable signing keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (r *GormSigningKeyRepository) List(ctx context.Context) ([]models.SigningKey, error) {
+	var keys []models.SigningKey
+	if err := conn(ctx, r.db).Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("list signing keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (r *GormSigningKeyRepository) Create(ctx context.Context, key *models.SigningKey) error {
+	if err := conn(ctx, r.db).Create(key).Error; err != nil {
+		if isDuplicateError(err) {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("create signing key: %w", err)
+	}
+	return nil
+}
+
+func (r *GormSigningKeyRepository) DemoteActive(ctx context.Context, at time.Time) error {
+	err := conn(ctx, r.db).
+		Model(&models.SigningKey{}).
+		Where("status = ?", models.SigningKeyActive).
+		Updates(map[string]any{"status": models.SigningKeyPrevious, "rotated_at": at}).Error
+	if err != nil {
+		return fmt.Errorf("demote active signing key: %w", err)
+	}
+	return nil
+}
+
+func (r *GormSigningKeyRepository) RetirePreviousBefore(ctx context.Context, cutoff, at time.Time) (int64, error) {
+	result := conn(ctx, r.db).
+		Model(&models.SigningKey{}).
+		Where("status = ?", models.SigningKeyPrevious).
+		Where("rotated_at < ?", cutoff).
+		Updates(map[string]any{"status": models.SigningKeyRetired, "retired_at": at})
+	if result.Error != nil {
+		return 0, fmt.Errorf("retire previous signing keys: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *GormSigningKeyRepository) Retire(ctx context.Context, id string, at time.Time) error {
+	result := conn(ctx, r.db).
+		Model(&models.SigningKey{}).
+		Where("id = ?", id).
+		Where("status = ?", models.SigningKeyPrevious).
+		Updates(map[string]any{"status": models.SigningKeyRetired, "retired_at": at})
+	if result.Error != nil {
+		return fmt.Errorf("retire signing key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagCount struct {
+	models.Tag
+	PostCount int64
+}
+
+type TaxonomyRepository interface {
+	UpsertTags(ctx context.Context, tags []models.Tag) ([]models.Tag, error)
+	ReplacePostTags(ctx context.Context, postID string, tagIDs []string) error
+	ListTagsWithCounts(ctx context.Context) ([]TagCount, error)
+	GetTagByID(ctx context.Context, id string) (*models.Tag, error)
+	RenameTag(ctx context.Context, id, name, slug string) error
+	MergeTags(ctx context.Context, sourceID, targetID string) error
+	CreateCategory(ctx context.Context, category *models.Category) error
+	GetCategoryByID(ctx context.Context, id string) (*models.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*models.Category, error)
+	ListCategories(ctx context.Context) ([]models.Category, error)
+}
+
+type GormTaxonomyRepository struct {
+	db *gorm.DB
+}
+
+func NewTaxonomyRepository(db *gorm.DB) *GormTaxonomyRepository {
+	return &GormTaxonomyRepository{db: db}
+}
+
+// UpsertTags creates any tags whose slug does not exist yet and returns the
+// stored rows for every requested slug.
+func (r *GormTaxonomyRepository) UpsertTags(ctx context.Context, tags []models.Tag) ([]models.Tag, error) {
+	if len(tags) == 0 {
+		return []models.Tag{}, nil
+	}
+
+	err := conn(ctx, r.db).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "slug"}}, DoNothing: true}).
+		Create(&tags).Error
+	if err != nil {
+		return nil, fmt.Errorf("upsert tags: %w", err)
+	}
+
+	slugs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		slugs = append(slugs, tag.Slug)
+	}
+
+	var stored []models.Tag
+	if err := conn(ctx, r.db).Where("slug IN ?", slugs).Order("name").Find(&stored).Error; err != nil {
+		return nil, fmt.Errorf("load upserted tags: %w", err)
+	}
+	return stored, nil
+}
+
+func (r *GormTaxonomyRepository) ReplacePostTags(ctx context.Context, postID string, tagIDs []string) error {
+	db := conn(ctx, r.db)
+	if err := db.Exec("DELETE FROM post_tags WHERE post_id = ?", postID).Error; err != nil {
+		return fmt.Errorf("clear post tags: %w", err)
+	}
+	for _, tagID := range tagIDs {
+		if err := db.Exec("INSERT INTO post_tags (post_id, tag_id) VALUES (?, ?) ON CONFLICT DO NOTHING", postID, tagID).Error; err != nil {
+			return fmt.Errorf("attach post tag: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *GormTaxonomyRepository) ListTagsWithCounts(ctx context.Context) ([]TagCount, error) {
+	var counts []TagCount
+	err := conn(ctx, r.db).
+		Table("tags").
+		Select("tags.*, COUNT(posts.id) AS post_count").
+		Joins("LEFT JOIN post_tags ON post_tags.tag_id = tags.id").
+		Joins("LEFT JOIN posts ON posts.id = post_tags.post_id AND posts.status = ? AND posts.deleted_at IS NULL", models.PostStatusPublished).
+		Group("tags.id").
+		Order("post_count desc, tags.name").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("list tags with counts: %w", err)
+	}
+	return counts, nil
+}
+
+func (r *GormTaxonomyRepository) GetTagByID(ctx context.Context, id string) (*models.Tag, error) {
+	var tag models.Tag
+	err := conn(ctx, r.db).Where("id = ?", id).First(&tag).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get tag by id: %w", err)
+	}
+	return &tag, nil
+}
+
+func (r *GormTaxonomyRepository) RenameTag(ctx context.Context, id, name, slug string) error {
+	result := conn(ctx, r.db).
+		Model(&models.Tag{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"name": name, "slug": slug})
+
+	if result.Error != nil {
+		if isDuplicateError(result.Error) {
+			return fmt.Errorf("rename tag: %w", ErrDuplicate)
+		}
+		return fmt.Errorf("rename tag: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// MergeTags moves every post tagged with sourceID onto targetID and removes the
+// source tag.
+func (r *GormTaxonomyRepository) MergeTags(ctx context.Context, sourceID, targetID string) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`INSERT INTO post_tags (post_id, tag_id)
+			SELECT post_id, ? FROM post_tags WHERE tag_id = ?
+			ON CONFLICT DO NOTHING`, targetID, sourceID).Error
+		if err != nil {
+			return fmt.Errorf("move merged tag posts: %w", err)
+		}
+
+		result := tx.Where("id = ?", sourceID).Delete(&models.Tag{})
+		if result.Error != nil {
+			return fmt.Errorf("delete merged tag: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *GormTaxonomyRepository) CreateCategory(ctx context.Context, category *models.Category) error {
+	if err := conn(ctx, r.db).Create(category).Error; err != nil {
+		if isDuplicateError(err) {
+			return fmt.Errorf("create category: %w", ErrDuplicate)
+		}
+		return fmt.Errorf("create category: %w", err)
+	}
+	return nil
+}
+
+func (r *GormTaxonomyRepository) GetCategoryByID(ctx context.Context, id string) (*models.Category, error) {
+	var category models.Category
+	err := conn(ctx, r.db).Where("id = ?", id).First(&category).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get category by id: %w", err)
+	}
+	return &category, nil
+}
+
+func (r *GormTaxonomyRepository) GetCategoryBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	var category models.Category
+	err := conn(ctx, r.db).Where("slug = ?", slug).First(&category).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get category by slug: %w", err)
+	}
+	return &category, nil
+}
+
+func (r *GormTaxonomyRepository) ListCategories(ctx context.Context) ([]models.Category, error) {
+	var categories []models.Category
+	if err := conn(ctx, r.db).Order("name").Find(&categories).Error; err != nil {
+		return nil, fmt.Errorf("list categories: %w", err)
+	}
+	return categories, nil
+}
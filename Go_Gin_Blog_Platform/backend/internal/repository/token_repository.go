@@ -8,18 +8,46 @@ import (
 
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type RefreshTokenRepository interface {
 	Create(ctx context.Context, token *models.RefreshToken) error
-	GetActiveByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	// GetByHashForUpdate returns the token whether or not it is still active,
+	// locking its row for the rest of the surrounding transaction.
+	GetByHashForUpdate(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
 	RevokeByHash(ctx context.Context, tokenHash string) error
+	RevokeFamily(ctx context.Context, familyID string) (int64, error)
+	// ListActiveByUser returns the user's unrevoked, unexpired tokens, one per
+	// session, most recently used first.
+	ListActiveByUser(ctx context.Context, userID string) ([]models.RefreshToken, error)
+	RevokeAllForUser(ctx context.Context, userID string) (int64, error)
 }
 
 type PasswordResetTokenRepository interface {
 	Create(ctx context.Context, token *models.PasswordResetToken) error
 	GetActiveByHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error)
 	MarkUsedByID(ctx context.Context, tokenID string) error
+	// InvalidateAllForUser marks every unused reset token of the user as used.
+	InvalidateAllForUser(ctx context.Context, userID string) (int64, error)
+}
+
+type EmailChangeTokenRepository interface {
+	Create(ctx context.Context, token *models.EmailChangeToken) error
+	GetActiveByHash(ctx context.Context, tokenHash string) (*models.EmailChangeToken, error)
+	MarkUsedByID(ctx context.Context, tokenID string) error
+	// InvalidateAllForUser marks every unused email change token of the user
+	// as used.
+	InvalidateAllForUser(ctx context.Context, userID string) (int64, error)
+}
+
+type EmailVerificationTokenRepository interface {
+	Create(ctx context.Context, token *models.EmailVerificationToken) error
+	GetActiveByHash(ctx context.Context, tokenHash string) (*models.EmailVerificationToken, error)
+	MarkUsedByID(ctx context.Context, tokenID string) error
+	// InvalidateAllForUser marks every unused verification token of the user
+	// as used.
+	InvalidateAllForUser(ctx context.Context, userID string) (int64, error)
 }
 
 type GormRefreshTokenRepository struct {
@@ -30,6 +58,14 @@ type GormPasswordResetTokenRepository struct {
 	db *gorm.DB
 }
 
+type GormEmailChangeTokenRepository struct {
+	db *gorm.DB
+}
+
+type GormEmailVerificationTokenRepository struct {
+	db *gorm.DB
+}
+
 func NewRefreshTokenRepository(db *gorm.DB) *GormRefreshTokenRepository {
 	return &GormRefreshTokenRepository{db: db}
 }
@@ -38,32 +74,39 @@ func NewPasswordResetTokenRepository(db *gorm.DB) *GormPasswordResetTokenReposit
 	return &GormPasswordResetTokenRepository{db: db}
 }
 
+func NewEmailChangeTokenRepository(db *gorm.DB) *GormEmailChangeTokenRepository {
+	return &GormEmailChangeTokenRepository{db: db}
+}
+
+func NewEmailVerificationTokenRepository(db *gorm.DB) *GormEmailVerificationTokenRepository {
+	return &GormEmailVerificationTokenRepository{db: db}
+}
+
 func (r *GormRefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
-	if err := r.db.WithContext(ctx).Create(token).Error; err != nil {
+	if err := conn(ctx, r.db).Create(token).Error; err != nil {
 		return fmt.Errorf("create refresh token: %w", err)
 	}
 	return nil
 }
 
-func (r *GormRefreshTokenRepository) GetActiveByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+func (r *GormRefreshTokenRepository) GetByHashForUpdate(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
 	var token models.RefreshToken
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
 		Where("token_hash = ?", tokenHash).
-		Where("revoked_at IS NULL").
-		Where("expires_at > ?", time.Now().UTC()).
 		First(&token).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrNotFound
 		}
-		return nil, fmt.Errorf("get active refresh token by hash: %w", err)
+		return nil, fmt.Errorf("get refresh token by hash: %w", err)
 	}
 	return &token, nil
 }
 
 func (r *GormRefreshTokenRepository) RevokeByHash(ctx context.Context, tokenHash string) error {
 	now := time.Now().UTC()
-	result := r.db.WithContext(ctx).
+	result := conn(ctx, r.db).
 		Model(&models.RefreshToken{}).
 		Where("token_hash = ?", tokenHash).
 		Where("revoked_at IS NULL").
@@ -78,8 +121,50 @@ func (r *GormRefreshTokenRepository) RevokeByHash(ctx context.Context, tokenHash
 	return nil
 }
 
+func (r *GormRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) (int64, error) {
+	now := time.Now().UTC()
+	result := conn(ctx, r.db).
+		Model(&models.RefreshToken{}).
+		Where("family_id = ?", familyID).
+		Where("revoked_at IS NULL").
+		Updates(map[string]any{"revoked_at": &now})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("revoke refresh token family: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *GormRefreshTokenRepository) ListActiveByUser(ctx context.Context, userID string) ([]models.RefreshToken, error) {
+	var tokens []models.RefreshToken
+	err := conn(ctx, r.db).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Where("expires_at > ?", time.Now().UTC()).
+		Order("last_used_at DESC").
+		Find(&tokens).Error
+	if err != nil {
+		return nil, fmt.Errorf("list active refresh tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+func (r *GormRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID string) (int64, error) {
+	now := time.Now().UTC()
+	result := conn(ctx, r.db).
+		Model(&models.RefreshToken{}).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Updates(map[string]any{"revoked_at": &now})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("revoke user refresh tokens: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
 func (r *GormPasswordResetTokenRepository) Create(ctx context.Context, token *models.PasswordResetToken) error {
-	if err := r.db.WithContext(ctx).Create(token).Error; err != nil {
+	if err := conn(ctx, r.db).Create(token).Error; err != nil {
 		return fmt.Errorf("create password reset token: %w", err)
 	}
 	return nil
@@ -87,7 +172,7 @@ func (r *GormPasswordResetTokenRepository) Create(ctx context.Context, token *mo
 
 func (r *GormPasswordResetTokenRepository) GetActiveByHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error) {
 	var token models.PasswordResetToken
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Where("token_hash = ?", tokenHash).
 		Where("used_at IS NULL").
 		Where("expires_at > ?", time.Now().UTC()).
@@ -103,7 +188,7 @@ func (r *GormPasswordResetTokenRepository) GetActiveByHash(ctx context.Context,
 
 func (r *GormPasswordResetTokenRepository) MarkUsedByID(ctx context.Context, tokenID string) error {
 	now := time.Now().UTC()
-	result := r.db.WithContext(ctx).
+	result := conn(ctx, r.db).
 		Model(&models.PasswordResetToken{}).
 		Where("id = ?", tokenID).
 		Where("used_at IS NULL").
@@ -117,3 +202,125 @@ func (r *GormPasswordResetTokenRepository) MarkUsedByID(ctx context.Context, tok
 	}
 	return nil
 }
+
+func (r *GormPasswordResetTokenRepository) InvalidateAllForUser(ctx context.Context, userID string) (int64, error) {
+	now := time.Now().UTC()
+	result := conn(ctx, r.db).
+		Model(&models.PasswordResetToken{}).
+		Where("user_id = ?", userID).
+		Where("used_at IS NULL").
+		Updates(map[string]any{"used_at": &now})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("invalidate password reset tokens: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *GormEmailChangeTokenRepository) Create(ctx context.Context, token *models.EmailChangeToken) error {
+	if err := conn(ctx, r.db).Create(token).Error; err != nil {
+		return fmt.Errorf("create email change token: %w", err)
+	}
+	return nil
+}
+
+func (r *GormEmailChangeTokenRepository) GetActiveByHash(ctx context.Context, tokenHash string) (*models.EmailChangeToken, error) {
+	var token models.EmailChangeToken
+	err := conn(ctx, r.db).
+		Where("token_hash = ?", tokenHash).
+		Where("used_at IS NULL").
+		Where("expires_at > ?", time.Now().UTC()).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get active email change token by hash: %w", err)
+	}
+	return &token, nil
+}
+
+func (r *GormEmailChangeTokenRepository) MarkUsedByID(ctx context.Context, tokenID string) error {
+	now := time.Now().UTC()
+	result := conn(ctx, r.db).
+		Model(&models.EmailChangeToken{}).
+		Where("id = ?", tokenID).
+		Where("used_at IS NULL").
+		Updates(map[string]any{"used_at": &now})
+
+	if result.Error != nil {
+		return fmt.Errorf("mark email change token used: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *GormEmailChangeTokenRepository) InvalidateAllForUser(ctx context.Context, userID string) (int64, error) {
+	now := time.Now().UTC()
+	result := conn(ctx, r.db).
+		Model(&models.EmailChangeToken{}).
+		Where("user_id = ?", userID).
+		Where("used_at IS NULL").
+		Updates(map[string]any{"used_at": &now})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("invalidate email change tokens: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *GormEmailVerificationTokenRepository) Create(ctx context.Context, token *models.EmailVerificationToken) error {
+	if err := conn(ctx, r.db).Create(token).Error; err != nil {
+		return fmt.Errorf("create email verification token: %w", err)
+	}
+	return nil
+}
+
+func (r *GormEmailVerificationTokenRepository) GetActiveByHash(ctx context.Context, tokenHash string) (*models.EmailVerificationToken, error) {
+	var token models.EmailVerificationToken
+	err := conn(ctx, r.db).
+		Where("token_hash = ?", tokenHash).
+		Where("used_at IS NULL").
+		Where("expires_at > ?", time.Now().UTC()).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get active email verification token by hash: %w", err)
+	}
+	return &token, nil
+}
+
+func (r *GormEmailVerificationTokenRepository) MarkUsedByID(ctx context.Context, tokenID string) error {
+	now := time.Now().UTC()
+	result := conn(ctx, r.db).
+		Model(&models.EmailVerificationToken{}).
+		Where("id = ?", tokenID).
+		Where("used_at IS NULL").
+		Updates(map[string]any{"used_at": &now})
+
+	if result.Error != nil {
+		return fmt.Errorf("mark email verification token used: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *GormEmailVerificationTokenRepository) InvalidateAllForUser(ctx context.Context, userID string) (int64, error) {
+	now := time.Now().UTC()
+	result := conn(ctx, r.db).
+		Model(&models.EmailVerificationToken{}).
+		Where("user_id = ?", userID).
+		Where("used_at IS NULL").
+		Updates(map[string]any{"used_at": &now})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("invalidate email verification tokens: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
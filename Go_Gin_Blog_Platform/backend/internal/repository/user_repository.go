@@ -19,6 +19,8 @@ type UserRepository interface {
 	Count(ctx context.Context) (int64, error)
 	UpdateRole(ctx context.Context, id string, role models.Role) error
 	UpdatePasswordHash(ctx context.Context, id, passwordHash string) error
+	UpdateEmail(ctx context.Context, id, email string) error
+	MarkEmailVerified(ctx context.Context, id string, at time.Time) error
 }
 
 type GormUserRepository struct {
@@ -30,8 +32,8 @@ func NewUserRepository(db *gorm.DB) *GormUserRepository {
 }
 
 func (r *GormUserRepository) Create(ctx context.Context, user *models.User) error {
-	if err := r.db.WithContext(ctx).Create(user).Error; err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "duplicate") || strings.Contains(strings.ToLower(err.Error()), "unique") {
+	if err := conn(ctx, r.db).Create(user).Error; err != nil {
+		if isDuplicateError(err) {
 			return fmt.Errorf("create user: %w", ErrDuplicate)
 		}
 		return fmt.Errorf("create user: %w", err)
@@ -41,7 +43,7 @@ func (r *GormUserRepository) Create(ctx context.Context, user *models.User) erro
 
 func (r *GormUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	err := r.db.WithContext(ctx).Where("email = ?", strings.ToLower(strings.TrimSpace(email))).First(&user).Error
+	err := conn(ctx, r.db).Where("email = ?", strings.ToLower(strings.TrimSpace(email))).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrNotFound
@@ -53,7 +55,7 @@ func (r *GormUserRepository) GetByEmail(ctx context.Context, email string) (*mod
 
 func (r *GormUserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
 	var user models.User
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&user).Error
+	err := conn(ctx, r.db).Where("id = ?", id).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrNotFound
@@ -75,7 +77,7 @@ func (r *GormUserRepository) List(ctx context.Context, limit, offset int) ([]mod
 	}
 
 	var users []models.User
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Order("created_at desc").
 		Limit(limit).
 		Offset(offset).
@@ -89,14 +91,14 @@ func (r *GormUserRepository) List(ctx context.Context, limit, offset int) ([]mod
 
 func (r *GormUserRepository) Count(ctx context.Context) (int64, error) {
 	var total int64
-	if err := r.db.WithContext(ctx).Model(&models.User{}).Count(&total).Error; err != nil {
+	if err := conn(ctx, r.db).Model(&models.User{}).Count(&total).Error; err != nil {
 		return 0, fmt.Errorf("count users: %w", err)
 	}
 	return total, nil
 }
 
 func (r *GormUserRepository) UpdateRole(ctx context.Context, id string, role models.Role) error {
-	result := r.db.WithContext(ctx).
+	result := conn(ctx, r.db).
 		Model(&models.User{}).
 		Where("id = ?", id).
 		Updates(map[string]any{
@@ -114,7 +116,7 @@ func (r *GormUserRepository) UpdateRole(ctx context.Context, id string, role mod
 }
 
 func (r *GormUserRepository) UpdatePasswordHash(ctx context.Context, id, passwordHash string) error {
-	result := r.db.WithContext(ctx).
+	result := conn(ctx, r.db).
 		Model(&models.User{}).
 		Where("id = ?", id).
 		Updates(map[string]any{
@@ -130,3 +132,42 @@ func (r *GormUserRepository) UpdatePasswordHash(ctx context.Context, id, passwor
 	}
 	return nil
 }
+
+func (r *GormUserRepository) UpdateEmail(ctx context.Context, id, email string) error {
+	result := conn(ctx, r.db).
+		Model(&models.User{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"email":      strings.ToLower(strings.TrimSpace(email)),
+			"updated_at": time.Now().UTC(),
+		})
+
+	if result.Error != nil {
+		if isDuplicateError(result.Error) {
+			return fmt.Errorf("update email: %w", ErrDuplicate)
+		}
+		return fmt.Errorf("update email: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *GormUserRepository) MarkEmailVerified(ctx context.Context, id string, at time.Time) error {
+	result := conn(ctx, r.db).
+		Model(&models.User{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"email_verified_at": at,
+			"updated_at":        time.Now().UTC(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("mark email verified: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/email"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+var ErrIncorrectPassword = errors.New("current password is incorrect")
+
+type ChangePasswordInput struct {
+	UserID          string
+	SessionID       string
+	CurrentPassword string
+	NewPassword     string
+}
+
+type RequestEmailChangeInput struct {
+	UserID          string
+	CurrentPassword string
+	NewEmail        string
+}
+
+type ConfirmEmailChangeInput struct {
+	Token string
+}
+
+// ChangePassword replaces the signed-in user's password. Every other session is
+// signed out, personal access tokens are revoked and outstanding reset links
+// are voided; the calling session (SessionID) stays signed in.
+func (s *AuthService) ChangePassword(ctx context.Context, input ChangePasswordInput) error {
+	if strings.TrimSpace(input.UserID) == "" || len(input.NewPassword) < 8 {
+		return fmt.Errorf("change password input invalid: %w", ErrValidation)
+	}
+
+	user, err := s.verifyCurrentPassword(ctx, input.UserID, input.CurrentPassword)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := auth.HashPassword(input.NewPassword)
+	if err != nil {
+		return fmt.Errorf("hash new password: %w", err)
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.users.UpdatePasswordHash(ctx, user.ID, hashedPassword); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrUserNotFound
+			}
+			return fmt.Errorf("update password hash: %w", err)
+		}
+
+		sessions, err := s.refreshTokens.ListActiveByUser(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("list sessions: %w", err)
+		}
+		for _, session := range sessions {
+			if session.FamilyID == input.SessionID {
+				continue
+			}
+			if _, err := s.refreshTokens.RevokeFamily(ctx, session.FamilyID); err != nil {
+				return fmt.Errorf("revoke other sessions: %w", err)
+			}
+		}
+		if _, err := s.pats.RevokeAllForUser(ctx, user.ID, s.now()); err != nil {
+			return fmt.Errorf("revoke personal access tokens: %w", err)
+		}
+
+		if _, err := s.resetTokens.InvalidateAllForUser(ctx, user.ID); err != nil {
+			return fmt.Errorf("invalidate reset tokens: %w", err)
+		}
+		return s.audit.Record(ctx, AuditRecord{
+			ActorID:    user.ID,
+			Action:     AuditPasswordChange,
+			TargetType: AuditTargetUser,
+			TargetID:   user.ID,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.sendAccountNotice(ctx, user.Email, "Your password was changed",
+		"The password for your account was just changed and your other devices were signed out. "+
+			"If this wasn't you, reset your password now: "+s.frontendBaseURL+"/forgot-password")
+	return nil
+}
+
+// RequestEmailChange sends a confirmation link to the new address; the email
+// only changes once the link is used. The current address is told about the
+// request.
+func (s *AuthService) RequestEmailChange(ctx context.Context, input RequestEmailChangeInput) error {
+	newEmail := normalizeEmail(input.NewEmail)
+	if strings.TrimSpace(input.UserID) == "" || !isValidEmail(newEmail) {
+		return fmt.Errorf("email change input invalid: %w", ErrValidation)
+	}
+
+	user, err := s.verifyCurrentPassword(ctx, input.UserID, input.CurrentPassword)
+	if err != nil {
+		return err
+	}
+	if newEmail == user.Email {
+		return fmt.Errorf("new email matches the current one: %w", ErrValidation)
+	}
+
+	if _, err := s.users.GetByEmail(ctx, newEmail); err == nil {
+		return ErrEmailAlreadyUsed
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("check new email: %w", err)
+	}
+
+	rawToken, err := auth.GenerateRandomToken(32)
+	if err != nil {
+		return fmt.Errorf("generate email change token: %w", err)
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.emailChanges.InvalidateAllForUser(ctx, user.ID); err != nil {
+			return fmt.Errorf("invalidate previous email change tokens: %w", err)
+		}
+		if err := s.emailChanges.Create(ctx, &models.EmailChangeToken{
+			UserID:    user.ID,
+			NewEmail:  newEmail,
+			TokenHash: auth.HashToken(rawToken),
+			ExpiresAt: s.now().Add(s.passwordResetTTL),
+		}); err != nil {
+			return fmt.Errorf("store email change token: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	confirmURL := s.frontendBaseURL + "/confirm-email-change?token=" + url.QueryEscape(rawToken)
+	s.sendAccountNotice(ctx, newEmail, "Confirm your new email address",
+		"Use this link to confirm your new email address: "+confirmURL)
+	s.sendAccountNotice(ctx, user.Email, "Email change requested",
+		"A request was made to change the email on your account to "+newEmail+". "+
+			"If this wasn't you, change your password now: "+s.frontendBaseURL+"/forgot-password")
+	return nil
+}
+
+// ConfirmEmailChange applies the change a confirmation link was sent for, voids
+// password reset links sent to the previous address and tells that address it
+// no longer signs in to the account.
+func (s *AuthService) ConfirmEmailChange(ctx context.Context, input ConfirmEmailChangeInput) error {
+	rawToken := strings.TrimSpace(input.Token)
+	if rawToken == "" {
+		return fmt.Errorf("email change confirm invalid: %w", ErrValidation)
+	}
+
+	storedToken, err := s.emailChanges.GetActiveByHash(ctx, auth.HashToken(rawToken))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrInvalidToken
+		}
+		return fmt.Errorf("load email change token: %w", err)
+	}
+
+	var previousEmail string
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.emailChanges.MarkUsedByID(ctx, storedToken.ID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrInvalidToken
+			}
+			return fmt.Errorf("mark email change token used: %w", err)
+		}
+
+		user, err := s.users.GetByID(ctx, storedToken.UserID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrInvalidToken
+			}
+			return fmt.Errorf("get user for email change: %w", err)
+		}
+		previousEmail = user.Email
+
+		if err := s.users.UpdateEmail(ctx, user.ID, storedToken.NewEmail); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				return ErrEmailAlreadyUsed
+			}
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrInvalidToken
+			}
+			return fmt.Errorf("update email: %w", err)
+		}
+		// Following the link proved the user controls the new address.
+		if err := s.users.MarkEmailVerified(ctx, user.ID, s.now()); err != nil {
+			return fmt.Errorf("mark new email verified: %w", err)
+		}
+		// Reset links sent to the old address must not outlive the change.
+		if _, err := s.resetTokens.InvalidateAllForUser(ctx, user.ID); err != nil {
+			return fmt.Errorf("invalidate reset tokens: %w", err)
+		}
+		return s.audit.Record(ctx, AuditRecord{
+			ActorID:    user.ID,
+			Action:     AuditEmailChange,
+			TargetType: AuditTargetUser,
+			TargetID:   user.ID,
+			Before:     map[string]any{"email": previousEmail},
+			After:      map[string]any{"email": storedToken.NewEmail},
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.sendAccountNotice(ctx, previousEmail, "Your account email was changed",
+		"The email on your account was changed to "+storedToken.NewEmail+". "+
+			"If this wasn't you, contact support right away.")
+	return nil
+}
+
+// verifyCurrentPassword checks the password a signed-in user re-entered. Wrong
+// guesses count towards the same lockout as failed sign-ins, so a stolen
+// session cannot be used to brute-force the password.
+func (s *AuthService) verifyCurrentPassword(ctx context.Context, userID, password string) (*models.User, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	if err := s.checkLocked(ctx, userThrottleKey(user.ID)); err != nil {
+		return nil, err
+	}
+	if !auth.VerifyPassword(user.PasswordHash, password) {
+		if err := s.recordLoginFailure(ctx, user, ""); err != nil {
+			return nil, err
+		}
+		return nil, ErrIncorrectPassword
+	}
+	if err := s.throttles.Clear(ctx, userThrottleKey(user.ID)); err != nil {
+		return nil, fmt.Errorf("reset login failures: %w", err)
+	}
+	return user, nil
+}
+
+func (s *AuthService) sendAccountNotice(ctx context.Context, to, subject, body string) {
+	if err := s.emailSender.Send(ctx, email.Message{To: to, Subject: subject, Body: body}); err != nil {
+		s.logger.Error("account notice email send failed", "error", err, "email", to, "subject", subject)
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuthServiceChangePasswordKeepsCurrentSession(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+
+	_, current, err := f.svc.Login(ctx, LoginInput{Email: "alice@example.com", Password: "correct-horse"})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	_, other, err := f.svc.Login(ctx, LoginInput{Email: "alice@example.com", Password: "correct-horse"})
+	if err != nil {
+		t.Fatalf("second login: %v", err)
+	}
+	currentSession := f.refresh.tokens[0].FamilyID
+
+	err = f.svc.ChangePassword(ctx, ChangePasswordInput{UserID: "u1", SessionID: currentSession, CurrentPassword: "wrong", NewPassword: "battery-staple"})
+	if !errors.Is(err, ErrIncorrectPassword) {
+		t.Fatalf("expected ErrIncorrectPassword, got %v", err)
+	}
+
+	if err := f.svc.ChangePassword(ctx, ChangePasswordInput{UserID: "u1", SessionID: currentSession, CurrentPassword: "correct-horse", NewPassword: "battery-staple"}); err != nil {
+		t.Fatalf("change password: %v", err)
+	}
+
+	if _, err := f.svc.Refresh(ctx, RefreshInput{RefreshToken: current.RefreshToken}); err != nil {
+		t.Fatalf("expected the current session to stay signed in: %v", err)
+	}
+	if _, err := f.svc.Refresh(ctx, RefreshInput{RefreshToken: other.RefreshToken}); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected other sessions to be revoked, got %v", err)
+	}
+	if len(f.emails.sent) != 1 || f.emails.sent[0].To != "alice@example.com" {
+		t.Fatalf("expected a notice to the account address, got %+v", f.emails.sent)
+	}
+	if _, _, err := f.svc.Login(ctx, LoginInput{Email: "alice@example.com", Password: "battery-staple"}); err != nil {
+		t.Fatalf("expected login with the new password: %v", err)
+	}
+}
+
+func TestAuthServiceEmailChangeFlow(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	f.users.users = append(f.users.users, f.users.users[0])
+	f.users.users[1].ID, f.users.users[1].Email = "u2", "bob@example.com"
+
+	taken := RequestEmailChangeInput{UserID: "u1", CurrentPassword: "correct-horse", NewEmail: "bob@example.com"}
+	if err := f.svc.RequestEmailChange(ctx, taken); !errors.Is(err, ErrEmailAlreadyUsed) {
+		t.Fatalf("expected ErrEmailAlreadyUsed, got %v", err)
+	}
+
+	request := RequestEmailChangeInput{UserID: "u1", CurrentPassword: "correct-horse", NewEmail: " Alice@New.example.com "}
+	if err := f.svc.RequestEmailChange(ctx, request); err != nil {
+		t.Fatalf("request email change: %v", err)
+	}
+	if len(f.emails.sent) != 2 || f.emails.sent[0].To != "alice@new.example.com" || f.emails.sent[1].To != "alice@example.com" {
+		t.Fatalf("expected a confirmation to the new address and a notice to the old one, got %+v", f.emails.sent)
+	}
+	if user, _ := f.users.GetByID(ctx, "u1"); user.Email != "alice@example.com" {
+		t.Fatalf("expected the email to stay unchanged until confirmed, got %s", user.Email)
+	}
+
+	_, token, _ := strings.Cut(f.emails.sent[0].Body, "token=")
+	if err := f.svc.RequestPasswordReset(ctx, RequestResetInput{Email: "alice@example.com"}); err != nil {
+		t.Fatalf("request password reset: %v", err)
+	}
+	_, resetToken, _ := strings.Cut(f.emails.sent[len(f.emails.sent)-1].Body, "token=")
+	if err := f.svc.ConfirmEmailChange(ctx, ConfirmEmailChangeInput{Token: token}); err != nil {
+		t.Fatalf("confirm email change: %v", err)
+	}
+	if user, _ := f.users.GetByID(ctx, "u1"); user.Email != "alice@new.example.com" {
+		t.Fatalf("expected the new email, got %s", user.Email)
+	}
+	if last := f.emails.sent[len(f.emails.sent)-1]; last.To != "alice@example.com" || last.Subject != "Your account email was changed" {
+		t.Fatalf("expected a change notice to the old address, got %+v", last)
+	}
+
+	if err := f.svc.ConfirmEmailChange(ctx, ConfirmEmailChangeInput{Token: token}); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected a used token to be rejected, got %v", err)
+	}
+	if err := f.svc.ConfirmPasswordReset(ctx, ConfirmResetInput{Token: resetToken, NewPassword: "battery-staple"}); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected a reset link sent to the old address to stop working, got %v", err)
+	}
+}
+
+func TestAuthServiceCurrentPasswordChecksCountTowardsLockout(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	change := ChangePasswordInput{UserID: "u1", CurrentPassword: "guess", NewPassword: "battery-staple"}
+
+	for i := 0; i < 2; i++ {
+		if err := f.svc.ChangePassword(ctx, change); !errors.Is(err, ErrIncorrectPassword) {
+			t.Fatalf("guess %d: expected ErrIncorrectPassword, got %v", i+1, err)
+		}
+	}
+	if err := f.svc.RequestEmailChange(ctx, RequestEmailChangeInput{UserID: "u1", CurrentPassword: "guess", NewEmail: "mallory@example.com"}); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("expected the third wrong password to lock the account, got %v", err)
+	}
+
+	change.CurrentPassword = "correct-horse"
+	if err := f.svc.ChangePassword(ctx, change); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("expected checks to be refused while locked, got %v", err)
+	}
+	if err := f.login("correct-horse"); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("expected sign-in to be locked too, got %v", err)
+	}
+	if len(f.emails.sent) != 1 || f.emails.sent[0].Subject != "Your account has been temporarily locked" {
+		t.Fatalf("expected a lock notice, got %+v", f.emails.sent)
+	}
+
+	*f.clock = f.clock.Add(2 * time.Minute)
+	if err := f.svc.ChangePassword(ctx, change); err != nil {
+		t.Fatalf("expected the change to work once the lock expired: %v", err)
+	}
+}
@@ -7,15 +7,26 @@ import (
 	"strings"
 
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/policy"
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
 )
 
 type AdminService struct {
-	users repository.UserRepository
+	users     repository.UserRepository
+	throttles repository.LoginThrottleRepository
+	audit     *AuditService
+	tx        repository.Transactor
+	policy    *policy.Policy
 }
 
-func NewAdminService(users repository.UserRepository) *AdminService {
-	return &AdminService{users: users}
+func NewAdminService(
+	users repository.UserRepository,
+	throttles repository.LoginThrottleRepository,
+	audit *AuditService,
+	tx repository.Transactor,
+	authz *policy.Policy,
+) *AdminService {
+	return &AdminService{users: users, throttles: throttles, audit: audit, tx: tx, policy: authz}
 }
 
 type UserSummary struct {
@@ -47,17 +58,44 @@ func (s *AdminService) ListUsers(ctx context.Context, page, limit int) ([]UserSu
 	return summaries, Pagination{Page: page, Limit: limit, Total: total, TotalPages: totalPages}, nil
 }
 
-func (s *AdminService) UpdateUserRole(ctx context.Context, userID, role string) (UserSummary, error) {
-	normalizedRole, err := normalizeRole(role)
-	if err != nil {
-		return UserSummary{}, err
+// UpdateUserRole gives a user another role. The actor's role has to hold
+// every permission of both the user's current role and the new one, so the
+// change cannot be used to gain permissions.
+func (s *AdminService) UpdateUserRole(ctx context.Context, actorID, actorRole, userID, role string) (UserSummary, error) {
+	normalizedRole := models.Role(strings.ToLower(strings.TrimSpace(role)))
+	if !s.policy.RoleExists(string(normalizedRole)) {
+		return UserSummary{}, fmt.Errorf("role %q does not exist: %w", normalizedRole, ErrValidation)
 	}
 
 	if strings.TrimSpace(userID) == "" {
 		return UserSummary{}, fmt.Errorf("user id is required: %w", ErrValidation)
 	}
 
-	if err := s.users.UpdateRole(ctx, userID, normalizedRole); err != nil {
+	current, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return UserSummary{}, ErrUserNotFound
+		}
+		return UserSummary{}, fmt.Errorf("get user: %w", err)
+	}
+	if !s.policy.Covers(actorRole, string(current.Role)) || !s.policy.Covers(actorRole, string(normalizedRole)) {
+		return UserSummary{}, ErrForbidden
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.users.UpdateRole(ctx, userID, normalizedRole); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditRecord{
+			ActorID:    actorID,
+			Action:     AuditUserRoleUpdate,
+			TargetType: AuditTargetUser,
+			TargetID:   current.ID,
+			Before:     map[string]any{"role": current.Role},
+			After:      map[string]any{"role": normalizedRole},
+		})
+	})
+	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return UserSummary{}, ErrUserNotFound
 		}
@@ -75,13 +113,32 @@ func (s *AdminService) UpdateUserRole(ctx context.Context, userID, role string)
 	return UserSummary{ID: user.ID, Email: user.Email, Role: user.Role}, nil
 }
 
-func normalizeRole(role string) (models.Role, error) {
-	value := strings.ToLower(strings.TrimSpace(role))
-	parsed := models.Role(value)
-	switch parsed {
-	case models.RoleAdmin, models.RoleAuthor, models.RoleReader:
-		return parsed, nil
-	default:
-		return "", fmt.Errorf("role must be admin, author, or reader: %w", ErrValidation)
+// UnlockUser lifts a sign-in lock on the user and forgets their failed attempts.
+func (s *AdminService) UnlockUser(ctx context.Context, actorID, userID string) error {
+	if strings.TrimSpace(userID) == "" {
+		return fmt.Errorf("user id is required: %w", ErrValidation)
+	}
+
+	if _, err := s.users.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("get user to unlock: %w", err)
+	}
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.throttles.Clear(ctx, userThrottleKey(userID)); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditRecord{
+			ActorID:    actorID,
+			Action:     AuditUserUnlock,
+			TargetType: AuditTargetUser,
+			TargetID:   userID,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("unlock user: %w", err)
 	}
+	return nil
 }
@@ -3,9 +3,12 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/policy"
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
 )
 
@@ -15,8 +18,18 @@ type fakeUserRepo struct {
 	updateRoleErr error
 }
 
-func (f *fakeUserRepo) Create(context.Context, *models.User) error { return nil }
-func (f *fakeUserRepo) GetByEmail(context.Context, string) (*models.User, error) {
+func (f *fakeUserRepo) Create(_ context.Context, user *models.User) error {
+	user.ID = fmt.Sprintf("new%d", len(f.users)+1)
+	f.users = append(f.users, *user)
+	return nil
+}
+func (f *fakeUserRepo) GetByEmail(_ context.Context, email string) (*models.User, error) {
+	for i := range f.users {
+		if f.users[i].Email == email {
+			copy := f.users[i]
+			return &copy, nil
+		}
+	}
 	return nil, repository.ErrNotFound
 }
 func (f *fakeUserRepo) GetByID(_ context.Context, id string) (*models.User, error) {
@@ -44,26 +57,79 @@ func (f *fakeUserRepo) UpdateRole(_ context.Context, id string, role models.Role
 	}
 	return repository.ErrNotFound
 }
-func (f *fakeUserRepo) UpdatePasswordHash(context.Context, string, string) error { return nil }
+func (f *fakeUserRepo) UpdatePasswordHash(_ context.Context, id, hash string) error {
+	for i := range f.users {
+		if f.users[i].ID == id {
+			f.users[i].PasswordHash = hash
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (f *fakeUserRepo) UpdateEmail(_ context.Context, id, email string) error {
+	for i := range f.users {
+		if f.users[i].Email == email && f.users[i].ID != id {
+			return repository.ErrDuplicate
+		}
+	}
+	for i := range f.users {
+		if f.users[i].ID == id {
+			f.users[i].Email = email
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (f *fakeUserRepo) MarkEmailVerified(_ context.Context, id string, at time.Time) error {
+	for i := range f.users {
+		if f.users[i].ID == id {
+			f.users[i].EmailVerifiedAt = &at
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
 
 func TestAdminServiceUpdateUserRoleValidation(t *testing.T) {
-	svc := NewAdminService(&fakeUserRepo{})
+	svc := NewAdminService(&fakeUserRepo{}, newFakeThrottleRepo(), newTestAudit(), fakeTransactor{}, defaultPolicy())
 
-	_, err := svc.UpdateUserRole(context.Background(), "u1", "invalid-role")
+	_, err := svc.UpdateUserRole(context.Background(), "admin-1", "admin", "u1", "invalid-role")
 	if !errors.Is(err, ErrValidation) {
 		t.Fatalf("expected ErrValidation, got %v", err)
 	}
 }
 
 func TestAdminServiceUpdateUserRoleNotFound(t *testing.T) {
-	svc := NewAdminService(&fakeUserRepo{updateRoleErr: repository.ErrNotFound})
+	svc := NewAdminService(&fakeUserRepo{updateRoleErr: repository.ErrNotFound}, newFakeThrottleRepo(), newTestAudit(), fakeTransactor{}, defaultPolicy())
 
-	_, err := svc.UpdateUserRole(context.Background(), "missing", "reader")
+	_, err := svc.UpdateUserRole(context.Background(), "admin-1", "admin", "missing", "reader")
 	if !errors.Is(err, ErrUserNotFound) {
 		t.Fatalf("expected ErrUserNotFound, got %v", err)
 	}
 }
 
+func TestAdminServiceUpdateUserRoleCannotEscalate(t *testing.T) {
+	authz := defaultPolicy()
+	grants := policy.DefaultGrants()
+	grants["lead"] = append(append([]policy.Permission{}, grants["editor"]...), policy.UserRoleAssign)
+	authz.Replace(grants)
+	repo := &fakeUserRepo{users: []models.User{{ID: "u1", Role: models.RoleAuthor}, {ID: "u2", Role: models.RoleAdmin}}}
+	svc := NewAdminService(repo, newFakeThrottleRepo(), newTestAudit(), fakeTransactor{}, authz)
+	ctx := context.Background()
+
+	if _, err := svc.UpdateUserRole(ctx, "admin-1", "lead", "u1", "editor"); err != nil {
+		t.Fatalf("expected a lead to promote an author to editor: %v", err)
+	}
+	if _, err := svc.UpdateUserRole(ctx, "admin-1", "lead", "u1", "admin"); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected a lead not to hand out admin, got %v", err)
+	}
+	if _, err := svc.UpdateUserRole(ctx, "admin-1", "lead", "u2", "reader"); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected a lead not to demote an admin, got %v", err)
+	}
+}
+
 func TestAdminServiceListUsersPagination(t *testing.T) {
 	repo := &fakeUserRepo{
 		users: []models.User{
@@ -72,7 +138,7 @@ func TestAdminServiceListUsersPagination(t *testing.T) {
 		},
 		count: 12,
 	}
-	svc := NewAdminService(repo)
+	svc := NewAdminService(repo, newFakeThrottleRepo(), newTestAudit(), fakeTransactor{}, defaultPolicy())
 
 	users, page, err := svc.ListUsers(context.Background(), 1, 10)
 	if err != nil {
@@ -85,3 +151,20 @@ func TestAdminServiceListUsersPagination(t *testing.T) {
 		t.Fatalf("unexpected pagination metadata: %+v", page)
 	}
 }
+
+func TestAdminServiceUnlockUserClearsThrottle(t *testing.T) {
+	throttles := newFakeThrottleRepo()
+	lockedUntil := time.Now().Add(time.Hour)
+	throttles.rows["user:u1"] = &models.LoginThrottle{Key: "user:u1", Lockouts: 2, LockedUntil: &lockedUntil}
+	svc := NewAdminService(&fakeUserRepo{users: []models.User{{ID: "u1", Email: "a@example.com"}}}, throttles, newTestAudit(), fakeTransactor{}, defaultPolicy())
+
+	if err := svc.UnlockUser(context.Background(), "admin-1", "missing"); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+	if err := svc.UnlockUser(context.Background(), "admin-1", "u1"); err != nil {
+		t.Fatalf("expected unlock to succeed: %v", err)
+	}
+	if _, ok := throttles.rows["user:u1"]; ok {
+		t.Fatalf("expected lock to be cleared")
+	}
+}
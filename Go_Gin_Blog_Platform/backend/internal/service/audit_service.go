@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/policy"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+const (
+	AuditUserRoleUpdate   = "user.role.update"
+	AuditUserUnlock       = "user.unlock"
+	AuditPostDelete       = "post.delete"
+	AuditPostRestore      = "post.restore"
+	AuditPostPurge        = "post.purge"
+	AuditPasswordReset    = "password.reset"
+	AuditPasswordChange   = "password.change"
+	AuditEmailChange      = "email.change"
+	AuditTwoFactorEnable  = "mfa.enable"
+	AuditTwoFactorDisable = "mfa.disable"
+	AuditRoleCreate       = "role.create"
+	AuditRoleUpdate       = "role.update"
+	AuditRoleDelete       = "role.delete"
+	AuditTokenCreate      = "token.create"
+	AuditTokenRevoke      = "token.revoke"
+	AuditSessionRevoke    = "session.revoke"
+	AuditSessionRevokeAll = "session.revoke_all"
+	AuditCommentModerate  = "comment.moderate"
+	AuditTagRename        = "tag.rename"
+	AuditTagMerge         = "tag.merge"
+	AuditPostAssign       = "post.assign"
+	AuditPostPublish      = "post.publish"
+)
+
+const (
+	AuditTargetUser    = "user"
+	AuditTargetPost    = "post"
+	AuditTargetRole    = "role"
+	AuditTargetToken   = "token"
+	AuditTargetSession = "session"
+	AuditTargetComment = "comment"
+	AuditTargetTag     = "tag"
+)
+
+// User and event IDs are UUIDs; anything else could never match and would
+// only make the database reject the query.
+var auditIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 200
+	auditExportBatchSize = 500
+)
+
+// RequestInfo identifies the API request a call is made for. The HTTP layer
+// attaches it to the context, and audit events copy it.
+type RequestInfo struct {
+	ID        string
+	IPAddress string
+}
+
+type requestInfoKey struct{}
+
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+func RequestInfoFrom(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
+
+// AuditRecord is an action to write to the audit log. Before and After are
+// stored as JSON; nil stores nothing.
+type AuditRecord struct {
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	Before     any
+	After      any
+}
+
+// AuditQuery filters the audit log. Actor and Action match exactly; From and
+// To are RFC 3339 times, From inclusive and To exclusive.
+type AuditQuery struct {
+	ViewerRole string
+	Actor      string
+	Action     string
+	From       string
+	To         string
+	Cursor     string
+	Limit      int
+}
+
+type AuditEventItem struct {
+	ID         string          `json:"id"`
+	ActorID    *string         `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   string          `json:"target_id"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	IPAddress  string          `json:"ip_address"`
+	RequestID  string          `json:"request_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditService writes and reads the audit log. Other services record their
+// security and administrative actions through it.
+type AuditService struct {
+	events repository.AuditRepository
+	policy *policy.Policy
+}
+
+func NewAuditService(events repository.AuditRepository, authz *policy.Policy) *AuditService {
+	return &AuditService{events: events, policy: authz}
+}
+
+// Record appends an event to the log. Callers pass the ctx of the transaction
+// making the change, so the event is kept exactly when the change is.
+func (s *AuditService) Record(ctx context.Context, record AuditRecord) error {
+	before, err := auditJSON(record.Before)
+	if err != nil {
+		return err
+	}
+	after, err := auditJSON(record.After)
+	if err != nil {
+		return err
+	}
+
+	info := RequestInfoFrom(ctx)
+	event := &models.AuditEvent{
+		Action:     record.Action,
+		TargetType: record.TargetType,
+		TargetID:   record.TargetID,
+		Before:     before,
+		After:      after,
+		IPAddress:  info.IPAddress,
+		RequestID:  info.ID,
+	}
+	if actorID := strings.TrimSpace(record.ActorID); actorID != "" {
+		event.ActorID = &actorID
+	}
+	if err := s.events.Create(ctx, event); err != nil {
+		return fmt.Errorf("record audit event: %w", err)
+	}
+	return nil
+}
+
+// List returns a page of matching events, newest first, and the cursor of the
+// next page, which is empty on the last one.
+func (s *AuditService) List(ctx context.Context, query AuditQuery) ([]AuditEventItem, string, error) {
+	filter, err := s.auditFilter(query)
+	if err != nil {
+		return nil, "", err
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultAuditPageSize
+	}
+	if limit > maxAuditPageSize {
+		limit = maxAuditPageSize
+	}
+
+	// One extra row tells whether there is a next page.
+	events, err := s.events.List(ctx, filter, limit+1)
+	if err != nil {
+		return nil, "", fmt.Errorf("list audit events: %w", err)
+	}
+
+	var next string
+	if len(events) > limit {
+		events = events[:limit]
+		last := events[len(events)-1]
+		next = encodeAuditCursor(repository.AuditCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	items := make([]AuditEventItem, 0, len(events))
+	for _, event := range events {
+		items = append(items, auditEventItem(event))
+	}
+	return items, next, nil
+}
+
+// Export passes every matching event to write, newest first, reading the log
+// in batches. The query is checked before write is first called.
+func (s *AuditService) Export(ctx context.Context, query AuditQuery, write func(AuditEventItem) error) error {
+	filter, err := s.auditFilter(query)
+	if err != nil {
+		return err
+	}
+
+	for {
+		events, err := s.events.List(ctx, filter, auditExportBatchSize)
+		if err != nil {
+			return fmt.Errorf("export audit events: %w", err)
+		}
+		for _, event := range events {
+			if err := write(auditEventItem(event)); err != nil {
+				return err
+			}
+		}
+		if len(events) < auditExportBatchSize {
+			return nil
+		}
+		last := events[len(events)-1]
+		filter.After = &repository.AuditCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+func (s *AuditService) auditFilter(query AuditQuery) (repository.AuditFilter, error) {
+	if !s.policy.Authorize(policy.Actor{Role: query.ViewerRole}, policy.AuditRead, nil) {
+		return repository.AuditFilter{}, ErrForbidden
+	}
+
+	filter := repository.AuditFilter{
+		ActorID: strings.TrimSpace(query.Actor),
+		Action:  strings.ToLower(strings.TrimSpace(query.Action)),
+	}
+	if filter.ActorID != "" && !auditIDPattern.MatchString(filter.ActorID) {
+		return repository.AuditFilter{}, fmt.Errorf("actor must be a user id: %w", ErrValidation)
+	}
+	var err error
+	if filter.From, err = parseAuditTime("from", query.From); err != nil {
+		return repository.AuditFilter{}, err
+	}
+	if filter.To, err = parseAuditTime("to", query.To); err != nil {
+		return repository.AuditFilter{}, err
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return repository.AuditFilter{}, fmt.Errorf("from must be before to: %w", ErrValidation)
+	}
+	if cursor := strings.TrimSpace(query.Cursor); cursor != "" {
+		after, err := decodeAuditCursor(cursor)
+		if err != nil {
+			return repository.AuditFilter{}, err
+		}
+		filter.After = &after
+	}
+	return filter, nil
+}
+
+func parseAuditTime(name, value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC 3339 time: %w", name, ErrValidation)
+	}
+	utc := parsed.UTC()
+	return &utc, nil
+}
+
+// Cursors are opaque to clients: the position of the last event of a page.
+func encodeAuditCursor(cursor repository.AuditCursor) string {
+	raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "," + cursor.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeAuditCursor(value string) (repository.AuditCursor, error) {
+	invalid := fmt.Errorf("cursor is invalid: %w", ErrValidation)
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return repository.AuditCursor{}, invalid
+	}
+	at, id, ok := strings.Cut(string(raw), ",")
+	if !ok || !auditIDPattern.MatchString(id) {
+		return repository.AuditCursor{}, invalid
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return repository.AuditCursor{}, invalid
+	}
+	return repository.AuditCursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+func auditJSON(value any) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("encode audit state: %w", err)
+	}
+	return encoded, nil
+}
+
+func auditEventItem(event models.AuditEvent) AuditEventItem {
+	return AuditEventItem{
+		ID:         event.ID,
+		ActorID:    event.ActorID,
+		Action:     event.Action,
+		TargetType: event.TargetType,
+		TargetID:   event.TargetID,
+		Before:     event.Before,
+		After:      event.After,
+		IPAddress:  event.IPAddress,
+		RequestID:  event.RequestID,
+		CreatedAt:  event.CreatedAt,
+	}
+}
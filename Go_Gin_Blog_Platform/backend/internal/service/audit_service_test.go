@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+// fakeAuditRepo orders events like the real one, by (created_at, id). With
+// frozen set every event gets the same timestamp.
+type fakeAuditRepo struct {
+	events []models.AuditEvent
+	clock  time.Time
+	frozen bool
+}
+
+func fakeAuditEventID(n int) string {
+	return fmt.Sprintf("00000000-0000-0000-0000-%012d", n)
+}
+
+func (f *fakeAuditRepo) Create(_ context.Context, event *models.AuditEvent) error {
+	if f.clock.IsZero() {
+		f.clock = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if !f.frozen {
+		f.clock = f.clock.Add(time.Minute)
+	}
+	event.ID = fakeAuditEventID(len(f.events) + 1)
+	event.CreatedAt = f.clock
+	f.events = append(f.events, *event)
+	return nil
+}
+
+func (f *fakeAuditRepo) List(_ context.Context, filter repository.AuditFilter, limit int) ([]models.AuditEvent, error) {
+	var events []models.AuditEvent
+	for _, event := range f.events {
+		switch {
+		case filter.ActorID != "" && (event.ActorID == nil || *event.ActorID != filter.ActorID):
+		case filter.Action != "" && event.Action != filter.Action:
+		case filter.From != nil && event.CreatedAt.Before(*filter.From):
+		case filter.To != nil && !event.CreatedAt.Before(*filter.To):
+		case filter.After != nil && !auditEventBefore(event, filter.After.CreatedAt, filter.After.ID):
+		default:
+			events = append(events, event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return auditEventBefore(events[j], events[i].CreatedAt, events[i].ID) })
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+// auditEventBefore reports whether event sorts before (createdAt, id), the
+// row comparison the repository's cursor uses.
+func auditEventBefore(event models.AuditEvent, createdAt time.Time, id string) bool {
+	if !event.CreatedAt.Equal(createdAt) {
+		return event.CreatedAt.Before(createdAt)
+	}
+	return event.ID < id
+}
+
+func newTestAudit() *AuditService {
+	return NewAuditService(&fakeAuditRepo{}, defaultPolicy())
+}
+
+func TestAuditServiceRecordsAdminActions(t *testing.T) {
+	events := &fakeAuditRepo{}
+	audit := NewAuditService(events, defaultPolicy())
+	users := &fakeUserRepo{users: []models.User{{ID: "u1", Role: models.RoleAuthor}}}
+	svc := NewAdminService(users, newFakeThrottleRepo(), audit, fakeTransactor{}, defaultPolicy())
+	ctx := WithRequestInfo(context.Background(), RequestInfo{ID: "req-1", IPAddress: "203.0.113.9"})
+
+	if _, err := svc.UpdateUserRole(ctx, "admin-1", "admin", "u1", "editor"); err != nil {
+		t.Fatalf("update role: %v", err)
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("expected one audit event, got %d", len(events.events))
+	}
+	event := events.events[0]
+	if event.Action != AuditUserRoleUpdate || event.ActorID == nil || *event.ActorID != "admin-1" || event.TargetType != AuditTargetUser || event.TargetID != "u1" {
+		t.Fatalf("unexpected audit event %+v", event)
+	}
+	if event.RequestID != "req-1" || event.IPAddress != "203.0.113.9" {
+		t.Fatalf("expected the request to be recorded, got %q from %q", event.RequestID, event.IPAddress)
+	}
+	var before, after map[string]string
+	if json.Unmarshal(event.Before, &before) != nil || json.Unmarshal(event.After, &after) != nil || before["role"] != "author" || after["role"] != "editor" {
+		t.Fatalf("expected the role change in before and after, got %s -> %s", event.Before, event.After)
+	}
+
+	if _, err := svc.UpdateUserRole(ctx, "admin-1", "admin", "u1", "emperor"); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected an invalid role to be refused, got %v", err)
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("expected refused changes not to be recorded, got %d events", len(events.events))
+	}
+}
+
+func TestAuditServiceListPaginatesWithCursor(t *testing.T) {
+	events := &fakeAuditRepo{}
+	svc := NewAuditService(events, defaultPolicy())
+	ctx := context.Background()
+	const editor = "7f9c2e4a-1b3d-4c5e-8f60-a1b2c3d4e5f6"
+	for i := 0; i < 5; i++ {
+		action := AuditPostDelete
+		if i%2 == 1 {
+			action = AuditPostRestore
+		}
+		if err := svc.Record(ctx, AuditRecord{ActorID: editor, Action: action, TargetType: AuditTargetPost, TargetID: "p1"}); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+
+	page, next, err := svc.List(ctx, AuditQuery{ViewerRole: "admin", Limit: 2})
+	if err != nil || len(page) != 2 || next == "" {
+		t.Fatalf("expected a first page with a cursor, got %d items, %q (%v)", len(page), next, err)
+	}
+	if page[0].ID != fakeAuditEventID(5) || page[1].ID != fakeAuditEventID(4) {
+		t.Fatalf("expected newest first, got %s, %s", page[0].ID, page[1].ID)
+	}
+
+	var seen []string
+	for cursor := next; cursor != ""; {
+		page, cursor, err = svc.List(ctx, AuditQuery{ViewerRole: "admin", Limit: 2, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("list next page: %v", err)
+		}
+		for _, item := range page {
+			seen = append(seen, item.ID)
+		}
+	}
+	want := []string{fakeAuditEventID(3), fakeAuditEventID(2), fakeAuditEventID(1)}
+	if fmt.Sprint(seen) != fmt.Sprint(want) {
+		t.Fatalf("expected the remaining events in order, got %v", seen)
+	}
+
+	restores, _, err := svc.List(ctx, AuditQuery{ViewerRole: "admin", Action: " POST.RESTORE "})
+	if err != nil || len(restores) != 2 {
+		t.Fatalf("expected the action filter to match two events, got %d (%v)", len(restores), err)
+	}
+
+	var exported int
+	if err := svc.Export(ctx, AuditQuery{ViewerRole: "admin", Actor: editor}, func(AuditEventItem) error { exported++; return nil }); err != nil || exported != 5 {
+		t.Fatalf("expected every event to be exported, got %d (%v)", exported, err)
+	}
+}
+
+func TestAuditServiceCursorBreaksTimestampTies(t *testing.T) {
+	events := &fakeAuditRepo{frozen: true}
+	svc := NewAuditService(events, defaultPolicy())
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		if err := svc.Record(ctx, AuditRecord{Action: AuditPostDelete, TargetType: AuditTargetPost, TargetID: "p1"}); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+
+	var seen []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatalf("expected pagination to end, seen %v", seen)
+		}
+		page, next, err := svc.List(ctx, AuditQuery{ViewerRole: "admin", Limit: 2, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		for _, item := range page {
+			seen = append(seen, item.ID)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	want := []string{fakeAuditEventID(5), fakeAuditEventID(4), fakeAuditEventID(3), fakeAuditEventID(2), fakeAuditEventID(1)}
+	if fmt.Sprint(seen) != fmt.Sprint(want) {
+		t.Fatalf("expected every event exactly once across page boundaries, got %v", seen)
+	}
+}
+
+func TestAuditServiceRejectsBadQueries(t *testing.T) {
+	svc := newTestAudit()
+	ctx := context.Background()
+
+	cases := map[string]struct {
+		query AuditQuery
+		want  error
+	}{
+		"not an admin":        {AuditQuery{ViewerRole: "editor"}, ErrForbidden},
+		"actor not an id":     {AuditQuery{ViewerRole: "admin", Actor: "bob"}, ErrValidation},
+		"bad from":            {AuditQuery{ViewerRole: "admin", From: "yesterday"}, ErrValidation},
+		"from after to":       {AuditQuery{ViewerRole: "admin", From: "2024-02-01T00:00:00Z", To: "2024-01-01T00:00:00Z"}, ErrValidation},
+		"forged cursor":       {AuditQuery{ViewerRole: "admin", Cursor: "bm90LWEtY3Vyc29y"}, ErrValidation},
+		"not base64 at all":   {AuditQuery{ViewerRole: "admin", Cursor: "%%%"}, ErrValidation},
+		"cursor id not an id": {AuditQuery{ViewerRole: "admin", Cursor: base64.RawURLEncoding.EncodeToString([]byte("2024-01-01T00:00:00Z,1 OR 1=1"))}, ErrValidation},
+	}
+	for name, tc := range cases {
+		if _, _, err := svc.List(ctx, tc.query); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", name, tc.want, err)
+		}
+	}
+
+	called := false
+	err := svc.Export(ctx, AuditQuery{ViewerRole: "author"}, func(AuditEventItem) error { called = true; return nil })
+	if !errors.Is(err, ErrForbidden) || called {
+		t.Fatalf("expected the export to be refused before writing, got %v", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/email"
@@ -23,18 +24,27 @@ var (
 	ErrUserNotFound       = errors.New("user not found")
 )
 
+// maxUserAgentLength bounds the user agent stored with a session.
+const maxUserAgentLength = 512
+
 type RegisterInput struct {
-	Email    string
-	Password string
+	Email     string
+	Password  string
+	IPAddress string
+	UserAgent string
 }
 
 type LoginInput struct {
-	Email    string
-	Password string
+	Email     string
+	Password  string
+	IPAddress string
+	UserAgent string
 }
 
 type RefreshInput struct {
 	RefreshToken string
+	IPAddress    string
+	UserAgent    string
 }
 
 type LogoutInput struct {
@@ -51,9 +61,10 @@ type ConfirmResetInput struct {
 }
 
 type RegisteredUser struct {
-	ID    string      `json:"id"`
-	Email string      `json:"email"`
-	Role  models.Role `json:"role"`
+	ID            string      `json:"id"`
+	Email         string      `json:"email"`
+	Role          models.Role `json:"role"`
+	EmailVerified bool        `json:"email_verified"`
 }
 
 type TokenPair struct {
@@ -69,11 +80,22 @@ type AuthService struct {
 	users            repository.UserRepository
 	refreshTokens    repository.RefreshTokenRepository
 	resetTokens      repository.PasswordResetTokenRepository
+	emailChanges     repository.EmailChangeTokenRepository
+	verifications    repository.EmailVerificationTokenRepository
+	throttles        repository.LoginThrottleRepository
+	mfa              repository.MFARepository
+	pats             repository.PersonalAccessTokenRepository
+	audit            *AuditService
+	tx               repository.Transactor
 	tokenManager     *auth.TokenManager
 	emailSender      email.Sender
 	defaultRole      models.Role
 	passwordResetTTL time.Duration
+	verificationTTL  time.Duration
+	lockout          LockoutPolicy
 	frontendBaseURL  string
+	mfaIssuer        string
+	now              func() time.Time
 }
 
 func NewAuthService(
@@ -81,21 +103,42 @@ func NewAuthService(
 	users repository.UserRepository,
 	refreshTokens repository.RefreshTokenRepository,
 	resetTokens repository.PasswordResetTokenRepository,
+	emailChanges repository.EmailChangeTokenRepository,
+	verifications repository.EmailVerificationTokenRepository,
+	throttles repository.LoginThrottleRepository,
+	mfa repository.MFARepository,
+	pats repository.PersonalAccessTokenRepository,
+	audit *AuditService,
+	tx repository.Transactor,
 	tokenManager *auth.TokenManager,
 	emailSender email.Sender,
 	passwordResetTTL time.Duration,
+	verificationTTL time.Duration,
+	lockout LockoutPolicy,
 	frontendBaseURL string,
+	mfaIssuer string,
 ) *AuthService {
 	return &AuthService{
 		logger:           logger,
 		users:            users,
 		refreshTokens:    refreshTokens,
 		resetTokens:      resetTokens,
+		emailChanges:     emailChanges,
+		verifications:    verifications,
+		throttles:        throttles,
+		mfa:              mfa,
+		pats:             pats,
+		audit:            audit,
+		tx:               tx,
 		tokenManager:     tokenManager,
 		emailSender:      emailSender,
 		defaultRole:      models.RoleAuthor,
 		passwordResetTTL: passwordResetTTL,
+		verificationTTL:  verificationTTL,
+		lockout:          lockout,
 		frontendBaseURL:  strings.TrimRight(frontendBaseURL, "/"),
+		mfaIssuer:        mfaIssuer,
+		now:              func() time.Time { return time.Now().UTC() },
 	}
 }
 
@@ -130,12 +173,16 @@ func (s *AuthService) Register(ctx context.Context, input RegisterInput) (Regist
 		return RegisteredUser{}, TokenPair{}, fmt.Errorf("create user: %w", err)
 	}
 
-	pair, err := s.issueTokenPair(ctx, user.ID, string(user.Role))
+	pair, err := s.issueTokenPair(ctx, user, sessionInfo{ipAddress: input.IPAddress, userAgent: input.UserAgent})
 	if err != nil {
 		return RegisteredUser{}, TokenPair{}, err
 	}
 
-	return RegisteredUser{ID: user.ID, Email: user.Email, Role: user.Role}, pair, nil
+	if err := s.sendVerificationEmail(ctx, user); err != nil {
+		s.logger.Error("email verification setup failed", "error", err, "user_id", user.ID)
+	}
+
+	return registeredUser(user), pair, nil
 }
 
 func (s *AuthService) Login(ctx context.Context, input LoginInput) (RegisteredUser, TokenPair, error) {
@@ -144,24 +191,53 @@ func (s *AuthService) Login(ctx context.Context, input LoginInput) (RegisteredUs
 		return RegisteredUser{}, TokenPair{}, fmt.Errorf("login input invalid: %w", ErrValidation)
 	}
 
+	ip := strings.TrimSpace(input.IPAddress)
+	if ip != "" {
+		if err := s.checkLocked(ctx, ipThrottleKey(ip)); err != nil {
+			return RegisteredUser{}, TokenPair{}, err
+		}
+	}
+
 	user, err := s.users.GetByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
+			if err := s.recordLoginFailure(ctx, nil, ip); err != nil {
+				return RegisteredUser{}, TokenPair{}, err
+			}
 			return RegisteredUser{}, TokenPair{}, ErrInvalidCredentials
 		}
 		return RegisteredUser{}, TokenPair{}, fmt.Errorf("fetch user by email: %w", err)
 	}
 
+	// A locked account is refused before the password is checked, so guesses
+	// made during the lock tell an attacker nothing.
+	if err := s.checkLocked(ctx, userThrottleKey(user.ID)); err != nil {
+		return RegisteredUser{}, TokenPair{}, err
+	}
+
 	if !auth.VerifyPassword(user.PasswordHash, input.Password) {
+		if err := s.recordLoginFailure(ctx, user, ip); err != nil {
+			return RegisteredUser{}, TokenPair{}, err
+		}
 		return RegisteredUser{}, TokenPair{}, ErrInvalidCredentials
 	}
 
-	pair, err := s.issueTokenPair(ctx, user.ID, string(user.Role))
+	// With 2FA on, the password only earns a challenge; failures are cleared
+	// once the second factor is accepted too.
+	if err := s.secondFactorChallenge(ctx, user.ID); err != nil {
+		return RegisteredUser{}, TokenPair{}, err
+	}
+
+	if err := s.throttles.Clear(ctx, userThrottleKey(user.ID)); err != nil {
+		return RegisteredUser{}, TokenPair{}, fmt.Errorf("reset login failures: %w", err)
+	}
+
+	pair, err := s.issueTokenPair(ctx, user, sessionInfo{ipAddress: ip, userAgent: input.UserAgent})
 	if err != nil {
 		return RegisteredUser{}, TokenPair{}, err
 	}
 
-	return RegisteredUser{ID: user.ID, Email: user.Email, Role: user.Role}, pair, nil
+	return registeredUser(user), pair, nil
 }
 
 func (s *AuthService) Refresh(ctx context.Context, input RefreshInput) (TokenPair, error) {
@@ -176,31 +252,75 @@ func (s *AuthService) Refresh(ctx context.Context, input RefreshInput) (TokenPai
 	}
 
 	hashed := auth.HashToken(rawToken)
-	storedToken, err := s.refreshTokens.GetActiveByHash(ctx, hashed)
-	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			return TokenPair{}, ErrInvalidToken
+	var pair TokenPair
+	var reused *models.RefreshToken
+	var familyRevoked int64
+	// The token row stays locked until the new pair is stored, so of two
+	// concurrent refreshes with the same token only one can rotate it; the
+	// other sees it revoked and is treated as a replay.
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		storedToken, err := s.refreshTokens.GetByHashForUpdate(ctx, hashed)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrInvalidToken
+			}
+			return fmt.Errorf("lookup refresh token: %w", err)
 		}
-		return TokenPair{}, fmt.Errorf("lookup refresh token: %w", err)
-	}
 
-	if storedToken.UserID != claims.Subject {
-		return TokenPair{}, ErrInvalidToken
-	}
+		if storedToken.UserID != claims.Subject {
+			return ErrInvalidToken
+		}
 
-	user, err := s.users.GetByID(ctx, claims.Subject)
-	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			return TokenPair{}, ErrInvalidToken
+		if storedToken.RevokedAt != nil {
+			// A rotated token coming back means it was copied: whoever holds
+			// its live descendant may be the attacker, so end the family.
+			reused = storedToken
+			familyRevoked, err = s.refreshTokens.RevokeFamily(ctx, storedToken.FamilyID)
+			if err != nil {
+				return fmt.Errorf("revoke refresh token family: %w", err)
+			}
+			return nil
 		}
-		return TokenPair{}, fmt.Errorf("get user for refresh: %w", err)
+
+		if !storedToken.ExpiresAt.After(s.now()) {
+			return ErrInvalidToken
+		}
+
+		user, err := s.users.GetByID(ctx, claims.Subject)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrInvalidToken
+			}
+			return fmt.Errorf("get user for refresh: %w", err)
+		}
+
+		if err := s.refreshTokens.RevokeByHash(ctx, hashed); err != nil {
+			return fmt.Errorf("revoke old refresh token: %w", err)
+		}
+
+		pair, err = s.issueTokenPair(ctx, user, sessionInfo{
+			familyID:  storedToken.FamilyID,
+			ipAddress: input.IPAddress,
+			userAgent: input.UserAgent,
+			mfa:       storedToken.MFA,
+		})
+		return err
+	})
+	if err != nil {
+		return TokenPair{}, err
 	}
 
-	if err := s.refreshTokens.RevokeByHash(ctx, hashed); err != nil && !errors.Is(err, repository.ErrNotFound) {
-		return TokenPair{}, fmt.Errorf("revoke old refresh token: %w", err)
+	if reused != nil {
+		s.logger.Warn("refresh token reuse detected",
+			"user_id", reused.UserID,
+			"family_id", reused.FamilyID,
+			"token_id", reused.ID,
+			"revoked_tokens", familyRevoked,
+		)
+		return TokenPair{}, ErrInvalidToken
 	}
 
-	return s.issueTokenPair(ctx, user.ID, string(user.Role))
+	return pair, nil
 }
 
 func (s *AuthService) Logout(ctx context.Context, input LogoutInput) error {
@@ -283,42 +403,117 @@ func (s *AuthService) ConfirmPasswordReset(ctx context.Context, input ConfirmRes
 		return fmt.Errorf("hash new password: %w", err)
 	}
 
-	if err := s.users.UpdatePasswordHash(ctx, storedToken.UserID, hashedPassword); err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			return ErrInvalidToken
+	var user *models.User
+	// Claiming the token, changing the password and signing out every session
+	// and personal access token commit together, so a reset never leaves a
+	// stolen session alive.
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.resetTokens.MarkUsedByID(ctx, storedToken.ID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrInvalidToken
+			}
+			return fmt.Errorf("mark reset token used: %w", err)
 		}
-		return fmt.Errorf("update password hash: %w", err)
-	}
 
-	if err := s.resetTokens.MarkUsedByID(ctx, storedToken.ID); err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			return ErrInvalidToken
+		user, err = s.users.GetByID(ctx, storedToken.UserID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrInvalidToken
+			}
+			return fmt.Errorf("get user for reset: %w", err)
+		}
+
+		if err := s.users.UpdatePasswordHash(ctx, user.ID, hashedPassword); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrInvalidToken
+			}
+			return fmt.Errorf("update password hash: %w", err)
 		}
-		return fmt.Errorf("mark reset token used: %w", err)
+
+		if _, err := s.refreshTokens.RevokeAllForUser(ctx, user.ID); err != nil {
+			return fmt.Errorf("revoke sessions after reset: %w", err)
+		}
+		if _, err := s.pats.RevokeAllForUser(ctx, user.ID, s.now()); err != nil {
+			return fmt.Errorf("revoke personal access tokens after reset: %w", err)
+		}
+		if _, err := s.resetTokens.InvalidateAllForUser(ctx, user.ID); err != nil {
+			return fmt.Errorf("invalidate other reset tokens: %w", err)
+		}
+		return s.audit.Record(ctx, AuditRecord{
+			ActorID:    user.ID,
+			Action:     AuditPasswordReset,
+			TargetType: AuditTargetUser,
+			TargetID:   user.ID,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	message := email.Message{
+		To:      user.Email,
+		Subject: "Your password was changed",
+		Body: "The password for your account was just changed and you have been signed out on every device. " +
+			"If this wasn't you, reset your password now: " + s.frontendBaseURL + "/forgot-password",
+	}
+	if err := s.emailSender.Send(ctx, message); err != nil {
+		s.logger.Error("password changed email send failed", "error", err, "email", user.Email)
 	}
 
 	return nil
 }
 
-func (s *AuthService) issueTokenPair(ctx context.Context, userID, role string) (TokenPair, error) {
-	accessToken, accessExpiresAt, err := s.tokenManager.GenerateAccessToken(userID, role)
-	if err != nil {
-		return TokenPair{}, fmt.Errorf("generate access token: %w", err)
+// sessionInfo describes the session a token pair is issued for. An empty
+// familyID starts a new session.
+type sessionInfo struct {
+	familyID  string
+	ipAddress string
+	userAgent string
+	mfa       bool
+}
+
+// truncateUserAgent drops invalid UTF-8 and cuts the user agent to
+// maxUserAgentLength bytes without splitting a rune, so it stores as text.
+func truncateUserAgent(userAgent string) string {
+	userAgent = strings.ToValidUTF8(strings.TrimSpace(userAgent), "")
+	if len(userAgent) <= maxUserAgentLength {
+		return userAgent
 	}
+	end := maxUserAgentLength
+	for end > 0 && !utf8.RuneStart(userAgent[end]) {
+		end--
+	}
+	return userAgent[:end]
+}
 
-	refreshToken, _, refreshExpiresAt, err := s.tokenManager.GenerateRefreshToken(userID)
+// issueTokenPair stores a new refresh token in the session's family. The
+// access token carries the family as its session.
+func (s *AuthService) issueTokenPair(ctx context.Context, user *models.User, session sessionInfo) (TokenPair, error) {
+	refreshToken, _, refreshExpiresAt, err := s.tokenManager.GenerateRefreshToken(user.ID)
 	if err != nil {
 		return TokenPair{}, fmt.Errorf("generate refresh token: %w", err)
 	}
 
-	if err := s.refreshTokens.Create(ctx, &models.RefreshToken{
-		UserID:    userID,
-		TokenHash: auth.HashToken(refreshToken),
-		ExpiresAt: refreshExpiresAt,
-	}); err != nil {
+	userAgent := truncateUserAgent(session.userAgent)
+	stored := &models.RefreshToken{
+		UserID:     user.ID,
+		FamilyID:   session.familyID,
+		TokenHash:  auth.HashToken(refreshToken),
+		UserAgent:  userAgent,
+		IPAddress:  strings.TrimSpace(session.ipAddress),
+		LastUsedAt: s.now(),
+		MFA:        session.mfa,
+		ExpiresAt:  refreshExpiresAt,
+	}
+	if err := s.refreshTokens.Create(ctx, stored); err != nil {
 		return TokenPair{}, fmt.Errorf("store refresh token: %w", err)
 	}
 
+	accessToken, accessExpiresAt, err := s.tokenManager.GenerateAccessToken(user.ID, string(user.Role), stored.FamilyID, user.EmailVerifiedAt != nil, session.mfa)
+	if err != nil {
+		return TokenPair{}, fmt.Errorf("generate access token: %w", err)
+	}
+
 	return TokenPair{
 		AccessToken:      accessToken,
 		RefreshToken:     refreshToken,
@@ -328,6 +523,10 @@ func (s *AuthService) issueTokenPair(ctx context.Context, userID, role string) (
 	}, nil
 }
 
+func registeredUser(user *models.User) RegisteredUser {
+	return RegisteredUser{ID: user.ID, Email: user.Email, Role: user.Role, EmailVerified: user.EmailVerifiedAt != nil}
+}
+
 func normalizeEmail(email string) string {
 	return strings.ToLower(strings.TrimSpace(email))
 }
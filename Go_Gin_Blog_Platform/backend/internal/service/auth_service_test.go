@@ -0,0 +1,544 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/email"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+type fakeRefreshRepo struct {
+	tokens []models.RefreshToken
+}
+
+func (f *fakeRefreshRepo) Create(_ context.Context, token *models.RefreshToken) error {
+	token.ID = fmt.Sprintf("rt%d", len(f.tokens)+1)
+	if token.FamilyID == "" {
+		token.FamilyID = "family-" + token.ID
+	}
+	f.tokens = append(f.tokens, *token)
+	return nil
+}
+
+func (f *fakeRefreshRepo) GetByHashForUpdate(_ context.Context, hash string) (*models.RefreshToken, error) {
+	for i := range f.tokens {
+		if f.tokens[i].TokenHash == hash {
+			copy := f.tokens[i]
+			return &copy, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeRefreshRepo) RevokeByHash(_ context.Context, hash string) error {
+	for i := range f.tokens {
+		if f.tokens[i].TokenHash == hash && f.tokens[i].RevokedAt == nil {
+			now := time.Now()
+			f.tokens[i].RevokedAt = &now
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (f *fakeRefreshRepo) RevokeFamily(_ context.Context, familyID string) (int64, error) {
+	var revoked int64
+	for i := range f.tokens {
+		if f.tokens[i].FamilyID == familyID && f.tokens[i].RevokedAt == nil {
+			now := time.Now()
+			f.tokens[i].RevokedAt = &now
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (f *fakeRefreshRepo) ListActiveByUser(_ context.Context, userID string) ([]models.RefreshToken, error) {
+	var active []models.RefreshToken
+	for i := len(f.tokens) - 1; i >= 0; i-- {
+		if f.tokens[i].UserID == userID && f.tokens[i].RevokedAt == nil {
+			active = append(active, f.tokens[i])
+		}
+	}
+	return active, nil
+}
+
+func (f *fakeRefreshRepo) RevokeAllForUser(_ context.Context, userID string) (int64, error) {
+	var revoked int64
+	for i := range f.tokens {
+		if f.tokens[i].UserID == userID && f.tokens[i].RevokedAt == nil {
+			now := time.Now()
+			f.tokens[i].RevokedAt = &now
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+type fakeResetRepo struct {
+	tokens []models.PasswordResetToken
+}
+
+func (f *fakeResetRepo) Create(_ context.Context, token *models.PasswordResetToken) error {
+	token.ID = fmt.Sprintf("pr%d", len(f.tokens)+1)
+	f.tokens = append(f.tokens, *token)
+	return nil
+}
+
+func (f *fakeResetRepo) GetActiveByHash(_ context.Context, hash string) (*models.PasswordResetToken, error) {
+	for i := range f.tokens {
+		if f.tokens[i].TokenHash == hash && f.tokens[i].UsedAt == nil {
+			copy := f.tokens[i]
+			return &copy, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeResetRepo) MarkUsedByID(_ context.Context, id string) error {
+	for i := range f.tokens {
+		if f.tokens[i].ID == id && f.tokens[i].UsedAt == nil {
+			now := time.Now()
+			f.tokens[i].UsedAt = &now
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (f *fakeResetRepo) InvalidateAllForUser(_ context.Context, userID string) (int64, error) {
+	var invalidated int64
+	for i := range f.tokens {
+		if f.tokens[i].UserID == userID && f.tokens[i].UsedAt == nil {
+			now := time.Now()
+			f.tokens[i].UsedAt = &now
+			invalidated++
+		}
+	}
+	return invalidated, nil
+}
+
+type fakeEmailChangeRepo struct {
+	tokens []models.EmailChangeToken
+}
+
+func (f *fakeEmailChangeRepo) Create(_ context.Context, token *models.EmailChangeToken) error {
+	token.ID = fmt.Sprintf("ec%d", len(f.tokens)+1)
+	f.tokens = append(f.tokens, *token)
+	return nil
+}
+
+func (f *fakeEmailChangeRepo) GetActiveByHash(_ context.Context, hash string) (*models.EmailChangeToken, error) {
+	for i := range f.tokens {
+		if f.tokens[i].TokenHash == hash && f.tokens[i].UsedAt == nil {
+			copy := f.tokens[i]
+			return &copy, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeEmailChangeRepo) MarkUsedByID(_ context.Context, id string) error {
+	for i := range f.tokens {
+		if f.tokens[i].ID == id && f.tokens[i].UsedAt == nil {
+			now := time.Now()
+			f.tokens[i].UsedAt = &now
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (f *fakeEmailChangeRepo) InvalidateAllForUser(_ context.Context, userID string) (int64, error) {
+	var invalidated int64
+	for i := range f.tokens {
+		if f.tokens[i].UserID == userID && f.tokens[i].UsedAt == nil {
+			now := time.Now()
+			f.tokens[i].UsedAt = &now
+			invalidated++
+		}
+	}
+	return invalidated, nil
+}
+
+type fakeVerificationRepo struct {
+	tokens []models.EmailVerificationToken
+}
+
+func (f *fakeVerificationRepo) Create(_ context.Context, token *models.EmailVerificationToken) error {
+	token.ID = fmt.Sprintf("ev%d", len(f.tokens)+1)
+	f.tokens = append(f.tokens, *token)
+	return nil
+}
+
+func (f *fakeVerificationRepo) GetActiveByHash(_ context.Context, hash string) (*models.EmailVerificationToken, error) {
+	for i := range f.tokens {
+		if f.tokens[i].TokenHash == hash && f.tokens[i].UsedAt == nil {
+			copy := f.tokens[i]
+			return &copy, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeVerificationRepo) MarkUsedByID(_ context.Context, id string) error {
+	for i := range f.tokens {
+		if f.tokens[i].ID == id && f.tokens[i].UsedAt == nil {
+			now := time.Now()
+			f.tokens[i].UsedAt = &now
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (f *fakeVerificationRepo) InvalidateAllForUser(_ context.Context, userID string) (int64, error) {
+	var invalidated int64
+	for i := range f.tokens {
+		if f.tokens[i].UserID == userID && f.tokens[i].UsedAt == nil {
+			now := time.Now()
+			f.tokens[i].UsedAt = &now
+			invalidated++
+		}
+	}
+	return invalidated, nil
+}
+
+type fakeThrottleRepo struct {
+	rows map[string]*models.LoginThrottle
+}
+
+func newFakeThrottleRepo() *fakeThrottleRepo {
+	return &fakeThrottleRepo{rows: make(map[string]*models.LoginThrottle)}
+}
+
+func (f *fakeThrottleRepo) Get(_ context.Context, key string) (*models.LoginThrottle, error) {
+	row, ok := f.rows[key]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copy := *row
+	return &copy, nil
+}
+
+func (f *fakeThrottleRepo) RecordFailure(_ context.Context, key string, now, failuresSince, lockoutsSince time.Time) (*models.LoginThrottle, error) {
+	row, ok := f.rows[key]
+	if !ok {
+		row = &models.LoginThrottle{Key: key}
+		f.rows[key] = row
+	} else {
+		if row.LastFailureAt.Before(failuresSince) {
+			row.Failures = 0
+		}
+		if row.LastFailureAt.Before(lockoutsSince) {
+			row.Lockouts = 0
+		}
+	}
+	row.Failures++
+	row.LastFailureAt = now
+	copy := *row
+	return &copy, nil
+}
+
+func (f *fakeThrottleRepo) Lock(_ context.Context, key string, until time.Time) error {
+	row, ok := f.rows[key]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	row.LockedUntil = &until
+	row.Lockouts++
+	row.Failures = 0
+	return nil
+}
+
+func (f *fakeThrottleRepo) Clear(_ context.Context, key string) error {
+	delete(f.rows, key)
+	return nil
+}
+
+func (f *fakeThrottleRepo) DeleteIdle(_ context.Context, idleSince, now time.Time) (int64, error) {
+	var deleted int64
+	for key, row := range f.rows {
+		if row.LastFailureAt.Before(idleSince) && (row.LockedUntil == nil || !row.LockedUntil.After(now)) {
+			delete(f.rows, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+type fakeEmailSender struct {
+	sent []email.Message
+}
+
+func (f *fakeEmailSender) Send(_ context.Context, message email.Message) error {
+	f.sent = append(f.sent, message)
+	return nil
+}
+
+type authFixture struct {
+	svc       *AuthService
+	users     *fakeUserRepo
+	refresh   *fakeRefreshRepo
+	throttles *fakeThrottleRepo
+	mfa       *fakeMFARepo
+	pats      *fakePersonalAccessTokenRepo
+	emails    *fakeEmailSender
+	clock     *time.Time
+}
+
+func newAuthFixture(t *testing.T) authFixture {
+	t.Helper()
+
+	hash, err := auth.HashPassword("correct-horse")
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	users := &fakeUserRepo{users: []models.User{{ID: "u1", Email: "alice@example.com", PasswordHash: hash, Role: models.RoleAuthor}}}
+	throttles := newFakeThrottleRepo()
+	emails := &fakeEmailSender{}
+	refresh := &fakeRefreshRepo{}
+	mfa := newFakeMFARepo()
+	pats := &fakePersonalAccessTokenRepo{}
+	tokens := auth.NewTokenManager("access-secret", "refresh-secret", time.Minute, time.Hour)
+	policy := LockoutPolicy{UserThreshold: 3, IPThreshold: 20, FailureWindow: 15 * time.Minute, BaseLockout: time.Minute, MaxLockout: 4 * time.Minute}
+
+	svc := NewAuthService(slog.New(slog.NewTextHandler(io.Discard, nil)), users, refresh, &fakeResetRepo{}, &fakeEmailChangeRepo{}, &fakeVerificationRepo{}, throttles, mfa, pats, newTestAudit(), fakeTransactor{}, tokens, emails, time.Hour, 48*time.Hour, policy, "https://blog.example.com", "Blog")
+	clock := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return clock }
+	return authFixture{svc: svc, users: users, refresh: refresh, throttles: throttles, mfa: mfa, pats: pats, emails: emails, clock: &clock}
+}
+
+func (f authFixture) login(password string) error {
+	_, _, err := f.svc.Login(context.Background(), LoginInput{Email: "alice@example.com", Password: password, IPAddress: "198.51.100.7"})
+	return err
+}
+
+func TestAuthServiceLoginLocksAccountAfterThreshold(t *testing.T) {
+	f := newAuthFixture(t)
+
+	for i := 0; i < 2; i++ {
+		if err := f.login("wrong"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("attempt %d: expected ErrInvalidCredentials, got %v", i+1, err)
+		}
+	}
+
+	err := f.login("wrong")
+	var locked *AccountLockedError
+	if !errors.As(err, &locked) || !errors.Is(err, ErrAccountLocked) || locked.ClientIP {
+		t.Fatalf("expected the third failure to lock the account, got %v", err)
+	}
+	if !locked.Until.Equal(f.clock.Add(time.Minute)) {
+		t.Fatalf("expected a one minute lock, got until %v", locked.Until)
+	}
+	if len(f.emails.sent) != 1 || f.emails.sent[0].To != "alice@example.com" {
+		t.Fatalf("expected a lock notification email, got %+v", f.emails.sent)
+	}
+
+	if err := f.login("correct-horse"); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("expected the right password to be refused while locked, got %v", err)
+	}
+
+	*f.clock = f.clock.Add(time.Minute + time.Second)
+	if err := f.login("correct-horse"); err != nil {
+		t.Fatalf("expected login to succeed after the lock expires: %v", err)
+	}
+	if _, ok := f.throttles.rows["user:u1"]; ok {
+		t.Fatalf("expected a successful login to reset the user's failures")
+	}
+}
+
+func TestAuthServiceLockoutBacksOffExponentially(t *testing.T) {
+	f := newAuthFixture(t)
+	var durations []time.Duration
+
+	for lock := 0; lock < 4; lock++ {
+		var err error
+		for i := 0; i < 3; i++ {
+			err = f.login("wrong")
+		}
+		var locked *AccountLockedError
+		if !errors.As(err, &locked) {
+			t.Fatalf("lock %d: expected account to lock, got %v", lock+1, err)
+		}
+		durations = append(durations, locked.Until.Sub(*f.clock))
+		*f.clock = locked.Until.Add(time.Second)
+	}
+
+	want := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 4 * time.Minute}
+	for i := range want {
+		if durations[i] != want[i] {
+			t.Fatalf("expected lock durations %v, got %v", want, durations)
+		}
+	}
+}
+
+func TestAuthServiceLoginLocksIPAcrossUnknownAccounts(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+
+	var err error
+	for i := 0; i < 20; i++ {
+		_, _, err = f.svc.Login(ctx, LoginInput{Email: "nobody@example.com", Password: "guess", IPAddress: "203.0.113.9"})
+	}
+	var locked *AccountLockedError
+	if !errors.As(err, &locked) || !locked.ClientIP {
+		t.Fatalf("expected the IP to be locked after 20 failures, got %v", err)
+	}
+
+	_, _, err = f.svc.Login(ctx, LoginInput{Email: "alice@example.com", Password: "correct-horse", IPAddress: "203.0.113.9"})
+	if !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("expected the locked IP to be refused, got %v", err)
+	}
+	if len(f.emails.sent) != 0 {
+		t.Fatalf("expected no email for an IP lock, got %+v", f.emails.sent)
+	}
+
+	_, _, err = f.svc.Login(ctx, LoginInput{Email: "alice@example.com", Password: "correct-horse", IPAddress: "198.51.100.7"})
+	if err != nil {
+		t.Fatalf("expected other IPs to be unaffected: %v", err)
+	}
+}
+
+func TestAuthServicePrunesIdleLoginThrottles(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+
+	if _, _, err := f.svc.Login(ctx, LoginInput{Email: "nobody@example.com", Password: "guess", IPAddress: "203.0.113.9"}); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+	}
+	*f.clock = f.clock.Add(23 * time.Hour)
+	for i := 0; i < 3; i++ {
+		_ = f.login("wrong")
+	}
+	if len(f.throttles.rows) != 3 {
+		t.Fatalf("expected throttles for both IPs and the user, got %v", f.throttles.rows)
+	}
+
+	*f.clock = f.clock.Add(2 * time.Hour)
+	if pruned, err := f.svc.PruneLoginThrottles(ctx); err != nil || pruned != 1 {
+		t.Fatalf("expected only the idle IP to be pruned, got %d %v", pruned, err)
+	}
+	if _, ok := f.throttles.rows["ip:203.0.113.9"]; ok {
+		t.Fatalf("expected the idle IP throttle to be gone")
+	}
+
+	lockedUntil := f.clock.Add(48 * time.Hour)
+	f.throttles.rows["user:u1"].LockedUntil = &lockedUntil
+	*f.clock = f.clock.Add(24 * time.Hour)
+	if pruned, err := f.svc.PruneLoginThrottles(ctx); err != nil || pruned != 1 {
+		t.Fatalf("expected the still-locked user to be kept, got %d %v", pruned, err)
+	}
+	if _, ok := f.throttles.rows["user:u1"]; !ok {
+		t.Fatalf("expected a locked user's throttle to survive pruning")
+	}
+}
+
+func TestAuthServiceRefreshRotatesWithinFamily(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+
+	_, first, err := f.svc.Login(ctx, LoginInput{Email: "alice@example.com", Password: "correct-horse"})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	second, err := f.svc.Refresh(ctx, RefreshInput{RefreshToken: first.RefreshToken})
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatalf("expected a new refresh token")
+	}
+
+	if len(f.refresh.tokens) != 2 {
+		t.Fatalf("expected two stored tokens, got %d", len(f.refresh.tokens))
+	}
+	if f.refresh.tokens[0].RevokedAt == nil || f.refresh.tokens[1].RevokedAt != nil {
+		t.Fatalf("expected only the presented token to be revoked: %+v", f.refresh.tokens)
+	}
+	if f.refresh.tokens[0].FamilyID != f.refresh.tokens[1].FamilyID {
+		t.Fatalf("expected the rotated token to stay in its family: %+v", f.refresh.tokens)
+	}
+}
+
+func TestAuthServiceRefreshReuseRevokesFamily(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+
+	_, first, err := f.svc.Login(ctx, LoginInput{Email: "alice@example.com", Password: "correct-horse"})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	_, other, err := f.svc.Login(ctx, LoginInput{Email: "alice@example.com", Password: "correct-horse"})
+	if err != nil {
+		t.Fatalf("second login: %v", err)
+	}
+	second, err := f.svc.Refresh(ctx, RefreshInput{RefreshToken: first.RefreshToken})
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	if _, err := f.svc.Refresh(ctx, RefreshInput{RefreshToken: first.RefreshToken}); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected replayed token to be rejected, got %v", err)
+	}
+	if _, err := f.svc.Refresh(ctx, RefreshInput{RefreshToken: second.RefreshToken}); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected the descendant token to be revoked with its family, got %v", err)
+	}
+	if _, err := f.svc.Refresh(ctx, RefreshInput{RefreshToken: other.RefreshToken}); err != nil {
+		t.Fatalf("expected other sessions to be unaffected: %v", err)
+	}
+}
+
+func TestAuthServiceConfirmPasswordResetSignsOutEverywhere(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+
+	_, session, err := f.svc.Login(ctx, LoginInput{Email: "alice@example.com", Password: "correct-horse"})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	pats := NewPersonalAccessTokenService(f.users, f.pats, newTestAudit(), fakeTransactor{}, defaultPolicy())
+	pat, err := pats.Create(ctx, CreatePersonalAccessTokenInput{UserID: "u1", Name: "ci", Scopes: []string{ScopePostsRead}})
+	if err != nil {
+		t.Fatalf("create personal access token: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := f.svc.RequestPasswordReset(ctx, RequestResetInput{Email: "alice@example.com"}); err != nil {
+			t.Fatalf("request reset: %v", err)
+		}
+	}
+	resetToken := func(i int) string {
+		_, token, _ := strings.Cut(f.emails.sent[i].Body, "token=")
+		return token
+	}
+	first, second := resetToken(0), resetToken(1)
+
+	if err := f.svc.ConfirmPasswordReset(ctx, ConfirmResetInput{Token: first, NewPassword: "battery-staple"}); err != nil {
+		t.Fatalf("confirm reset: %v", err)
+	}
+
+	if _, err := f.svc.Refresh(ctx, RefreshInput{RefreshToken: session.RefreshToken}); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected existing sessions to be revoked, got %v", err)
+	}
+	if _, err := pats.Authenticate(ctx, pat.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected personal access tokens to be revoked, got %v", err)
+	}
+	if err := f.svc.ConfirmPasswordReset(ctx, ConfirmResetInput{Token: second, NewPassword: "another-one"}); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected other reset tokens to be invalidated, got %v", err)
+	}
+	if len(f.emails.sent) != 3 || f.emails.sent[2].Subject != "Your password was changed" {
+		t.Fatalf("expected a password changed email, got %+v", f.emails.sent)
+	}
+	if _, _, err := f.svc.Login(ctx, LoginInput{Email: "alice@example.com", Password: "battery-staple"}); err != nil {
+		t.Fatalf("expected login with the new password: %v", err)
+	}
+}
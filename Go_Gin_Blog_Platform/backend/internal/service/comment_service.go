@@ -0,0 +1,459 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/policy"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+const (
+	maxCommentLength = 5000
+	// maxThreadComments bounds a single thread response; threads are returned
+	// whole so replies can be nested under their parents.
+	maxThreadComments = 1000
+)
+
+var ErrCommentNotFound = errors.New("comment not found")
+
+type CreateCommentInput struct {
+	PostID    string
+	ParentID  *string
+	ActorID   string
+	ActorRole string
+	Body      string
+}
+
+type ListCommentsInput struct {
+	PostID     string
+	ViewerID   string
+	ViewerRole string
+	Status     string
+}
+
+type UpdateCommentInput struct {
+	CommentID string
+	ActorID   string
+	ActorRole string
+	Body      string
+}
+
+type DeleteCommentInput struct {
+	CommentID string
+	ActorID   string
+	ActorRole string
+}
+
+type ModerateCommentInput struct {
+	CommentID string
+	ActorID   string
+	ActorRole string
+	Status    string
+}
+
+type ListModerationQueueInput struct {
+	ActorRole string
+	Status    string
+	Page      int
+	Limit     int
+}
+
+type CommentItem struct {
+	ID        string               `json:"id"`
+	PostID    string               `json:"post_id"`
+	ParentID  *string              `json:"parent_id"`
+	AuthorID  string               `json:"author_id"`
+	Body      string               `json:"body"`
+	Status    models.CommentStatus `json:"status"`
+	Deleted   bool                 `json:"deleted"`
+	CreatedAt time.Time            `json:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+type CommentNode struct {
+	CommentItem
+	Replies []CommentNode `json:"replies"`
+}
+
+// CommentThread is a post's comment tree. Truncated is set when the thread
+// holds more than maxThreadComments comments and the newest were left out.
+type CommentThread struct {
+	Comments  []CommentNode
+	Truncated bool
+}
+
+type CommentService struct {
+	comments repository.CommentRepository
+	posts    repository.PostRepository
+	audit    *AuditService
+	tx       repository.Transactor
+	policy   *policy.Policy
+}
+
+func NewCommentService(
+	comments repository.CommentRepository,
+	posts repository.PostRepository,
+	audit *AuditService,
+	tx repository.Transactor,
+	authz *policy.Policy,
+) *CommentService {
+	return &CommentService{comments: comments, posts: posts, audit: audit, tx: tx, policy: authz}
+}
+
+// Create adds a comment to a post the actor can see. Comments start pending
+// unless the actor already moderates the post.
+func (s *CommentService) Create(ctx context.Context, input CreateCommentInput) (CommentItem, error) {
+	actorID := strings.TrimSpace(input.ActorID)
+	if actorID == "" {
+		return CommentItem{}, ErrForbidden
+	}
+
+	body, err := normalizeCommentBody(input.Body)
+	if err != nil {
+		return CommentItem{}, err
+	}
+
+	post, err := s.getPost(ctx, input.PostID)
+	if err != nil {
+		return CommentItem{}, err
+	}
+	if !canViewPost(s.policy, input.ActorRole, actorID, *post) {
+		return CommentItem{}, ErrPostNotFound
+	}
+
+	var parentID *string
+	if input.ParentID != nil && strings.TrimSpace(*input.ParentID) != "" {
+		parent, err := s.getComment(ctx, *input.ParentID)
+		if err != nil {
+			if errors.Is(err, ErrCommentNotFound) {
+				return CommentItem{}, fmt.Errorf("parent comment not found: %w", ErrValidation)
+			}
+			return CommentItem{}, err
+		}
+		if parent.PostID != post.ID {
+			return CommentItem{}, fmt.Errorf("parent comment belongs to another post: %w", ErrValidation)
+		}
+		if parent.DeletedAt != nil {
+			return CommentItem{}, fmt.Errorf("cannot reply to a deleted comment: %w", ErrValidation)
+		}
+		parentID = &parent.ID
+	}
+
+	status := models.CommentStatusPending
+	if s.moderates(input.ActorRole, actorID, post) {
+		status = models.CommentStatusApproved
+	}
+
+	comment := models.Comment{
+		PostID:   post.ID,
+		ParentID: parentID,
+		AuthorID: actorID,
+		Body:     body,
+		Status:   status,
+	}
+	if err := s.comments.Create(ctx, &comment); err != nil {
+		return CommentItem{}, fmt.Errorf("create comment: %w", err)
+	}
+	return toCommentItem(comment), nil
+}
+
+// List returns the comment thread of a post as a tree. Readers see approved
+// comments plus their own; moderators of the post may filter by any status.
+func (s *CommentService) List(ctx context.Context, input ListCommentsInput) (CommentThread, error) {
+	post, err := s.getPost(ctx, input.PostID)
+	if err != nil {
+		return CommentThread{}, err
+	}
+	if !canViewPost(s.policy, input.ViewerRole, input.ViewerID, *post) {
+		return CommentThread{}, ErrPostNotFound
+	}
+
+	filter := repository.CommentListFilter{
+		PostID:          post.ID,
+		Statuses:        []models.CommentStatus{models.CommentStatusApproved},
+		IncludeAuthorID: strings.TrimSpace(input.ViewerID),
+	}
+	if strings.TrimSpace(input.Status) != "" {
+		if !s.moderates(input.ViewerRole, input.ViewerID, post) {
+			return CommentThread{}, ErrForbidden
+		}
+		status, err := parseCommentStatus(input.Status)
+		if err != nil {
+			return CommentThread{}, err
+		}
+		filter.Statuses = []models.CommentStatus{status}
+		filter.IncludeAuthorID = ""
+	}
+
+	comments, total, err := s.comments.List(ctx, filter, maxThreadComments, 0)
+	if err != nil {
+		return CommentThread{}, fmt.Errorf("list comments: %w", err)
+	}
+	return CommentThread{Comments: buildCommentTree(comments), Truncated: total > int64(len(comments))}, nil
+}
+
+// Update edits the body of the actor's own comment. An approved comment goes
+// back to pending unless its author moderates the post.
+func (s *CommentService) Update(ctx context.Context, input UpdateCommentInput) (CommentItem, error) {
+	body, err := normalizeCommentBody(input.Body)
+	if err != nil {
+		return CommentItem{}, err
+	}
+
+	comment, err := s.getComment(ctx, input.CommentID)
+	if err != nil {
+		return CommentItem{}, err
+	}
+	if comment.DeletedAt != nil {
+		return CommentItem{}, ErrCommentNotFound
+	}
+	if strings.TrimSpace(input.ActorID) == "" || comment.AuthorID != strings.TrimSpace(input.ActorID) {
+		return CommentItem{}, ErrForbidden
+	}
+
+	post, err := s.getPost(ctx, comment.PostID)
+	if err != nil {
+		return CommentItem{}, err
+	}
+
+	updates := map[string]any{"body": body}
+	if comment.Status == models.CommentStatusApproved && !s.moderates(input.ActorRole, input.ActorID, post) {
+		updates["status"] = models.CommentStatusPending
+	}
+	if err := s.comments.Update(ctx, comment.ID, updates); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return CommentItem{}, ErrCommentNotFound
+		}
+		return CommentItem{}, fmt.Errorf("update comment: %w", err)
+	}
+	return s.reload(ctx, comment.ID)
+}
+
+// Delete removes a comment on behalf of its author or a moderator of the post.
+// Comments with replies are tombstoned so the thread below them survives.
+func (s *CommentService) Delete(ctx context.Context, input DeleteCommentInput) error {
+	comment, err := s.getComment(ctx, input.CommentID)
+	if err != nil {
+		return err
+	}
+	if comment.DeletedAt != nil {
+		return ErrCommentNotFound
+	}
+
+	if strings.TrimSpace(input.ActorID) == "" || comment.AuthorID != strings.TrimSpace(input.ActorID) {
+		post, err := s.getPost(ctx, comment.PostID)
+		if err != nil {
+			return err
+		}
+		if !s.moderates(input.ActorRole, input.ActorID, post) {
+			return ErrForbidden
+		}
+	}
+
+	hasReplies, err := s.comments.HasReplies(ctx, comment.ID)
+	if err != nil {
+		return fmt.Errorf("check comment replies: %w", err)
+	}
+	if hasReplies {
+		err = s.comments.Update(ctx, comment.ID, map[string]any{"body": "", "deleted_at": time.Now().UTC()})
+	} else {
+		err = s.comments.Delete(ctx, comment.ID)
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrCommentNotFound
+		}
+		return fmt.Errorf("delete comment: %w", err)
+	}
+	return nil
+}
+
+// Moderate sets the status of a comment. Users with comment.moderate may
+// moderate any comment and post authors the comments on their own posts.
+// Tombstoned comments cannot be moderated.
+func (s *CommentService) Moderate(ctx context.Context, input ModerateCommentInput) (CommentItem, error) {
+	status, err := parseCommentStatus(input.Status)
+	if err != nil {
+		return CommentItem{}, err
+	}
+
+	comment, err := s.getComment(ctx, input.CommentID)
+	if err != nil {
+		return CommentItem{}, err
+	}
+	if comment.DeletedAt != nil {
+		return CommentItem{}, ErrCommentNotFound
+	}
+
+	post, err := s.getPost(ctx, comment.PostID)
+	if err != nil {
+		return CommentItem{}, err
+	}
+	if !s.moderates(input.ActorRole, input.ActorID, post) {
+		return CommentItem{}, ErrForbidden
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.comments.Update(ctx, comment.ID, map[string]any{"status": status}); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditRecord{
+			ActorID:    input.ActorID,
+			Action:     AuditCommentModerate,
+			TargetType: AuditTargetComment,
+			TargetID:   comment.ID,
+			Before:     map[string]any{"status": comment.Status, "post_id": comment.PostID},
+			After:      map[string]any{"status": status, "post_id": comment.PostID},
+		})
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return CommentItem{}, ErrCommentNotFound
+		}
+		return CommentItem{}, fmt.Errorf("moderate comment: %w", err)
+	}
+	return s.reload(ctx, comment.ID)
+}
+
+// ListModerationQueue lists comments across all posts, oldest first, for the
+// moderation queue. It defaults to pending comments.
+func (s *CommentService) ListModerationQueue(ctx context.Context, input ListModerationQueueInput) ([]CommentItem, Pagination, error) {
+	if !s.policy.Authorize(policy.Actor{Role: input.ActorRole}, policy.CommentModerate, nil) {
+		return nil, Pagination{}, ErrForbidden
+	}
+
+	status := models.CommentStatusPending
+	if strings.TrimSpace(input.Status) != "" {
+		parsed, err := parseCommentStatus(input.Status)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+		status = parsed
+	}
+
+	page, limit := normalizePagination(input.Page, input.Limit)
+	offset := (page - 1) * limit
+
+	filter := repository.CommentListFilter{Statuses: []models.CommentStatus{status}, ExcludeDeleted: true}
+	comments, total, err := s.comments.List(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, Pagination{}, fmt.Errorf("list moderation queue: %w", err)
+	}
+
+	items := make([]CommentItem, 0, len(comments))
+	for _, comment := range comments {
+		items = append(items, toCommentItem(comment))
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	return items, Pagination{Page: page, Limit: limit, Total: total, TotalPages: totalPages}, nil
+}
+
+func (s *CommentService) getPost(ctx context.Context, postID string) (*models.Post, error) {
+	post, err := s.posts.GetByID(ctx, strings.TrimSpace(postID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrPostNotFound
+		}
+		return nil, fmt.Errorf("get post: %w", err)
+	}
+	return post, nil
+}
+
+func (s *CommentService) getComment(ctx context.Context, commentID string) (*models.Comment, error) {
+	comment, err := s.comments.GetByID(ctx, strings.TrimSpace(commentID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("get comment: %w", err)
+	}
+	return comment, nil
+}
+
+func (s *CommentService) reload(ctx context.Context, commentID string) (CommentItem, error) {
+	comment, err := s.getComment(ctx, commentID)
+	if err != nil {
+		return CommentItem{}, err
+	}
+	return toCommentItem(*comment), nil
+}
+
+func normalizeCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("comment body is required: %w", ErrValidation)
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return "", fmt.Errorf("comment body exceeds %d characters: %w", maxCommentLength, ErrValidation)
+	}
+	return body, nil
+}
+
+func parseCommentStatus(status string) (models.CommentStatus, error) {
+	s := models.CommentStatus(strings.ToLower(strings.TrimSpace(status)))
+	switch s {
+	case models.CommentStatusPending, models.CommentStatusApproved, models.CommentStatusSpam, models.CommentStatusRejected:
+		return s, nil
+	}
+	return "", fmt.Errorf("comment status must be pending, approved, spam or rejected: %w", ErrValidation)
+}
+
+// buildCommentTree nests comments under their parents, keeping the input order
+// at every level. Replies whose parent is not in the list (for example because
+// it is still pending) are shown at the top level rather than dropped.
+func buildCommentTree(comments []models.Comment) []CommentNode {
+	children := make(map[string][]models.Comment)
+	known := make(map[string]bool, len(comments))
+	for _, comment := range comments {
+		known[comment.ID] = true
+	}
+
+	var roots []models.Comment
+	for _, comment := range comments {
+		if comment.ParentID == nil || !known[*comment.ParentID] {
+			roots = append(roots, comment)
+			continue
+		}
+		children[*comment.ParentID] = append(children[*comment.ParentID], comment)
+	}
+
+	var build func(list []models.Comment) []CommentNode
+	build = func(list []models.Comment) []CommentNode {
+		nodes := make([]CommentNode, 0, len(list))
+		for _, comment := range list {
+			nodes = append(nodes, CommentNode{
+				CommentItem: toCommentItem(comment),
+				Replies:     build(children[comment.ID]),
+			})
+		}
+		return nodes
+	}
+	return build(roots)
+}
+
+func toCommentItem(comment models.Comment) CommentItem {
+	return CommentItem{
+		ID:        comment.ID,
+		PostID:    comment.PostID,
+		ParentID:  comment.ParentID,
+		AuthorID:  comment.AuthorID,
+		Body:      comment.Body,
+		Status:    comment.Status,
+		Deleted:   comment.DeletedAt != nil,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}
+}
+
+// moderates reports whether the actor moderates the comments on post: its
+// author does, as does anyone holding comment.moderate.
+func (s *CommentService) moderates(actorRole, actorID string, post *models.Post) bool {
+	return s.policy.Authorize(policy.Actor{ID: actorID, Role: actorRole}, policy.CommentModerate, &policy.Resource{OwnerID: post.AuthorID})
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+type fakeCommentRepo struct {
+	comments   []models.Comment
+	lastFilter repository.CommentListFilter
+}
+
+func (f *fakeCommentRepo) Create(_ context.Context, comment *models.Comment) error {
+	comment.ID = fmt.Sprintf("c%d", len(f.comments)+1)
+	comment.CreatedAt = time.Now().UTC()
+	comment.UpdatedAt = comment.CreatedAt
+	f.comments = append(f.comments, *comment)
+	return nil
+}
+
+func (f *fakeCommentRepo) GetByID(_ context.Context, id string) (*models.Comment, error) {
+	for _, comment := range f.comments {
+		if comment.ID == id {
+			copy := comment
+			return &copy, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeCommentRepo) List(_ context.Context, filter repository.CommentListFilter, limit, offset int) ([]models.Comment, int64, error) {
+	f.lastFilter = filter
+	var matched []models.Comment
+	for _, comment := range f.comments {
+		if filter.PostID != "" && comment.PostID != filter.PostID {
+			continue
+		}
+		if len(filter.Statuses) > 0 && !hasCommentStatus(filter.Statuses, comment.Status) && comment.AuthorID != filter.IncludeAuthorID {
+			continue
+		}
+		if filter.ExcludeDeleted && comment.DeletedAt != nil {
+			continue
+		}
+		matched = append(matched, comment)
+	}
+	total := int64(len(matched))
+	if offset >= len(matched) {
+		return nil, total, nil
+	}
+	matched = matched[offset:]
+	if len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched, total, nil
+}
+
+func (f *fakeCommentRepo) Update(_ context.Context, id string, updates map[string]any) error {
+	for i := range f.comments {
+		if f.comments[i].ID != id {
+			continue
+		}
+		if body, ok := updates["body"].(string); ok {
+			f.comments[i].Body = body
+		}
+		if status, ok := updates["status"].(models.CommentStatus); ok {
+			f.comments[i].Status = status
+		}
+		if deletedAt, ok := updates["deleted_at"].(time.Time); ok {
+			f.comments[i].DeletedAt = &deletedAt
+		}
+		return nil
+	}
+	return repository.ErrNotFound
+}
+
+func (f *fakeCommentRepo) HasReplies(_ context.Context, id string) (bool, error) {
+	for _, comment := range f.comments {
+		if comment.ParentID != nil && *comment.ParentID == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeCommentRepo) Delete(_ context.Context, id string) error {
+	for i := range f.comments {
+		if f.comments[i].ID == id {
+			f.comments = append(f.comments[:i], f.comments[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func hasCommentStatus(statuses []models.CommentStatus, status models.CommentStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func newCommentServiceFixture() (*CommentService, *fakeCommentRepo) {
+	posts := &fakePostRepo{post: models.Post{ID: "p1", AuthorID: "owner", Title: "T", Content: "C", Status: models.PostStatusPublished}}
+	comments := &fakeCommentRepo{}
+	return NewCommentService(comments, posts, newTestAudit(), fakeTransactor{}, defaultPolicy()), comments
+}
+
+func TestCommentServiceCreateModerationDefaults(t *testing.T) {
+	svc, _ := newCommentServiceFixture()
+	ctx := context.Background()
+
+	reader, err := svc.Create(ctx, CreateCommentInput{PostID: "p1", ActorID: "reader-1", ActorRole: "reader", Body: "  Nice post  "})
+	if err != nil {
+		t.Fatalf("expected reader comment to succeed: %v", err)
+	}
+	if reader.Status != models.CommentStatusPending || reader.Body != "Nice post" {
+		t.Fatalf("expected trimmed pending comment, got %+v", reader)
+	}
+
+	owner, err := svc.Create(ctx, CreateCommentInput{PostID: "p1", ActorID: "owner", ActorRole: "author", Body: "Thanks"})
+	if err != nil {
+		t.Fatalf("expected post author comment to succeed: %v", err)
+	}
+	if owner.Status != models.CommentStatusApproved {
+		t.Fatalf("expected post author comment to be approved, got %s", owner.Status)
+	}
+}
+
+func TestCommentServiceCreateValidatesBodyAndParent(t *testing.T) {
+	svc, comments := newCommentServiceFixture()
+	ctx := context.Background()
+
+	if _, err := svc.Create(ctx, CreateCommentInput{PostID: "p1", ActorID: "u1", ActorRole: "reader", Body: "   "}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected empty body to be rejected, got %v", err)
+	}
+	if _, err := svc.Create(ctx, CreateCommentInput{PostID: "p1", ActorID: "u1", ActorRole: "reader", Body: strings.Repeat("x", maxCommentLength+1)}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected long body to be rejected, got %v", err)
+	}
+
+	comments.comments = append(comments.comments, models.Comment{ID: "other", PostID: "p2", AuthorID: "u2", Body: "x", Status: models.CommentStatusApproved})
+	parentID := "other"
+	if _, err := svc.Create(ctx, CreateCommentInput{PostID: "p1", ParentID: &parentID, ActorID: "u1", ActorRole: "reader", Body: "reply"}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected cross-post parent to be rejected, got %v", err)
+	}
+
+	if _, err := svc.Create(ctx, CreateCommentInput{PostID: "missing", ActorID: "u1", ActorRole: "reader", Body: "hi"}); !errors.Is(err, ErrPostNotFound) {
+		t.Fatalf("expected missing post to be reported, got %v", err)
+	}
+}
+
+func TestCommentServiceListBuildsThreadAndHidesPending(t *testing.T) {
+	svc, comments := newCommentServiceFixture()
+	root := "c1"
+	comments.comments = []models.Comment{
+		{ID: "c1", PostID: "p1", AuthorID: "u1", Body: "root", Status: models.CommentStatusApproved},
+		{ID: "c2", PostID: "p1", ParentID: &root, AuthorID: "u2", Body: "reply", Status: models.CommentStatusApproved},
+		{ID: "c3", PostID: "p1", ParentID: &root, AuthorID: "u3", Body: "pending reply", Status: models.CommentStatusPending},
+	}
+
+	thread, err := svc.List(context.Background(), ListCommentsInput{PostID: "p1"})
+	if err != nil {
+		t.Fatalf("expected list to succeed: %v", err)
+	}
+	if len(thread.Comments) != 1 || len(thread.Comments[0].Replies) != 1 || thread.Comments[0].Replies[0].ID != "c2" || thread.Truncated {
+		t.Fatalf("expected one root with one approved reply, got %+v", thread)
+	}
+
+	thread, err = svc.List(context.Background(), ListCommentsInput{PostID: "p1", ViewerID: "u3", ViewerRole: "reader"})
+	if err != nil {
+		t.Fatalf("expected list to succeed: %v", err)
+	}
+	if len(thread.Comments[0].Replies) != 2 {
+		t.Fatalf("expected commenter to see their own pending reply, got %+v", thread.Comments[0].Replies)
+	}
+}
+
+func TestCommentServiceListFlagsTruncatedThreads(t *testing.T) {
+	svc, comments := newCommentServiceFixture()
+	for i := 0; i <= maxThreadComments; i++ {
+		comments.comments = append(comments.comments, models.Comment{ID: fmt.Sprintf("c%d", i), PostID: "p1", AuthorID: "u1", Status: models.CommentStatusApproved})
+	}
+
+	thread, err := svc.List(context.Background(), ListCommentsInput{PostID: "p1"})
+	if err != nil {
+		t.Fatalf("expected list to succeed: %v", err)
+	}
+	if len(thread.Comments) != maxThreadComments || !thread.Truncated {
+		t.Fatalf("expected %d comments flagged as truncated, got %d truncated=%v", maxThreadComments, len(thread.Comments), thread.Truncated)
+	}
+}
+
+func TestCommentServiceListStatusFilterRequiresModerator(t *testing.T) {
+	svc, comments := newCommentServiceFixture()
+	ctx := context.Background()
+
+	if _, err := svc.List(ctx, ListCommentsInput{PostID: "p1", ViewerID: "u1", ViewerRole: "reader", Status: "pending"}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected status filter to be forbidden for readers, got %v", err)
+	}
+	if _, err := svc.List(ctx, ListCommentsInput{PostID: "p1", ViewerID: "owner", ViewerRole: "author", Status: "pending"}); err != nil {
+		t.Fatalf("expected post author to filter by status: %v", err)
+	}
+	if comments.lastFilter.Statuses[0] != models.CommentStatusPending || comments.lastFilter.IncludeAuthorID != "" {
+		t.Fatalf("unexpected filter: %+v", comments.lastFilter)
+	}
+}
+
+func TestCommentServiceEditResetsApprovedToPending(t *testing.T) {
+	svc, comments := newCommentServiceFixture()
+	comments.comments = []models.Comment{{ID: "c1", PostID: "p1", AuthorID: "u1", Body: "old", Status: models.CommentStatusApproved}}
+	ctx := context.Background()
+
+	if _, err := svc.Update(ctx, UpdateCommentInput{CommentID: "c1", ActorID: "owner", ActorRole: "author", Body: "hijack"}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected only the comment author to edit, got %v", err)
+	}
+
+	updated, err := svc.Update(ctx, UpdateCommentInput{CommentID: "c1", ActorID: "u1", ActorRole: "reader", Body: "new"})
+	if err != nil {
+		t.Fatalf("expected edit to succeed: %v", err)
+	}
+	if updated.Body != "new" || updated.Status != models.CommentStatusPending {
+		t.Fatalf("expected edited comment back in moderation, got %+v", updated)
+	}
+}
+
+func TestCommentServiceDeleteTombstonesCommentsWithReplies(t *testing.T) {
+	svc, comments := newCommentServiceFixture()
+	root := "c1"
+	comments.comments = []models.Comment{
+		{ID: "c1", PostID: "p1", AuthorID: "u1", Body: "root", Status: models.CommentStatusApproved},
+		{ID: "c2", PostID: "p1", ParentID: &root, AuthorID: "u2", Body: "reply", Status: models.CommentStatusApproved},
+	}
+	ctx := context.Background()
+
+	if err := svc.Delete(ctx, DeleteCommentInput{CommentID: "c1", ActorID: "u2", ActorRole: "reader"}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected other readers to be forbidden, got %v", err)
+	}
+	if err := svc.Delete(ctx, DeleteCommentInput{CommentID: "c1", ActorID: "u1", ActorRole: "reader"}); err != nil {
+		t.Fatalf("expected owner delete to succeed: %v", err)
+	}
+	if len(comments.comments) != 2 || comments.comments[0].DeletedAt == nil || comments.comments[0].Body != "" {
+		t.Fatalf("expected parent to be tombstoned, got %+v", comments.comments)
+	}
+
+	if err := svc.Delete(ctx, DeleteCommentInput{CommentID: "c2", ActorID: "owner", ActorRole: "author"}); err != nil {
+		t.Fatalf("expected post author to delete a reply: %v", err)
+	}
+	if len(comments.comments) != 1 {
+		t.Fatalf("expected leaf comment to be removed, got %+v", comments.comments)
+	}
+}
+
+func TestCommentServiceModerateReusesPostOwnership(t *testing.T) {
+	svc, comments := newCommentServiceFixture()
+	events := &fakeAuditRepo{}
+	svc.audit = NewAuditService(events, defaultPolicy())
+	comments.comments = []models.Comment{{ID: "c1", PostID: "p1", AuthorID: "u1", Body: "buy now", Status: models.CommentStatusPending}}
+	ctx := context.Background()
+
+	if _, err := svc.Moderate(ctx, ModerateCommentInput{CommentID: "c1", ActorID: "other-author", ActorRole: "author", Status: "spam"}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected other authors to be forbidden, got %v", err)
+	}
+	if _, err := svc.Moderate(ctx, ModerateCommentInput{CommentID: "c1", ActorID: "owner", ActorRole: "author", Status: "bogus"}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected unknown status to be rejected, got %v", err)
+	}
+
+	moderated, err := svc.Moderate(ctx, ModerateCommentInput{CommentID: "c1", ActorID: "owner", ActorRole: "author", Status: "spam"})
+	if err != nil {
+		t.Fatalf("expected post author to moderate: %v", err)
+	}
+	if moderated.Status != models.CommentStatusSpam {
+		t.Fatalf("expected spam status, got %s", moderated.Status)
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("expected only the applied moderation to be audited, got %+v", events.events)
+	}
+	event := events.events[0]
+	if event.Action != AuditCommentModerate || *event.ActorID != "owner" || event.TargetType != AuditTargetComment || event.TargetID != "c1" {
+		t.Fatalf("unexpected audit event %+v", event)
+	}
+	if !strings.Contains(string(event.Before), `"pending"`) || !strings.Contains(string(event.After), `"spam"`) {
+		t.Fatalf("expected the status change in before and after, got %s -> %s", event.Before, event.After)
+	}
+}
+
+func TestCommentServiceModerateRejectsTombstones(t *testing.T) {
+	svc, comments := newCommentServiceFixture()
+	deletedAt := time.Now().UTC()
+	comments.comments = []models.Comment{{ID: "c1", PostID: "p1", AuthorID: "u1", Status: models.CommentStatusPending, DeletedAt: &deletedAt}}
+
+	if _, err := svc.Moderate(context.Background(), ModerateCommentInput{CommentID: "c1", ActorID: "owner", ActorRole: "author", Status: "approved"}); !errors.Is(err, ErrCommentNotFound) {
+		t.Fatalf("expected a tombstone to be refused, got %v", err)
+	}
+	if comments.comments[0].Status != models.CommentStatusPending {
+		t.Fatalf("expected the tombstone to keep its status, got %s", comments.comments[0].Status)
+	}
+}
+
+func TestCommentServiceModerationQueueDefaultsToPending(t *testing.T) {
+	svc, comments := newCommentServiceFixture()
+	ctx := context.Background()
+
+	if _, _, err := svc.ListModerationQueue(ctx, ListModerationQueueInput{ActorRole: "author"}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected queue to be admin only, got %v", err)
+	}
+	if _, _, err := svc.ListModerationQueue(ctx, ListModerationQueueInput{ActorRole: "admin"}); err != nil {
+		t.Fatalf("expected queue to load: %v", err)
+	}
+	if comments.lastFilter.PostID != "" || comments.lastFilter.Statuses[0] != models.CommentStatusPending || !comments.lastFilter.ExcludeDeleted {
+		t.Fatalf("unexpected queue filter: %+v", comments.lastFilter)
+	}
+}
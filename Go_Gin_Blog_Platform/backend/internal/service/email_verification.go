@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+var ErrEmailAlreadyVerified = errors.New("email already verified")
+
+type VerifyEmailInput struct {
+	Token string
+}
+
+// VerifyEmail marks the address a verification link was sent to as verified.
+// Access tokens issued before that still say unverified until refreshed.
+func (s *AuthService) VerifyEmail(ctx context.Context, input VerifyEmailInput) error {
+	rawToken := strings.TrimSpace(input.Token)
+	if rawToken == "" {
+		return fmt.Errorf("verify email input invalid: %w", ErrValidation)
+	}
+
+	storedToken, err := s.verifications.GetActiveByHash(ctx, auth.HashToken(rawToken))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrInvalidToken
+		}
+		return fmt.Errorf("load verification token: %w", err)
+	}
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.verifications.MarkUsedByID(ctx, storedToken.ID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrInvalidToken
+			}
+			return fmt.Errorf("mark verification token used: %w", err)
+		}
+		if err := s.users.MarkEmailVerified(ctx, storedToken.UserID, s.now()); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrInvalidToken
+			}
+			return fmt.Errorf("mark email verified: %w", err)
+		}
+		if _, err := s.verifications.InvalidateAllForUser(ctx, storedToken.UserID); err != nil {
+			return fmt.Errorf("invalidate other verification tokens: %w", err)
+		}
+		return nil
+	})
+}
+
+// ResendVerification sends a fresh verification link, voiding earlier ones.
+func (s *AuthService) ResendVerification(ctx context.Context, userID string) error {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("get user for verification: %w", err)
+	}
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+
+	return s.sendVerificationEmail(ctx, user)
+}
+
+func (s *AuthService) sendVerificationEmail(ctx context.Context, user *models.User) error {
+	rawToken, err := auth.GenerateRandomToken(32)
+	if err != nil {
+		return fmt.Errorf("generate verification token: %w", err)
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.verifications.InvalidateAllForUser(ctx, user.ID); err != nil {
+			return fmt.Errorf("invalidate previous verification tokens: %w", err)
+		}
+		if err := s.verifications.Create(ctx, &models.EmailVerificationToken{
+			UserID:    user.ID,
+			TokenHash: auth.HashToken(rawToken),
+			ExpiresAt: s.now().Add(s.verificationTTL),
+		}); err != nil {
+			return fmt.Errorf("store verification token: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	verifyURL := s.frontendBaseURL + "/verify-email?token=" + url.QueryEscape(rawToken)
+	s.sendAccountNotice(ctx, user.Email, "Verify your email address",
+		"Use this link to verify your email address: "+verifyURL)
+	return nil
+}
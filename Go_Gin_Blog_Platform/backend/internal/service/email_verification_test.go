@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAuthServiceRegisterRequiresEmailVerification(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+
+	user, pair, err := f.svc.Register(ctx, RegisterInput{Email: "carol@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if user.EmailVerified {
+		t.Fatalf("expected a new account to start unverified")
+	}
+	claims, err := f.svc.tokenManager.ParseAccessToken(pair.AccessToken)
+	if err != nil || claims.EmailVerified {
+		t.Fatalf("expected an unverified access token, got %+v (%v)", claims, err)
+	}
+	if len(f.emails.sent) != 1 || f.emails.sent[0].To != "carol@example.com" {
+		t.Fatalf("expected a verification email, got %+v", f.emails.sent)
+	}
+
+	if err := f.svc.ResendVerification(ctx, user.ID); err != nil {
+		t.Fatalf("resend: %v", err)
+	}
+	_, stale, _ := strings.Cut(f.emails.sent[0].Body, "token=")
+	_, fresh, _ := strings.Cut(f.emails.sent[1].Body, "token=")
+	if err := f.svc.VerifyEmail(ctx, VerifyEmailInput{Token: stale}); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected a resent link to void the earlier one, got %v", err)
+	}
+	if err := f.svc.VerifyEmail(ctx, VerifyEmailInput{Token: fresh}); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+
+	refreshed, err := f.svc.Refresh(ctx, RefreshInput{RefreshToken: pair.RefreshToken})
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	claims, err = f.svc.tokenManager.ParseAccessToken(refreshed.AccessToken)
+	if err != nil || !claims.EmailVerified {
+		t.Fatalf("expected a verified access token after refresh, got %+v (%v)", claims, err)
+	}
+	if err := f.svc.ResendVerification(ctx, user.ID); !errors.Is(err, ErrEmailAlreadyVerified) {
+		t.Fatalf("expected ErrEmailAlreadyVerified, got %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/email"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+// lockoutMemory is how long a subject has to go without failures before its
+// lockout history is forgotten and backoff starts from the base again.
+const lockoutMemory = 24 * time.Hour
+
+var ErrAccountLocked = errors.New("account locked")
+
+// AccountLockedError reports a sign-in refused because the account or client
+// IP is locked. It matches ErrAccountLocked with errors.Is.
+type AccountLockedError struct {
+	Until time.Time
+	// ClientIP is set when the lock is on the client IP rather than the
+	// account.
+	ClientIP bool
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("account locked until %s", e.Until.Format(time.RFC3339))
+}
+
+func (e *AccountLockedError) Unwrap() error {
+	return ErrAccountLocked
+}
+
+// LockoutPolicy locks a user after UserThreshold failed sign-ins, and a client
+// IP after IPThreshold, within FailureWindow. Each lock lasts BaseLockout,
+// doubling for every further lock up to MaxLockout. A zero threshold disables
+// that kind of lock.
+type LockoutPolicy struct {
+	UserThreshold int
+	IPThreshold   int
+	FailureWindow time.Duration
+	BaseLockout   time.Duration
+	MaxLockout    time.Duration
+}
+
+func (p LockoutPolicy) lockDuration(previousLockouts int) time.Duration {
+	duration := p.BaseLockout
+	for i := 0; i < previousLockouts && duration < p.MaxLockout; i++ {
+		duration *= 2
+	}
+	if p.MaxLockout > 0 && duration > p.MaxLockout {
+		duration = p.MaxLockout
+	}
+	return duration
+}
+
+const ipThrottlePrefix = "ip:"
+
+func userThrottleKey(userID string) string {
+	return "user:" + userID
+}
+
+func ipThrottleKey(ip string) string {
+	return ipThrottlePrefix + ip
+}
+
+// PruneLoginThrottles deletes failure counts that have gone unused for
+// lockoutMemory; by then RecordFailure would start them from scratch anyway.
+func (s *AuthService) PruneLoginThrottles(ctx context.Context) (int64, error) {
+	now := s.now()
+	pruned, err := s.throttles.DeleteIdle(ctx, now.Add(-lockoutMemory), now)
+	if err != nil {
+		return 0, fmt.Errorf("prune login throttles: %w", err)
+	}
+	return pruned, nil
+}
+
+// checkLocked returns an AccountLockedError while key is locked.
+func (s *AuthService) checkLocked(ctx context.Context, key string) error {
+	throttle, err := s.throttles.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("check login lock: %w", err)
+	}
+	if throttle.LockedUntil != nil && throttle.LockedUntil.After(s.now()) {
+		return &AccountLockedError{Until: *throttle.LockedUntil, ClientIP: strings.HasPrefix(key, ipThrottlePrefix)}
+	}
+	return nil
+}
+
+// recordFailure counts a failed sign-in against key and locks it once the
+// threshold is reached, returning the lock if one was applied.
+func (s *AuthService) recordFailure(ctx context.Context, key string, threshold int) (*AccountLockedError, error) {
+	if threshold <= 0 {
+		return nil, nil
+	}
+
+	now := s.now()
+	throttle, err := s.throttles.RecordFailure(ctx, key, now, now.Add(-s.lockout.FailureWindow), now.Add(-lockoutMemory))
+	if err != nil {
+		return nil, err
+	}
+	if throttle.Failures < threshold {
+		return nil, nil
+	}
+
+	until := now.Add(s.lockout.lockDuration(throttle.Lockouts))
+	if err := s.throttles.Lock(ctx, key, until); err != nil {
+		return nil, err
+	}
+	return &AccountLockedError{Until: until, ClientIP: strings.HasPrefix(key, ipThrottlePrefix)}, nil
+}
+
+// recordLoginFailure tracks a failed sign-in for the user (when known) and the
+// client IP. A lock on the user is reported to them by email.
+func (s *AuthService) recordLoginFailure(ctx context.Context, user *models.User, ip string) error {
+	var locked *AccountLockedError
+	if ip != "" {
+		ipLock, err := s.recordFailure(ctx, ipThrottleKey(ip), s.lockout.IPThreshold)
+		if err != nil {
+			return fmt.Errorf("record ip login failure: %w", err)
+		}
+		locked = ipLock
+	}
+
+	if user != nil {
+		userLock, err := s.recordFailure(ctx, userThrottleKey(user.ID), s.lockout.UserThreshold)
+		if err != nil {
+			return fmt.Errorf("record user login failure: %w", err)
+		}
+		if userLock != nil {
+			s.notifyLocked(ctx, user, userLock.Until)
+			locked = userLock
+		}
+	}
+
+	if locked != nil {
+		return locked
+	}
+	return nil
+}
+
+func (s *AuthService) notifyLocked(ctx context.Context, user *models.User, until time.Time) {
+	message := email.Message{
+		To:      user.Email,
+		Subject: "Your account has been temporarily locked",
+		Body: "We locked your account after several failed sign-in attempts. " +
+			"You can sign in again after " + until.UTC().Format(time.RFC1123) + ". " +
+			"If this wasn't you, reset your password: " + s.frontendBaseURL + "/forgot-password",
+	}
+	if err := s.emailSender.Send(ctx, message); err != nil {
+		s.logger.Error("account locked email send failed", "error", err, "email", user.Email)
+	}
+}
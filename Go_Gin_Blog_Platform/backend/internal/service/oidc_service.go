@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/oidc"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+// oidcStateTTL is how long a user has to finish signing in at the provider.
+const oidcStateTTL = 10 * time.Minute
+
+var (
+	ErrOIDCProviderNotFound     = errors.New("oidc provider not found")
+	ErrOIDCProviderUnavailable  = errors.New("oidc provider unavailable")
+	ErrInvalidOIDCState         = errors.New("invalid oidc state")
+	ErrOIDCAuthenticationFailed = errors.New("oidc authentication failed")
+	ErrOIDCEmailRequired        = errors.New("oidc provider did not return an email")
+	ErrOIDCAccountConflict      = errors.New("email belongs to an account that cannot be linked")
+)
+
+// OIDCProvider is one configured OpenID provider; *oidc.Provider implements
+// it.
+type OIDCProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	Authenticate(ctx context.Context, code, codeVerifier, nonce string) (oidc.Identity, error)
+}
+
+type OIDCAuthorization struct {
+	AuthorizationURL string    `json:"authorization_url"`
+	State            string    `json:"state"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+type OIDCCallbackInput struct {
+	Provider  string
+	Code      string
+	State     string
+	IPAddress string
+	UserAgent string
+}
+
+// OIDCService signs users in through external providers. Sessions it starts
+// are ordinary ones issued by the AuthService.
+type OIDCService struct {
+	auth       *AuthService
+	identities repository.IdentityRepository
+	providers  map[string]OIDCProvider
+	now        func() time.Time
+}
+
+func NewOIDCService(authService *AuthService, identities repository.IdentityRepository, providers map[string]OIDCProvider) *OIDCService {
+	return &OIDCService{
+		auth:       authService,
+		identities: identities,
+		providers:  providers,
+		now:        func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (s *OIDCService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StartLogin records a state, nonce and PKCE verifier for a new sign-in and
+// returns the provider URL to send the browser to. The HTTP layer binds the
+// state to the browser with a cookie and checks it on the callback.
+func (s *OIDCService) StartLogin(ctx context.Context, providerName string) (OIDCAuthorization, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return OIDCAuthorization{}, ErrOIDCProviderNotFound
+	}
+
+	state, err := auth.GenerateRandomToken(32)
+	if err != nil {
+		return OIDCAuthorization{}, err
+	}
+	nonce, err := auth.GenerateRandomToken(32)
+	if err != nil {
+		return OIDCAuthorization{}, err
+	}
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return OIDCAuthorization{}, err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, oidc.CodeChallenge(verifier))
+	if err != nil {
+		return OIDCAuthorization{}, fmt.Errorf("%w: %v", ErrOIDCProviderUnavailable, err)
+	}
+
+	now := s.now()
+	if _, err := s.identities.DeleteExpiredLoginStates(ctx, now); err != nil {
+		s.auth.logger.Error("expired oidc state cleanup failed", "error", err)
+	}
+	expiresAt := now.Add(oidcStateTTL)
+	if err := s.identities.SaveLoginState(ctx, &models.OIDCLoginState{
+		StateHash:    auth.HashToken(state),
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    expiresAt,
+	}); err != nil {
+		return OIDCAuthorization{}, err
+	}
+
+	return OIDCAuthorization{AuthorizationURL: authURL, State: state, ExpiresAt: expiresAt}, nil
+}
+
+// CompleteLogin redeems the code the provider redirected back with. A new
+// provider account is linked to the user with the same email when both sides
+// have verified it, and otherwise gets a new user. Users with 2FA on get an
+// MFARequiredError, as with a password login.
+func (s *OIDCService) CompleteLogin(ctx context.Context, input OIDCCallbackInput) (RegisteredUser, TokenPair, error) {
+	provider, ok := s.providers[input.Provider]
+	if !ok {
+		return RegisteredUser{}, TokenPair{}, ErrOIDCProviderNotFound
+	}
+	code := strings.TrimSpace(input.Code)
+	rawState := strings.TrimSpace(input.State)
+	if code == "" || rawState == "" {
+		return RegisteredUser{}, TokenPair{}, fmt.Errorf("code and state are required: %w", ErrValidation)
+	}
+
+	state, err := s.identities.ConsumeLoginState(ctx, auth.HashToken(rawState), s.now())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return RegisteredUser{}, TokenPair{}, ErrInvalidOIDCState
+		}
+		return RegisteredUser{}, TokenPair{}, err
+	}
+	if state.Provider != input.Provider {
+		return RegisteredUser{}, TokenPair{}, ErrInvalidOIDCState
+	}
+
+	identity, err := provider.Authenticate(ctx, code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		if errors.Is(err, oidc.ErrExchangeFailed) || errors.Is(err, oidc.ErrInvalidIDToken) {
+			return RegisteredUser{}, TokenPair{}, fmt.Errorf("%w: %v", ErrOIDCAuthenticationFailed, err)
+		}
+		return RegisteredUser{}, TokenPair{}, fmt.Errorf("%w: %v", ErrOIDCProviderUnavailable, err)
+	}
+
+	user, linked, err := s.resolveUser(ctx, input.Provider, identity)
+	if err != nil {
+		return RegisteredUser{}, TokenPair{}, err
+	}
+
+	if err := s.auth.secondFactorChallenge(ctx, user.ID); err != nil {
+		return RegisteredUser{}, TokenPair{}, err
+	}
+
+	pair, err := s.auth.issueTokenPair(ctx, user, sessionInfo{ipAddress: input.IPAddress, userAgent: input.UserAgent})
+	if err != nil {
+		return RegisteredUser{}, TokenPair{}, err
+	}
+	// Only a sign-in that got past the second factor counts as one.
+	if err := s.identities.TouchLogin(ctx, linked.ID, s.now()); err != nil {
+		s.auth.logger.Error("identity login timestamp update failed", "error", err, "identity_id", linked.ID)
+	}
+	return registeredUser(user), pair, nil
+}
+
+// resolveUser finds the user a provider account belongs to, linking or
+// creating one on its first sign-in.
+func (s *OIDCService) resolveUser(ctx context.Context, providerName string, identity oidc.Identity) (*models.User, *models.Identity, error) {
+	linked, err := s.identities.GetByProviderSubject(ctx, providerName, identity.Subject)
+	if err == nil {
+		user, err := s.auth.loadUser(ctx, linked.UserID)
+		return user, linked, err
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, nil, err
+	}
+
+	email := normalizeEmail(identity.Email)
+	if !isValidEmail(email) {
+		return nil, nil, ErrOIDCEmailRequired
+	}
+
+	var (
+		user    *models.User
+		created bool
+	)
+	err = s.auth.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.auth.users.GetByEmail(ctx, email)
+		switch {
+		case err == nil:
+			// Linking on an unverified address on either side would let whoever
+			// registered the address first take over the other account.
+			if !identity.EmailVerified || existing.EmailVerifiedAt == nil {
+				return ErrOIDCAccountConflict
+			}
+			user = existing
+		case errors.Is(err, repository.ErrNotFound):
+			// Provider-only users have no password; they can set one through
+			// a password reset.
+			user = &models.User{Email: email, Role: s.auth.defaultRole}
+			if identity.EmailVerified {
+				verifiedAt := s.now()
+				user.EmailVerifiedAt = &verifiedAt
+			}
+			if err := s.auth.users.Create(ctx, user); err != nil {
+				if errors.Is(err, repository.ErrDuplicate) {
+					return ErrOIDCAccountConflict
+				}
+				return fmt.Errorf("create user: %w", err)
+			}
+			created = true
+		default:
+			return fmt.Errorf("fetch user by email: %w", err)
+		}
+
+		linked = &models.Identity{UserID: user.ID, Provider: providerName, Subject: identity.Subject, Email: email}
+		if err := s.identities.Create(ctx, linked); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				return ErrOIDCAccountConflict
+			}
+			return fmt.Errorf("link identity: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if created {
+		if user.EmailVerifiedAt == nil {
+			if err := s.auth.sendVerificationEmail(ctx, user); err != nil {
+				s.auth.logger.Error("email verification setup failed", "error", err, "user_id", user.ID)
+			}
+		}
+	} else {
+		s.auth.sendAccountNotice(ctx, user.Email, "New sign-in method linked",
+			fmt.Sprintf("Your %s account can now be used to sign in to your blog account. If this was not you, reset your password and contact support.", providerName))
+	}
+	return user, linked, nil
+}
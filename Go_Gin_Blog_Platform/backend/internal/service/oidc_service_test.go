@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/oidc"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/oidc/oidctest"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+type fakeIdentityRepo struct {
+	identities []models.Identity
+	states     map[string]models.OIDCLoginState
+}
+
+func newFakeIdentityRepo() *fakeIdentityRepo {
+	return &fakeIdentityRepo{states: map[string]models.OIDCLoginState{}}
+}
+
+func (f *fakeIdentityRepo) GetByProviderSubject(_ context.Context, provider, subject string) (*models.Identity, error) {
+	for i := range f.identities {
+		if f.identities[i].Provider == provider && f.identities[i].Subject == subject {
+			copy := f.identities[i]
+			return &copy, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeIdentityRepo) Create(ctx context.Context, identity *models.Identity) error {
+	if _, err := f.GetByProviderSubject(ctx, identity.Provider, identity.Subject); err == nil {
+		return repository.ErrDuplicate
+	}
+	identity.ID = "identity-" + identity.Subject
+	f.identities = append(f.identities, *identity)
+	return nil
+}
+
+func (f *fakeIdentityRepo) TouchLogin(_ context.Context, id string, at time.Time) error {
+	for i := range f.identities {
+		if f.identities[i].ID == id {
+			f.identities[i].LastLoginAt = &at
+		}
+	}
+	return nil
+}
+
+func (f *fakeIdentityRepo) SaveLoginState(_ context.Context, state *models.OIDCLoginState) error {
+	f.states[state.StateHash] = *state
+	return nil
+}
+
+func (f *fakeIdentityRepo) ConsumeLoginState(_ context.Context, stateHash string, now time.Time) (*models.OIDCLoginState, error) {
+	state, ok := f.states[stateHash]
+	delete(f.states, stateHash)
+	if !ok || !state.ExpiresAt.After(now) {
+		return nil, repository.ErrNotFound
+	}
+	return &state, nil
+}
+
+func (f *fakeIdentityRepo) DeleteExpiredLoginStates(_ context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	for hash, state := range f.states {
+		if !state.ExpiresAt.After(before) {
+			delete(f.states, hash)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+type oidcFixture struct {
+	authFixture
+	svc        *OIDCService
+	identities *fakeIdentityRepo
+	provider   *oidctest.Server
+}
+
+func newOIDCFixture(t *testing.T) oidcFixture {
+	t.Helper()
+	base := newAuthFixture(t)
+	server := oidctest.NewServer("blog-client", "blog-secret")
+	t.Cleanup(server.Close)
+
+	identities := newFakeIdentityRepo()
+	svc := NewOIDCService(base.svc, identities, map[string]OIDCProvider{
+		"test": oidc.NewProvider(oidc.Config{
+			Name:         "test",
+			Issuer:       server.Issuer(),
+			ClientID:     "blog-client",
+			ClientSecret: "blog-secret",
+			RedirectURL:  "https://blog.example.com/auth/oidc/test/callback",
+		}, server.Client()),
+	})
+	svc.now = base.svc.now
+	return oidcFixture{authFixture: base, svc: svc, identities: identities, provider: server}
+}
+
+// signIn runs a full sign-in at the stand-in provider as user.
+func (f oidcFixture) signIn(t *testing.T, user oidctest.User) (RegisteredUser, error) {
+	t.Helper()
+	f.provider.SetUser(user)
+	start, err := f.svc.StartLogin(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("start login: %v", err)
+	}
+	code, state, err := f.provider.Authorize(start.AuthorizationURL)
+	if err != nil || state != start.State {
+		t.Fatalf("authorize: state %q, %v", state, err)
+	}
+	registered, _, err := f.svc.CompleteLogin(context.Background(), OIDCCallbackInput{Provider: "test", Code: code, State: state})
+	return registered, err
+}
+
+func TestOIDCServiceCreatesAndReusesProviderUsers(t *testing.T) {
+	f := newOIDCFixture(t)
+	bob := oidctest.User{Subject: "sub-bob", Email: "Bob@Example.com", EmailVerified: true}
+
+	first, err := f.signIn(t, bob)
+	if err != nil {
+		t.Fatalf("first sign-in: %v", err)
+	}
+	if first.Email != "bob@example.com" || !first.EmailVerified || first.Role != models.RoleAuthor {
+		t.Fatalf("unexpected new user %+v", first)
+	}
+	if len(f.identities.identities) != 1 || f.identities.identities[0].LastLoginAt == nil {
+		t.Fatalf("expected the provider account to be linked, got %+v", f.identities.identities)
+	}
+
+	// The provider changed the email, but the subject still identifies Bob.
+	bob.Email = "robert@example.com"
+	second, err := f.signIn(t, bob)
+	if err != nil || second.ID != first.ID {
+		t.Fatalf("expected the linked user on the second sign-in, got %+v (%v)", second, err)
+	}
+	if len(f.refresh.tokens) != 2 {
+		t.Fatalf("expected a session per sign-in, got %d", len(f.refresh.tokens))
+	}
+
+	if _, err := f.signIn(t, oidctest.User{Subject: "sub-anon"}); !errors.Is(err, ErrOIDCEmailRequired) {
+		t.Fatalf("expected an account without email to be refused, got %v", err)
+	}
+}
+
+func TestOIDCServiceLinksExistingAccountsByVerifiedEmail(t *testing.T) {
+	f := newOIDCFixture(t)
+	alice := oidctest.User{Subject: "sub-alice", Email: "alice@example.com", EmailVerified: true}
+
+	if _, err := f.signIn(t, alice); !errors.Is(err, ErrOIDCAccountConflict) {
+		t.Fatalf("expected an unverified local account not to be linked, got %v", err)
+	}
+
+	verified := f.clock.Add(-time.Hour)
+	f.users.users[0].EmailVerifiedAt = &verified
+	if _, err := f.signIn(t, oidctest.User{Subject: "sub-alice", Email: "alice@example.com"}); !errors.Is(err, ErrOIDCAccountConflict) {
+		t.Fatalf("expected an unverified provider email not to be linked, got %v", err)
+	}
+
+	linked, err := f.signIn(t, alice)
+	if err != nil || linked.ID != "u1" {
+		t.Fatalf("expected alice's account to be linked, got %+v (%v)", linked, err)
+	}
+	if len(f.users.users) != 1 {
+		t.Fatalf("expected no new user, got %d users", len(f.users.users))
+	}
+	if len(f.emails.sent) != 1 || f.emails.sent[0].To != "alice@example.com" {
+		t.Fatalf("expected a linking notice, got %+v", f.emails.sent)
+	}
+}
+
+func TestOIDCServiceRejectsBadStateAndAsksForSecondFactor(t *testing.T) {
+	f := newOIDCFixture(t)
+	ctx := context.Background()
+
+	start, err := f.svc.StartLogin(ctx, "test")
+	if err != nil {
+		t.Fatalf("start login: %v", err)
+	}
+	if _, err := f.svc.StartLogin(ctx, "unknown"); !errors.Is(err, ErrOIDCProviderNotFound) {
+		t.Fatalf("expected an unknown provider to be refused, got %v", err)
+	}
+	if _, _, err := f.svc.CompleteLogin(ctx, OIDCCallbackInput{Provider: "test", Code: "c", State: "forged"}); !errors.Is(err, ErrInvalidOIDCState) {
+		t.Fatalf("expected an unknown state to be refused, got %v", err)
+	}
+
+	*f.clock = f.clock.Add(oidcStateTTL)
+	if _, _, err := f.svc.CompleteLogin(ctx, OIDCCallbackInput{Provider: "test", Code: "c", State: start.State}); !errors.Is(err, ErrInvalidOIDCState) {
+		t.Fatalf("expected an expired state to be refused, got %v", err)
+	}
+
+	enabled := f.clock.Add(-time.Hour)
+	verified := enabled
+	f.users.users[0].EmailVerifiedAt = &verified
+	f.mfa.credentials["u1"] = &models.TOTPCredential{UserID: "u1", Secret: "JBSWY3DPEHPK3PXP", EnabledAt: &enabled}
+	_, err = f.signIn(t, oidctest.User{Subject: "sub-alice", Email: "alice@example.com", EmailVerified: true})
+	var mfaRequired *MFARequiredError
+	if !errors.As(err, &mfaRequired) || mfaRequired.Token == "" {
+		t.Fatalf("expected a second factor challenge, got %v", err)
+	}
+	if len(f.refresh.tokens) != 0 {
+		t.Fatalf("expected no session before the second factor")
+	}
+	if len(f.identities.identities) != 1 || f.identities.identities[0].LastLoginAt != nil {
+		t.Fatalf("expected a challenged sign-in not to count as a login, got %+v", f.identities.identities)
+	}
+}
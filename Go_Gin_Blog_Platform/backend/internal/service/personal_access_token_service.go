@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/policy"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+// Scopes a personal access token can be granted. posts:write also grants
+// posts:read.
+const (
+	ScopePostsRead  = "posts:read"
+	ScopePostsWrite = "posts:write"
+	ScopeAdminUsers = "admin:users"
+)
+
+// PersonalAccessTokenPrefix starts every personal access token, which is how
+// they are told apart from access tokens.
+const PersonalAccessTokenPrefix = "bpat_"
+
+const (
+	defaultTokenLifetimeDays = 90
+	maxTokenLifetimeDays     = 365
+	maxTokenNameLength       = 100
+	// tokenLastUsedResolution bounds how often a token's last use is written.
+	tokenLastUsedResolution = time.Minute
+)
+
+var ErrPersonalAccessTokenNotFound = errors.New("personal access token not found")
+
+// scopePermissions lists, for each scope, the permissions of which a role
+// needs one to grant it; posts:read needs none.
+var scopePermissions = map[string][]policy.Permission{
+	ScopePostsRead:  nil,
+	ScopePostsWrite: {policy.PostCreate, policy.PostEditAny},
+	ScopeAdminUsers: {policy.UserManage},
+}
+
+type CreatePersonalAccessTokenInput struct {
+	UserID        string
+	Name          string
+	Scopes        []string
+	ExpiresInDays int
+	// MFA is whether the session creating the token was signed in with a
+	// second factor; the token keeps it.
+	MFA bool
+}
+
+type PersonalAccessTokenItem struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedPersonalAccessToken carries the token itself, which is only ever
+// shown once.
+type CreatedPersonalAccessToken struct {
+	PersonalAccessTokenItem
+	Token string `json:"token"`
+}
+
+// TokenPrincipal is who a personal access token acts for. Role and
+// EmailVerified are read from the user on every request, so a demotion takes
+// effect immediately.
+type TokenPrincipal struct {
+	TokenID       string
+	UserID        string
+	Role          models.Role
+	EmailVerified bool
+	MFA           bool
+	Scopes        []string
+}
+
+type PersonalAccessTokenService struct {
+	users  repository.UserRepository
+	tokens repository.PersonalAccessTokenRepository
+	audit  *AuditService
+	tx     repository.Transactor
+	policy *policy.Policy
+	now    func() time.Time
+}
+
+func NewPersonalAccessTokenService(
+	users repository.UserRepository,
+	tokens repository.PersonalAccessTokenRepository,
+	audit *AuditService,
+	tx repository.Transactor,
+	authz *policy.Policy,
+) *PersonalAccessTokenService {
+	return &PersonalAccessTokenService{
+		users:  users,
+		tokens: tokens,
+		audit:  audit,
+		tx:     tx,
+		policy: authz,
+		now:    func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (s *PersonalAccessTokenService) Create(ctx context.Context, input CreatePersonalAccessTokenInput) (CreatedPersonalAccessToken, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || len(name) > maxTokenNameLength {
+		return CreatedPersonalAccessToken{}, fmt.Errorf("name must be between 1 and %d characters: %w", maxTokenNameLength, ErrValidation)
+	}
+	scopes, err := normalizeScopes(input.Scopes)
+	if err != nil {
+		return CreatedPersonalAccessToken{}, err
+	}
+	days := input.ExpiresInDays
+	if days == 0 {
+		days = defaultTokenLifetimeDays
+	}
+	if days < 1 || days > maxTokenLifetimeDays {
+		return CreatedPersonalAccessToken{}, fmt.Errorf("expires_in_days must be between 1 and %d: %w", maxTokenLifetimeDays, ErrValidation)
+	}
+
+	user, err := s.loadUser(ctx, input.UserID)
+	if err != nil {
+		return CreatedPersonalAccessToken{}, err
+	}
+	for _, scope := range scopes {
+		if !s.canGrant(user, scope) {
+			return CreatedPersonalAccessToken{}, fmt.Errorf("role %s cannot grant %s: %w", user.Role, scope, ErrForbidden)
+		}
+	}
+
+	secret, err := auth.GenerateRandomToken(32)
+	if err != nil {
+		return CreatedPersonalAccessToken{}, err
+	}
+	raw := PersonalAccessTokenPrefix + secret
+	token := &models.PersonalAccessToken{
+		UserID:    user.ID,
+		Name:      name,
+		TokenHash: auth.HashToken(raw),
+		Prefix:    raw[:len(PersonalAccessTokenPrefix)+8],
+		Scopes:    strings.Join(scopes, " "),
+		MFA:       input.MFA,
+		ExpiresAt: s.now().AddDate(0, 0, days),
+		CreatedAt: s.now(),
+	}
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.tokens.Create(ctx, token); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditRecord{
+			ActorID:    user.ID,
+			Action:     AuditTokenCreate,
+			TargetType: AuditTargetToken,
+			TargetID:   token.ID,
+			After:      map[string]any{"name": token.Name, "scopes": scopes, "expires_at": token.ExpiresAt},
+		})
+	})
+	if err != nil {
+		return CreatedPersonalAccessToken{}, fmt.Errorf("create personal access token: %w", err)
+	}
+
+	return CreatedPersonalAccessToken{PersonalAccessTokenItem: personalAccessTokenItem(token), Token: raw}, nil
+}
+
+func (s *PersonalAccessTokenService) List(ctx context.Context, userID string) ([]PersonalAccessTokenItem, error) {
+	if _, err := s.loadUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.tokens.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list personal access tokens: %w", err)
+	}
+	items := make([]PersonalAccessTokenItem, 0, len(tokens))
+	for i := range tokens {
+		items = append(items, personalAccessTokenItem(&tokens[i]))
+	}
+	return items, nil
+}
+
+func (s *PersonalAccessTokenService) Revoke(ctx context.Context, userID, tokenID string) error {
+	if strings.TrimSpace(tokenID) == "" {
+		return fmt.Errorf("token id is required: %w", ErrValidation)
+	}
+	if _, err := s.loadUser(ctx, userID); err != nil {
+		return err
+	}
+
+	tokens, err := s.tokens.ListByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("list personal access tokens: %w", err)
+	}
+	for _, token := range tokens {
+		if token.ID != tokenID {
+			continue
+		}
+		err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.tokens.Revoke(ctx, token.ID, s.now()); err != nil {
+				return err
+			}
+			return s.audit.Record(ctx, AuditRecord{
+				ActorID:    userID,
+				Action:     AuditTokenRevoke,
+				TargetType: AuditTargetToken,
+				TargetID:   token.ID,
+				Before:     map[string]any{"name": token.Name},
+			})
+		})
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrPersonalAccessTokenNotFound
+			}
+			return fmt.Errorf("revoke personal access token: %w", err)
+		}
+		return nil
+	}
+	return ErrPersonalAccessTokenNotFound
+}
+
+// Authenticate resolves a raw personal access token to the user it acts for,
+// returning ErrInvalidToken for unknown, revoked and expired tokens.
+func (s *PersonalAccessTokenService) Authenticate(ctx context.Context, raw string) (TokenPrincipal, error) {
+	if !strings.HasPrefix(raw, PersonalAccessTokenPrefix) {
+		return TokenPrincipal{}, ErrInvalidToken
+	}
+
+	now := s.now()
+	token, err := s.tokens.GetActiveByHash(ctx, auth.HashToken(raw), now)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return TokenPrincipal{}, ErrInvalidToken
+		}
+		return TokenPrincipal{}, err
+	}
+	user, err := s.users.GetByID(ctx, token.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return TokenPrincipal{}, ErrInvalidToken
+		}
+		return TokenPrincipal{}, fmt.Errorf("fetch user: %w", err)
+	}
+	if err := s.tokens.TouchLastUsed(ctx, token.ID, now, now.Add(-tokenLastUsedResolution)); err != nil {
+		return TokenPrincipal{}, err
+	}
+
+	return TokenPrincipal{
+		TokenID:       token.ID,
+		UserID:        user.ID,
+		Role:          user.Role,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		MFA:           token.MFA,
+		Scopes:        splitScopes(token.Scopes),
+	}, nil
+}
+
+// ScopeGranted reports whether a token with the granted scopes may act under
+// the required one.
+func ScopeGranted(granted []string, required string) bool {
+	for _, scope := range granted {
+		if scope == required || (scope == ScopePostsWrite && required == ScopePostsRead) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *PersonalAccessTokenService) loadUser(ctx context.Context, userID string) (*models.User, error) {
+	if strings.TrimSpace(userID) == "" {
+		return nil, fmt.Errorf("user id is required: %w", ErrValidation)
+	}
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("fetch user: %w", err)
+	}
+	return user, nil
+}
+
+func normalizeScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if _, ok := scopePermissions[scope]; !ok {
+			return nil, fmt.Errorf("unknown scope %q: %w", scope, ErrValidation)
+		}
+		if _, dup := seen[scope]; dup {
+			continue
+		}
+		seen[scope] = struct{}{}
+		normalized = append(normalized, scope)
+	}
+	if len(normalized) == 0 {
+		return nil, fmt.Errorf("at least one scope is required: %w", ErrValidation)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+func splitScopes(scopes string) []string {
+	return strings.Fields(scopes)
+}
+
+func (s *PersonalAccessTokenService) canGrant(user *models.User, scope string) bool {
+	permissions := scopePermissions[scope]
+	if len(permissions) == 0 {
+		return true
+	}
+	actor := policy.Actor{ID: user.ID, Role: string(user.Role)}
+	for _, permission := range permissions {
+		if s.policy.Authorize(actor, permission, nil) {
+			return true
+		}
+	}
+	return false
+}
+
+func personalAccessTokenItem(token *models.PersonalAccessToken) PersonalAccessTokenItem {
+	return PersonalAccessTokenItem{
+		ID:         token.ID,
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     splitScopes(token.Scopes),
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
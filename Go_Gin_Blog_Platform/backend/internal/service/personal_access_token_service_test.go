@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+type fakePersonalAccessTokenRepo struct {
+	tokens  []models.PersonalAccessToken
+	touches int
+}
+
+func (f *fakePersonalAccessTokenRepo) Create(_ context.Context, token *models.PersonalAccessToken) error {
+	token.ID = "pat-" + token.Name
+	f.tokens = append(f.tokens, *token)
+	return nil
+}
+
+func (f *fakePersonalAccessTokenRepo) ListByUser(_ context.Context, userID string) ([]models.PersonalAccessToken, error) {
+	var tokens []models.PersonalAccessToken
+	for _, token := range f.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (f *fakePersonalAccessTokenRepo) GetActiveByHash(_ context.Context, tokenHash string, now time.Time) (*models.PersonalAccessToken, error) {
+	for i := range f.tokens {
+		token := f.tokens[i]
+		if token.TokenHash == tokenHash && token.RevokedAt == nil && token.ExpiresAt.After(now) {
+			return &token, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakePersonalAccessTokenRepo) Revoke(_ context.Context, id string, at time.Time) error {
+	for i := range f.tokens {
+		if f.tokens[i].ID == id && f.tokens[i].RevokedAt == nil {
+			f.tokens[i].RevokedAt = &at
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (f *fakePersonalAccessTokenRepo) RevokeAllForUser(_ context.Context, userID string, at time.Time) (int64, error) {
+	var revoked int64
+	for i := range f.tokens {
+		if f.tokens[i].UserID == userID && f.tokens[i].RevokedAt == nil {
+			f.tokens[i].RevokedAt = &at
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (f *fakePersonalAccessTokenRepo) TouchLastUsed(_ context.Context, id string, at, staleBefore time.Time) error {
+	for i := range f.tokens {
+		token := &f.tokens[i]
+		if token.ID == id && (token.LastUsedAt == nil || token.LastUsedAt.Before(staleBefore)) {
+			token.LastUsedAt = &at
+			f.touches++
+		}
+	}
+	return nil
+}
+
+func newPersonalAccessTokenFixture() (*PersonalAccessTokenService, *fakePersonalAccessTokenRepo, *time.Time) {
+	users := &fakeUserRepo{users: []models.User{
+		{ID: "u1", Email: "alice@example.com", Role: models.RoleAuthor},
+		{ID: "u2", Email: "root@example.com", Role: models.RoleAdmin},
+	}}
+	tokens := &fakePersonalAccessTokenRepo{}
+	svc := NewPersonalAccessTokenService(users, tokens, newTestAudit(), fakeTransactor{}, defaultPolicy())
+	clock := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return clock }
+	return svc, tokens, &clock
+}
+
+func TestPersonalAccessTokenServiceCreatesAndAuthenticates(t *testing.T) {
+	svc, repo, clock := newPersonalAccessTokenFixture()
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, CreatePersonalAccessTokenInput{UserID: "u1", Name: "ci", Scopes: []string{"posts:write", " POSTS:READ ", "posts:write"}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if !strings.HasPrefix(created.Token, PersonalAccessTokenPrefix) || !strings.HasPrefix(created.Token, created.Prefix) {
+		t.Fatalf("unexpected token %q with prefix %q", created.Token, created.Prefix)
+	}
+	if strings.Join(created.Scopes, " ") != "posts:read posts:write" || !created.ExpiresAt.Equal(clock.AddDate(0, 0, 90)) {
+		t.Fatalf("unexpected token item %+v", created.PersonalAccessTokenItem)
+	}
+	if repo.tokens[0].TokenHash == created.Token || strings.Contains(repo.tokens[0].TokenHash, created.Token) {
+		t.Fatalf("expected only a hash of the token to be stored")
+	}
+
+	principal, err := svc.Authenticate(ctx, created.Token)
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if principal.UserID != "u1" || principal.Role != models.RoleAuthor || principal.TokenID != created.ID {
+		t.Fatalf("unexpected principal %+v", principal)
+	}
+
+	*clock = clock.Add(30 * time.Second)
+	if _, err := svc.Authenticate(ctx, created.Token); err != nil {
+		t.Fatalf("second authenticate: %v", err)
+	}
+	if repo.touches != 1 {
+		t.Fatalf("expected uses within a minute to be recorded once, got %d writes", repo.touches)
+	}
+
+	if _, err := svc.Authenticate(ctx, created.Token+"x"); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected an unknown token to be refused, got %v", err)
+	}
+	*clock = clock.AddDate(0, 0, 90)
+	if _, err := svc.Authenticate(ctx, created.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected an expired token to be refused, got %v", err)
+	}
+}
+
+func TestPersonalAccessTokenServiceValidatesScopesAndLifetime(t *testing.T) {
+	svc, _, _ := newPersonalAccessTokenFixture()
+	ctx := context.Background()
+
+	cases := []struct {
+		input CreatePersonalAccessTokenInput
+		want  error
+	}{
+		{CreatePersonalAccessTokenInput{UserID: "u1", Name: "ci"}, ErrValidation},
+		{CreatePersonalAccessTokenInput{UserID: "u1", Name: "ci", Scopes: []string{"posts:delete"}}, ErrValidation},
+		{CreatePersonalAccessTokenInput{UserID: "u1", Name: " ", Scopes: []string{"posts:read"}}, ErrValidation},
+		{CreatePersonalAccessTokenInput{UserID: "u1", Name: "ci", Scopes: []string{"posts:read"}, ExpiresInDays: 366}, ErrValidation},
+		{CreatePersonalAccessTokenInput{UserID: "u1", Name: "ci", Scopes: []string{"admin:users"}}, ErrForbidden},
+		{CreatePersonalAccessTokenInput{UserID: "missing", Name: "ci", Scopes: []string{"posts:read"}}, ErrUserNotFound},
+	}
+	for _, tc := range cases {
+		if _, err := svc.Create(ctx, tc.input); !errors.Is(err, tc.want) {
+			t.Fatalf("%+v: expected %v, got %v", tc.input, tc.want, err)
+		}
+	}
+
+	if _, err := svc.Create(ctx, CreatePersonalAccessTokenInput{UserID: "u2", Name: "ops", Scopes: []string{"admin:users"}, ExpiresInDays: 7}); err != nil {
+		t.Fatalf("expected an admin to grant admin:users: %v", err)
+	}
+}
+
+func TestPersonalAccessTokenServiceListsAndRevokes(t *testing.T) {
+	svc, _, _ := newPersonalAccessTokenFixture()
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, CreatePersonalAccessTokenInput{UserID: "u1", Name: "ci", Scopes: []string{"posts:read"}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := svc.Create(ctx, CreatePersonalAccessTokenInput{UserID: "u2", Name: "ops", Scopes: []string{"posts:read"}}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	items, err := svc.List(ctx, "u1")
+	if err != nil || len(items) != 1 || items[0].ID != created.ID {
+		t.Fatalf("expected only alice's token, got %+v (%v)", items, err)
+	}
+
+	if err := svc.Revoke(ctx, "u2", created.ID); !errors.Is(err, ErrPersonalAccessTokenNotFound) {
+		t.Fatalf("expected another user's token to be invisible, got %v", err)
+	}
+	if err := svc.Revoke(ctx, "u1", created.ID); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, created.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected a revoked token to be refused, got %v", err)
+	}
+	if items, _ := svc.List(ctx, "u1"); len(items) != 0 {
+		t.Fatalf("expected no tokens after revoking, got %+v", items)
+	}
+}
+
+func TestScopeGranted(t *testing.T) {
+	if !ScopeGranted([]string{ScopePostsWrite}, ScopePostsRead) {
+		t.Fatalf("expected posts:write to grant posts:read")
+	}
+	if ScopeGranted([]string{ScopePostsRead}, ScopePostsWrite) || ScopeGranted([]string{ScopePostsWrite}, ScopeAdminUsers) {
+		t.Fatalf("expected scopes not to grant more than they name")
+	}
+}
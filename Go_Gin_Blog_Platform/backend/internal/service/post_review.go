@@ -0,0 +1,407 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/policy"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+// Review actions move a post through the editorial workflow: an author
+// submits a draft, a reviewer approves it or requests changes, and the author
+// publishes it once approved.
+const (
+	ReviewSubmit         = "submit"
+	ReviewAssign         = "assign"
+	ReviewApprove        = "approve"
+	ReviewRequestChanges = "request_changes"
+	ReviewWithdraw       = "withdraw"
+	ReviewPublish        = "publish"
+)
+
+// Actions recorded for status changes made through a plain update.
+const (
+	reviewSchedule  = "schedule"
+	reviewUnpublish = "unpublish"
+)
+
+const (
+	ReviewQueueAssignedToMe = "me"
+	ReviewQueueUnassigned   = "none"
+)
+
+type ReviewPostInput struct {
+	PostID     string
+	ActorID    string
+	ActorRole  string
+	Action     string
+	Comment    string
+	ReviewerID string
+}
+
+type ReviewQueueInput struct {
+	ActorID   string
+	ActorRole string
+	Assigned  string
+	Page      int
+	Limit     int
+}
+
+type ListPostReviewsInput struct {
+	PostID    string
+	ActorID   string
+	ActorRole string
+}
+
+type PostReviewEventItem struct {
+	ID         string            `json:"id"`
+	ActorID    *string           `json:"actor_id"`
+	Action     string            `json:"action"`
+	FromStatus models.PostStatus `json:"from_status"`
+	ToStatus   models.PostStatus `json:"to_status"`
+	ReviewerID *string           `json:"reviewer_id,omitempty"`
+	Comment    string            `json:"comment,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// Review applies one review action to a post and records it, with the
+// comment, in the post's review history.
+func (s *PostService) Review(ctx context.Context, input ReviewPostInput) (PostItem, error) {
+	post, err := s.repo.GetByID(ctx, strings.TrimSpace(input.PostID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return PostItem{}, ErrPostNotFound
+		}
+		return PostItem{}, fmt.Errorf("get existing post: %w", err)
+	}
+
+	actor := policy.Actor{ID: input.ActorID, Role: input.ActorRole}
+	action := strings.ToLower(strings.TrimSpace(input.Action))
+	comment := strings.TrimSpace(input.Comment)
+	reviewerID := strings.TrimSpace(input.ReviewerID)
+
+	var target models.PostStatus
+	switch action {
+	case ReviewSubmit:
+		target = models.PostStatusInReview
+	case ReviewWithdraw, ReviewRequestChanges:
+		target = models.PostStatusDraft
+	case ReviewApprove:
+		target = models.PostStatusApproved
+	case ReviewPublish:
+		target = models.PostStatusPublished
+	case ReviewAssign:
+		target = post.Status
+	default:
+		return PostItem{}, fmt.Errorf("action must be submit, assign, approve, request_changes, withdraw or publish: %w", ErrValidation)
+	}
+
+	switch action {
+	case ReviewSubmit, ReviewWithdraw, ReviewPublish:
+		if !s.policy.Authorize(actor, policy.PostEditAny, &policy.Resource{OwnerID: post.AuthorID}) {
+			return PostItem{}, ErrForbidden
+		}
+	default:
+		if !s.policy.Authorize(actor, policy.PostReview, nil) {
+			return PostItem{}, ErrForbidden
+		}
+	}
+
+	switch action {
+	case ReviewSubmit:
+		if post.Status != models.PostStatusDraft {
+			return PostItem{}, invalidTransition(post.Status, target)
+		}
+	case ReviewWithdraw:
+		if !underReview(post.Status) {
+			return PostItem{}, fmt.Errorf("only posts under review can be withdrawn: %w", ErrValidation)
+		}
+	case ReviewAssign, ReviewApprove, ReviewRequestChanges:
+		if post.Status != models.PostStatusInReview {
+			return PostItem{}, fmt.Errorf("post is %s, not in review: %w", post.Status, ErrValidation)
+		}
+	}
+	if action == ReviewRequestChanges && comment == "" {
+		return PostItem{}, fmt.Errorf("a comment is required when requesting changes: %w", ErrValidation)
+	}
+	if action == ReviewAssign && reviewerID == "" {
+		return PostItem{}, fmt.Errorf("reviewer_id is required: %w", ErrValidation)
+	}
+	if reviewerID != "" && action != ReviewSubmit && action != ReviewAssign {
+		return PostItem{}, fmt.Errorf("reviewer_id is only allowed when submitting or assigning: %w", ErrValidation)
+	}
+	// Every action but assign moves the post; one already where the action
+	// would take it has nothing to record.
+	if action != ReviewAssign {
+		if target == post.Status {
+			return PostItem{}, invalidTransition(post.Status, target)
+		}
+		if err := s.checkTransition(actor, post.AuthorID, post.Status, target); err != nil {
+			return PostItem{}, err
+		}
+	}
+
+	updates := map[string]any{}
+	if target != post.Status {
+		updates["status"] = target
+		updates["publish_at"] = nil
+		trackSubmission(updates, target)
+	}
+	event := &models.PostReviewEvent{
+		PostID:     post.ID,
+		ActorID:    &input.ActorID,
+		Action:     action,
+		FromStatus: post.Status,
+		ToStatus:   target,
+		ReviewerID: post.ReviewerID,
+		Comment:    comment,
+	}
+	if reviewerID != "" {
+		if err := s.checkReviewer(ctx, post, reviewerID); err != nil {
+			return PostItem{}, err
+		}
+		updates["reviewer_id"] = reviewerID
+		event.ReviewerID = &reviewerID
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.lockPost(ctx, post); err != nil {
+			return err
+		}
+		if err := s.repo.Update(ctx, post.ID, updates); err != nil {
+			return err
+		}
+		if err := s.reviews.Create(ctx, event); err != nil {
+			return err
+		}
+		return s.auditReview(ctx, post, event)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return PostItem{}, ErrPostNotFound
+		}
+		return PostItem{}, fmt.Errorf("review post: %w", err)
+	}
+
+	reviewed, err := s.repo.GetByID(ctx, post.ID)
+	if err != nil {
+		return PostItem{}, fmt.Errorf("reload reviewed post: %w", err)
+	}
+	return toPostItem(*reviewed), nil
+}
+
+// ReviewQueue lists posts waiting for review, longest waiting first.
+func (s *PostService) ReviewQueue(ctx context.Context, input ReviewQueueInput) ([]PostItem, Pagination, error) {
+	if !s.policy.Authorize(policy.Actor{ID: input.ActorID, Role: input.ActorRole}, policy.PostReview, nil) {
+		return nil, Pagination{}, ErrForbidden
+	}
+
+	filter := repository.PostListFilter{
+		Statuses:    []models.PostStatus{models.PostStatusInReview},
+		ReviewOrder: true,
+	}
+	switch strings.ToLower(strings.TrimSpace(input.Assigned)) {
+	case "":
+	case ReviewQueueAssignedToMe:
+		filter.ReviewerID = input.ActorID
+	case ReviewQueueUnassigned:
+		filter.Unassigned = true
+	default:
+		return nil, Pagination{}, fmt.Errorf("assigned must be me or none: %w", ErrValidation)
+	}
+
+	return s.list(ctx, filter, input.Page, input.Limit, ContentFormatMarkdown)
+}
+
+// ListReviewEvents returns a post's review history to its author, to
+// reviewers and to anyone who may edit it.
+func (s *PostService) ListReviewEvents(ctx context.Context, input ListPostReviewsInput) ([]PostReviewEventItem, error) {
+	post, err := s.repo.GetByID(ctx, strings.TrimSpace(input.PostID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrPostNotFound
+		}
+		return nil, fmt.Errorf("get existing post: %w", err)
+	}
+
+	actor := policy.Actor{ID: input.ActorID, Role: input.ActorRole}
+	if !s.policy.Authorize(actor, policy.PostEditAny, &policy.Resource{OwnerID: post.AuthorID}) &&
+		!s.policy.Authorize(actor, policy.PostReview, nil) {
+		return nil, ErrForbidden
+	}
+
+	events, err := s.reviews.ListByPost(ctx, post.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list post review events: %w", err)
+	}
+
+	items := make([]PostReviewEventItem, 0, len(events))
+	for _, event := range events {
+		items = append(items, PostReviewEventItem{
+			ID:         event.ID,
+			ActorID:    event.ActorID,
+			Action:     event.Action,
+			FromStatus: event.FromStatus,
+			ToStatus:   event.ToStatus,
+			ReviewerID: event.ReviewerID,
+			Comment:    event.Comment,
+			CreatedAt:  event.CreatedAt,
+		})
+	}
+	return items, nil
+}
+
+// auditReview records the review actions that decide who signs a post off and
+// what goes live: assigning a reviewer and publishing.
+func (s *PostService) auditReview(ctx context.Context, post *models.Post, event *models.PostReviewEvent) error {
+	record := AuditRecord{TargetType: AuditTargetPost, TargetID: post.ID}
+	if event.ActorID != nil {
+		record.ActorID = *event.ActorID
+	}
+	switch event.Action {
+	case ReviewAssign:
+		record.Action = AuditPostAssign
+		record.Before = map[string]any{"reviewer_id": post.ReviewerID}
+		record.After = map[string]any{"reviewer_id": event.ReviewerID}
+	case ReviewPublish:
+		record.Action = AuditPostPublish
+		record.Before = auditPostState(post)
+		after := auditPostState(post)
+		after["status"] = event.ToStatus
+		record.After = after
+	default:
+		return nil
+	}
+	return s.audit.Record(ctx, record)
+}
+
+// lockPost locks post's row for the rest of the transaction and returns it as
+// stored, after making sure its status is still the one the caller's checks
+// were made against, so two concurrent transitions cannot both apply.
+func (s *PostService) lockPost(ctx context.Context, post *models.Post) (*models.Post, error) {
+	current, err := s.repo.GetByIDForUpdate(ctx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status != post.Status {
+		return nil, fmt.Errorf("post moved from %s to %s meanwhile, reload it and retry: %w", post.Status, current.Status, ErrConflict)
+	}
+	return current, nil
+}
+
+// checkReviewer makes sure a post can be assigned to the user: they must be
+// able to review posts and must not be its author.
+func (s *PostService) checkReviewer(ctx context.Context, post *models.Post, reviewerID string) error {
+	reviewer, err := s.users.GetByID(ctx, reviewerID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("reviewer %q does not exist: %w", reviewerID, ErrValidation)
+		}
+		return fmt.Errorf("get reviewer: %w", err)
+	}
+	if reviewer.ID == post.AuthorID {
+		return fmt.Errorf("authors cannot review their own posts: %w", ErrValidation)
+	}
+	if !s.policy.Authorize(policy.Actor{ID: reviewer.ID, Role: string(reviewer.Role)}, policy.PostReview, nil) {
+		return fmt.Errorf("reviewer %q cannot review posts: %w", reviewerID, ErrValidation)
+	}
+	return nil
+}
+
+// checkTransition enforces the review workflow on a status change. Any post
+// can go back to a draft and any draft can be submitted; only a reviewer
+// other than the author can approve, and a post goes live either because the
+// actor may publish or because it is the author's approved post. Actors
+// without post.publish have to take their drafts through review.
+func (s *PostService) checkTransition(actor policy.Actor, authorID string, from, to models.PostStatus) error {
+	switch {
+	case to == models.PostStatusDraft:
+		return nil
+	case from == models.PostStatusDraft && to == models.PostStatusInReview:
+		return nil
+	case from == models.PostStatusInReview && to == models.PostStatusApproved:
+		if actor.ID == authorID || !s.policy.Authorize(actor, policy.PostReview, nil) {
+			return ErrForbidden
+		}
+		return nil
+	case isLive(to) && (from == models.PostStatusApproved || from == models.PostStatusDraft || isLive(from)):
+		if from == models.PostStatusApproved && actor.ID == authorID {
+			return nil
+		}
+		if !s.policy.Authorize(actor, policy.PostPublish, nil) {
+			if from == models.PostStatusDraft {
+				return fmt.Errorf("a draft must be submitted and approved before it goes live: %w", ErrForbidden)
+			}
+			return ErrForbidden
+		}
+		return nil
+	}
+	return invalidTransition(from, to)
+}
+
+// checkChange checks an edit that leaves post in status to. While a post is
+// under review its text is what the reviewer signs off on, so only reviewers
+// may edit it unless it goes back to a draft.
+func (s *PostService) checkChange(actor policy.Actor, post *models.Post, to models.PostStatus, edits bool) error {
+	if edits && underReview(post.Status) && to != models.PostStatusDraft && !s.policy.Authorize(actor, policy.PostReview, nil) {
+		return fmt.Errorf("post is under review, move it back to draft before editing: %w", ErrValidation)
+	}
+	if to != post.Status {
+		return s.checkTransition(actor, post.AuthorID, post.Status, to)
+	}
+	if !s.mayLeaveIn(actor, to) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// transitionAction names a status change made through a plain update for the
+// review history.
+func transitionAction(from, to models.PostStatus, byAuthor bool) string {
+	switch to {
+	case models.PostStatusInReview:
+		return ReviewSubmit
+	case models.PostStatusApproved:
+		return ReviewApprove
+	case models.PostStatusScheduled:
+		return reviewSchedule
+	case models.PostStatusPublished:
+		return ReviewPublish
+	}
+	switch {
+	case isLive(from):
+		return reviewUnpublish
+	case from == models.PostStatusInReview && !byAuthor:
+		return ReviewRequestChanges
+	default:
+		return ReviewWithdraw
+	}
+}
+
+func invalidTransition(from, to models.PostStatus) error {
+	return fmt.Errorf("a post cannot move from %s to %s: %w", from, to, ErrValidation)
+}
+
+// trackSubmission stamps submitted_at when a post enters review and clears it
+// when the post goes back to a draft.
+func trackSubmission(updates map[string]any, to models.PostStatus) {
+	switch to {
+	case models.PostStatusInReview:
+		updates["submitted_at"] = time.Now().UTC()
+	case models.PostStatusDraft:
+		updates["submitted_at"] = nil
+	}
+}
+
+func isLive(status models.PostStatus) bool {
+	return status == models.PostStatusPublished || status == models.PostStatusScheduled
+}
+
+func underReview(status models.PostStatus) bool {
+	return status == models.PostStatusInReview || status == models.PostStatusApproved
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+)
+
+type fakeReviewRepo struct {
+	events []models.PostReviewEvent
+}
+
+func (f *fakeReviewRepo) Create(_ context.Context, event *models.PostReviewEvent) error {
+	f.events = append(f.events, *event)
+	return nil
+}
+
+func (f *fakeReviewRepo) ListByPost(_ context.Context, postID string) ([]models.PostReviewEvent, error) {
+	var events []models.PostReviewEvent
+	for _, event := range f.events {
+		if event.PostID == postID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func newReviewFixture(status models.PostStatus) (*PostService, *fakePostRepo, *fakeReviewRepo) {
+	repo := &fakePostRepo{post: models.Post{ID: "p1", AuthorID: "writer", Title: "Draft", Slug: "draft", Content: "Text", Status: status}}
+	reviews := &fakeReviewRepo{}
+	users := &fakeUserRepo{users: []models.User{
+		{ID: "writer", Role: models.RoleContributor},
+		{ID: "ed", Role: models.RoleEditor},
+		{ID: "reader", Role: models.RoleReader},
+	}}
+	svc := newTestPostService(repo, testPostDeps{reviews: reviews, users: users})
+	return svc, repo, reviews
+}
+
+func TestPostReviewAuditsAssignAndPublish(t *testing.T) {
+	svc, _, _ := newReviewFixture(models.PostStatusDraft)
+	events := &fakeAuditRepo{}
+	svc.audit = NewAuditService(events, defaultPolicy())
+	ctx := context.Background()
+
+	steps := []ReviewPostInput{
+		{PostID: "p1", ActorID: "writer", ActorRole: "contributor", Action: ReviewSubmit},
+		{PostID: "p1", ActorID: "ed", ActorRole: "editor", Action: ReviewAssign, ReviewerID: "ed"},
+		{PostID: "p1", ActorID: "ed", ActorRole: "editor", Action: ReviewApprove},
+		{PostID: "p1", ActorID: "writer", ActorRole: "contributor", Action: ReviewPublish},
+	}
+	for _, step := range steps {
+		if _, err := svc.Review(ctx, step); err != nil {
+			t.Fatalf("%s: %v", step.Action, err)
+		}
+	}
+
+	if len(events.events) != 2 {
+		t.Fatalf("expected assign and publish to be audited, got %+v", events.events)
+	}
+	assign, publish := events.events[0], events.events[1]
+	if assign.Action != AuditPostAssign || *assign.ActorID != "ed" || assign.TargetID != "p1" || string(assign.After) != `{"reviewer_id":"ed"}` {
+		t.Fatalf("unexpected assign event %+v (%s)", assign, assign.After)
+	}
+	if publish.Action != AuditPostPublish || *publish.ActorID != "writer" || !strings.Contains(string(publish.Before), `"approved"`) || !strings.Contains(string(publish.After), `"published"`) {
+		t.Fatalf("unexpected publish event %+v (%s -> %s)", publish, publish.Before, publish.After)
+	}
+}
+
+func TestPostReviewWorkflow(t *testing.T) {
+	svc, repo, reviews := newReviewFixture(models.PostStatusDraft)
+	ctx := context.Background()
+	writer := ReviewPostInput{PostID: "p1", ActorID: "writer", ActorRole: "contributor"}
+	editor := ReviewPostInput{PostID: "p1", ActorID: "ed", ActorRole: "editor"}
+
+	published := string(models.PostStatusPublished)
+	if _, err := svc.Update(ctx, UpdatePostInput{PostID: "p1", ActorID: "writer", ActorRole: "contributor", Status: &published}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected a contributor not to publish a draft, got %v", err)
+	}
+
+	submit := writer
+	submit.Action, submit.ReviewerID = ReviewSubmit, "ed"
+	item, err := svc.Review(ctx, submit)
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if item.Status != models.PostStatusInReview || item.SubmittedAt == nil || item.ReviewerID == nil || *item.ReviewerID != "ed" {
+		t.Fatalf("expected the post in review with ed assigned, got %+v", item)
+	}
+
+	content := "Sneaky edit"
+	if _, err := svc.Update(ctx, UpdatePostInput{PostID: "p1", ActorID: "writer", ActorRole: "contributor", Content: &content}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected edits under review to be refused, got %v", err)
+	}
+	if _, err := svc.Review(ctx, ReviewPostInput{PostID: "p1", ActorID: "writer", ActorRole: "contributor", Action: ReviewApprove}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected a contributor not to approve, got %v", err)
+	}
+
+	changes := editor
+	changes.Action = ReviewRequestChanges
+	if _, err := svc.Review(ctx, changes); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected a comment to be required, got %v", err)
+	}
+	changes.Comment = "Needs a conclusion."
+	if item, err = svc.Review(ctx, changes); err != nil || item.Status != models.PostStatusDraft || item.SubmittedAt != nil {
+		t.Fatalf("expected the post back in draft, got %+v (%v)", item, err)
+	}
+
+	submit.ReviewerID = ""
+	if _, err := svc.Review(ctx, submit); err != nil {
+		t.Fatalf("resubmit: %v", err)
+	}
+	approve := editor
+	approve.Action = ReviewApprove
+	if _, err := svc.Review(ctx, approve); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if _, err := svc.Update(ctx, UpdatePostInput{PostID: "p1", ActorID: "writer", ActorRole: "contributor", Status: &published}); err != nil {
+		t.Fatalf("expected the author to publish an approved post: %v", err)
+	}
+	if repo.post.Status != models.PostStatusPublished {
+		t.Fatalf("expected the post to be published, got %s", repo.post.Status)
+	}
+	republish := writer
+	republish.Action = ReviewPublish
+	if _, err := svc.Review(ctx, republish); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected publishing a published post to be refused, got %v", err)
+	}
+
+	history, err := svc.ListReviewEvents(ctx, ListPostReviewsInput{PostID: "p1", ActorID: "writer", ActorRole: "contributor"})
+	if err != nil {
+		t.Fatalf("list review events: %v", err)
+	}
+	var actions []string
+	for _, event := range history {
+		actions = append(actions, event.Action)
+	}
+	want := []string{ReviewSubmit, ReviewRequestChanges, ReviewSubmit, ReviewApprove, ReviewPublish}
+	if len(actions) != len(want) {
+		t.Fatalf("expected actions %v, got %v", want, actions)
+	}
+	for i := range want {
+		if actions[i] != want[i] {
+			t.Fatalf("expected actions %v, got %v", want, actions)
+		}
+	}
+	if reviews.events[1].Comment != "Needs a conclusion." {
+		t.Fatalf("expected the reviewer comment on the transition, got %+v", reviews.events[1])
+	}
+}
+
+func TestPostReviewRejectsInvalidTransitions(t *testing.T) {
+	ctx := context.Background()
+
+	svc, _, _ := newReviewFixture(models.PostStatusDraft)
+	approved := string(models.PostStatusApproved)
+	if _, err := svc.Update(ctx, UpdatePostInput{PostID: "p1", ActorID: "ed", ActorRole: "editor", Status: &approved}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected a draft not to be approved directly, got %v", err)
+	}
+	if _, err := svc.Review(ctx, ReviewPostInput{PostID: "p1", ActorID: "ed", ActorRole: "editor", Action: ReviewApprove}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected approving a draft to be refused, got %v", err)
+	}
+	if _, err := svc.Review(ctx, ReviewPostInput{PostID: "p1", ActorID: "writer", ActorRole: "contributor", Action: "merge"}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected an unknown action to be refused, got %v", err)
+	}
+	for _, reviewer := range []string{"reader", "writer", "ghost"} {
+		_, err := svc.Review(ctx, ReviewPostInput{PostID: "p1", ActorID: "writer", ActorRole: "contributor", Action: ReviewSubmit, ReviewerID: reviewer})
+		if !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected %s not to be assignable, got %v", reviewer, err)
+		}
+	}
+
+	published := string(models.PostStatusPublished)
+	if _, err := svc.Update(ctx, UpdatePostInput{PostID: "p1", ActorID: "writer", ActorRole: "contributor", Status: &published}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected a contributor's draft to need approval before going live, got %v", err)
+	}
+	created, err := svc.Create(ctx, CreatePostInput{ActorID: "writer", ActorRole: "contributor", Title: "Fresh", Content: "Text"})
+	if err != nil || created.Status != models.PostStatusDraft {
+		t.Fatalf("expected a contributor's post without a status to start as a draft, got %+v %v", created, err)
+	}
+
+	svc, _, _ = newReviewFixture(models.PostStatusInReview)
+	if _, err := svc.Review(ctx, ReviewPostInput{PostID: "p1", ActorID: "writer", ActorRole: "editor", Action: ReviewApprove}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected authors not to approve their own posts, got %v", err)
+	}
+
+	svc, _, _ = newReviewFixture(models.PostStatusPublished)
+	inReview := string(models.PostStatusInReview)
+	if _, err := svc.Update(ctx, UpdatePostInput{PostID: "p1", ActorID: "ed", ActorRole: "editor", Status: &inReview}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected a published post not to go back to review, got %v", err)
+	}
+}
+
+func TestPostReviewConflictsWithAConcurrentTransition(t *testing.T) {
+	svc, repo, reviews := newReviewFixture(models.PostStatusInReview)
+	ctx := context.Background()
+	// Another reviewer requests changes between this call's checks and its
+	// transaction.
+	repo.beforeLock = func(post *models.Post) { post.Status = models.PostStatusDraft }
+
+	_, err := svc.Review(ctx, ReviewPostInput{PostID: "p1", ActorID: "ed", ActorRole: "editor", Action: ReviewApprove})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if repo.post.Status != models.PostStatusDraft || len(reviews.events) != 0 {
+		t.Fatalf("expected nothing to be applied or recorded, got %s and %+v", repo.post.Status, reviews.events)
+	}
+}
+
+func TestPostReviewQueue(t *testing.T) {
+	svc, repo, _ := newReviewFixture(models.PostStatusInReview)
+	ctx := context.Background()
+
+	if _, _, err := svc.ReviewQueue(ctx, ReviewQueueInput{ActorID: "writer", ActorRole: "author"}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected authors to be kept out of the queue, got %v", err)
+	}
+
+	items, _, err := svc.ReviewQueue(ctx, ReviewQueueInput{ActorID: "ed", ActorRole: "editor", Assigned: "me"})
+	if err != nil || len(items) != 1 {
+		t.Fatalf("expected the queue to list the post, got %v (%v)", items, err)
+	}
+	filter := repo.lastFilter
+	if len(filter.Statuses) != 1 || filter.Statuses[0] != models.PostStatusInReview || !filter.ReviewOrder || filter.ReviewerID != "ed" {
+		t.Fatalf("unexpected queue filter %+v", filter)
+	}
+
+	if _, _, err := svc.ReviewQueue(ctx, ReviewQueueInput{ActorID: "ed", ActorRole: "editor", Assigned: "none"}); err != nil || !repo.lastFilter.Unassigned {
+		t.Fatalf("expected the unassigned filter, got %+v (%v)", repo.lastFilter, err)
+	}
+	if _, _, err := svc.ReviewQueue(ctx, ReviewQueueInput{ActorID: "ed", ActorRole: "editor", Assigned: "them"}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected an unknown assigned filter to be refused, got %v", err)
+	}
+}
+
+func TestPostReviewLetsPublishersSkipReview(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _ := newReviewFixture(models.PostStatusDraft)
+	repo.post.AuthorID = "owner"
+
+	published := string(models.PostStatusPublished)
+	if _, err := svc.Update(ctx, UpdatePostInput{PostID: "p1", ActorID: "owner", ActorRole: "author", Status: &published}); err != nil {
+		t.Fatalf("expected an author to publish their draft directly: %v", err)
+	}
+	if repo.post.Status != models.PostStatusPublished {
+		t.Fatalf("expected the post to be published, got %s", repo.post.Status)
+	}
+
+	svc, repo, _ = newReviewFixture(models.PostStatusDraft)
+	repo.post.AuthorID = "owner"
+	scheduled := string(models.PostStatusScheduled)
+	publishAt := time.Now().Add(time.Hour)
+	if _, err := svc.Update(ctx, UpdatePostInput{PostID: "p1", ActorID: "owner", ActorRole: "author", Status: &scheduled, PublishAt: &publishAt}); err != nil {
+		t.Fatalf("expected an author to schedule their draft directly: %v", err)
+	}
+	if repo.post.Status != models.PostStatusScheduled {
+		t.Fatalf("expected the post to be scheduled, got %s", repo.post.Status)
+	}
+
+	created, err := svc.Create(ctx, CreatePostInput{ActorID: "owner", ActorRole: "author", Title: "Fresh", Content: "Text"})
+	if err != nil || created.Status != models.PostStatusPublished {
+		t.Fatalf("expected an author's post without a status to be published, got %+v %v", created, err)
+	}
+}
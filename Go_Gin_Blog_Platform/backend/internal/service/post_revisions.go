@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/policy"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/textdiff"
+)
+
+const revisionDiffContext = 3
+
+var ErrRevisionNotFound = errors.New("revision not found")
+
+type PostRevisionInput struct {
+	PostID    string
+	Revision  int
+	ActorID   string
+	ActorRole string
+}
+
+type PostRevisionItem struct {
+	Revision  int               `json:"revision"`
+	EditorID  *string           `json:"editor_id"`
+	Title     string            `json:"title"`
+	Content   string            `json:"content"`
+	Status    models.PostStatus `json:"status"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+type PostRevisionDiff struct {
+	Revision int    `json:"revision"`
+	Diff     string `json:"diff"`
+}
+
+func (s *PostService) ListRevisions(ctx context.Context, input PostRevisionInput) ([]PostRevisionItem, error) {
+	post, err := s.modifiablePost(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.revisions.ListByPost(ctx, post.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list post revisions: %w", err)
+	}
+
+	items := make([]PostRevisionItem, 0, len(revisions))
+	for _, revision := range revisions {
+		items = append(items, toPostRevisionItem(revision))
+	}
+	return items, nil
+}
+
+// DiffRevision returns a unified diff from the stored revision to the current
+// version. The title is diffed as the first line so renames show up too.
+func (s *PostService) DiffRevision(ctx context.Context, input PostRevisionInput) (PostRevisionDiff, error) {
+	post, err := s.modifiablePost(ctx, input)
+	if err != nil {
+		return PostRevisionDiff{}, err
+	}
+
+	revision, err := s.getRevision(ctx, post.ID, input.Revision)
+	if err != nil {
+		return PostRevisionDiff{}, err
+	}
+
+	diff := textdiff.Unified(
+		fmt.Sprintf("revision %d", revision.Revision),
+		"current",
+		revisionDocument(revision.Title, revision.Content),
+		revisionDocument(post.Title, post.Content),
+		revisionDiffContext,
+	)
+	return PostRevisionDiff{Revision: revision.Revision, Diff: diff}, nil
+}
+
+// RestoreRevision brings back the title and content of a revision. The status
+// is left alone so restoring old text never publishes or unpublishes a post,
+// and the version being replaced is itself kept as a new revision.
+func (s *PostService) RestoreRevision(ctx context.Context, input PostRevisionInput) (PostItem, error) {
+	post, err := s.modifiablePost(ctx, input)
+	if err != nil {
+		return PostItem{}, err
+	}
+	if err := s.checkChange(policy.Actor{ID: input.ActorID, Role: input.ActorRole}, post, post.Status, true); err != nil {
+		return PostItem{}, err
+	}
+
+	revision, err := s.getRevision(ctx, post.ID, input.Revision)
+	if err != nil {
+		return PostItem{}, err
+	}
+
+	updates := map[string]any{
+		"title":   revision.Title,
+		"content": revision.Content,
+	}
+	if base := postSlugBase(revision.Title); !slugMatchesBase(post.Slug, base) {
+		slug, err := s.uniqueSlug(ctx, base, post.ID)
+		if err != nil {
+			return PostItem{}, err
+		}
+		updates["slug"] = slug
+	}
+
+	return s.applyUpdate(ctx, post, input.ActorID, updates, nil)
+}
+
+func (s *PostService) modifiablePost(ctx context.Context, input PostRevisionInput) (*models.Post, error) {
+	post, err := s.repo.GetByID(ctx, strings.TrimSpace(input.PostID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrPostNotFound
+		}
+		return nil, fmt.Errorf("get existing post: %w", err)
+	}
+
+	if !s.policy.Authorize(policy.Actor{ID: input.ActorID, Role: input.ActorRole}, policy.PostEditAny, &policy.Resource{OwnerID: post.AuthorID}) {
+		return nil, ErrForbidden
+	}
+	return post, nil
+}
+
+func (s *PostService) getRevision(ctx context.Context, postID string, number int) (*models.PostRevision, error) {
+	if number <= 0 {
+		return nil, ErrRevisionNotFound
+	}
+
+	revision, err := s.revisions.Get(ctx, postID, number)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, fmt.Errorf("get post revision: %w", err)
+	}
+	return revision, nil
+}
+
+func revisionDocument(title, content string) string {
+	return title + "\n\n" + content + "\n"
+}
+
+func toPostRevisionItem(revision models.PostRevision) PostRevisionItem {
+	return PostRevisionItem{
+		Revision:  revision.Revision,
+		EditorID:  revision.EditorID,
+		Title:     revision.Title,
+		Content:   revision.Content,
+		Status:    revision.Status,
+		CreatedAt: revision.CreatedAt,
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+type fakeRevisionRepo struct {
+	revisions []models.PostRevision
+}
+
+func (f *fakeRevisionRepo) Create(_ context.Context, revision *models.PostRevision) error {
+	revision.Revision = len(f.revisions) + 1
+	revision.CreatedAt = time.Now().UTC()
+	f.revisions = append(f.revisions, *revision)
+	return nil
+}
+
+func (f *fakeRevisionRepo) ListByPost(_ context.Context, postID string) ([]models.PostRevision, error) {
+	var out []models.PostRevision
+	for i := len(f.revisions) - 1; i >= 0; i-- {
+		if f.revisions[i].PostID == postID {
+			out = append(out, f.revisions[i])
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRevisionRepo) Get(_ context.Context, postID string, revision int) (*models.PostRevision, error) {
+	for _, stored := range f.revisions {
+		if stored.PostID == postID && stored.Revision == revision {
+			copy := stored
+			return &copy, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func TestPostServiceUpdateSnapshotsPreviousVersion(t *testing.T) {
+	repo := &fakePostRepo{post: models.Post{ID: "p1", AuthorID: "owner", Title: "Old", Slug: "old", Content: "Old text", Status: models.PostStatusDraft}}
+	revisions := &fakeRevisionRepo{}
+	svc := newTestPostService(repo, testPostDeps{revisions: revisions})
+
+	content := "New text"
+	if _, err := svc.Update(context.Background(), UpdatePostInput{PostID: "p1", ActorID: "owner", ActorRole: "author", Content: &content}); err != nil {
+		t.Fatalf("expected update to succeed: %v", err)
+	}
+
+	if len(revisions.revisions) != 1 {
+		t.Fatalf("expected one revision, got %d", len(revisions.revisions))
+	}
+	got := revisions.revisions[0]
+	if got.Revision != 1 || got.EditorID == nil || *got.EditorID != "owner" || got.Content != "Old text" || got.Status != models.PostStatusDraft {
+		t.Fatalf("unexpected revision snapshot: %+v", got)
+	}
+}
+
+func TestPostServiceUpdateSnapshotsTheLockedRow(t *testing.T) {
+	repo := &fakePostRepo{post: models.Post{ID: "p1", AuthorID: "owner", Title: "Old", Slug: "old", Content: "Old text", Status: models.PostStatusDraft}}
+	repo.beforeLock = func(post *models.Post) { post.Content = "Concurrent text" }
+	revisions := &fakeRevisionRepo{}
+	svc := newTestPostService(repo, testPostDeps{revisions: revisions})
+
+	content := "New text"
+	if _, err := svc.Update(context.Background(), UpdatePostInput{PostID: "p1", ActorID: "owner", ActorRole: "author", Content: &content}); err != nil {
+		t.Fatalf("expected update to succeed: %v", err)
+	}
+	if len(revisions.revisions) != 1 || revisions.revisions[0].Content != "Concurrent text" {
+		t.Fatalf("expected the revision to keep the text the concurrent edit wrote, got %+v", revisions.revisions)
+	}
+}
+
+func TestPostServiceRevisionsEnforceOwnership(t *testing.T) {
+	repo := &fakePostRepo{post: models.Post{ID: "p1", AuthorID: "owner", Title: "Old", Content: "Text", Status: models.PostStatusPublished}}
+	svc := newTestPostService(repo, testPostDeps{})
+
+	input := PostRevisionInput{PostID: "p1", Revision: 1, ActorID: "someone-else", ActorRole: "author"}
+	if _, err := svc.ListRevisions(context.Background(), input); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden listing revisions, got %v", err)
+	}
+	if _, err := svc.DiffRevision(context.Background(), input); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden diffing revision, got %v", err)
+	}
+	if _, err := svc.RestoreRevision(context.Background(), input); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden restoring revision, got %v", err)
+	}
+
+	input.ActorRole = "admin"
+	if _, err := svc.ListRevisions(context.Background(), input); err != nil {
+		t.Fatalf("expected admin to list revisions: %v", err)
+	}
+}
+
+func TestPostServiceDiffRevisionAgainstCurrent(t *testing.T) {
+	repo := &fakePostRepo{post: models.Post{ID: "p1", AuthorID: "owner", Title: "Title", Slug: "title", Content: "one\ntwo\nthree", Status: models.PostStatusPublished}}
+	svc := newTestPostService(repo, testPostDeps{})
+
+	content := "one\n2\nthree"
+	if _, err := svc.Update(context.Background(), UpdatePostInput{PostID: "p1", ActorID: "owner", ActorRole: "author", Content: &content}); err != nil {
+		t.Fatalf("expected update to succeed: %v", err)
+	}
+
+	diff, err := svc.DiffRevision(context.Background(), PostRevisionInput{PostID: "p1", Revision: 1, ActorID: "owner", ActorRole: "author"})
+	if err != nil {
+		t.Fatalf("expected diff to succeed: %v", err)
+	}
+	if !strings.HasPrefix(diff.Diff, "--- revision 1\n+++ current\n") {
+		t.Fatalf("unexpected diff header: %q", diff.Diff)
+	}
+	if !strings.Contains(diff.Diff, "-two\n+2\n") {
+		t.Fatalf("expected changed line in diff: %q", diff.Diff)
+	}
+
+	_, err = svc.DiffRevision(context.Background(), PostRevisionInput{PostID: "p1", Revision: 9, ActorID: "owner", ActorRole: "author"})
+	if !errors.Is(err, ErrRevisionNotFound) {
+		t.Fatalf("expected ErrRevisionNotFound, got %v", err)
+	}
+}
+
+func TestPostServiceRestoreRevisionKeepsStatusAndSnapshotsCurrent(t *testing.T) {
+	repo := &fakePostRepo{post: models.Post{ID: "p1", AuthorID: "owner", Title: "First", Slug: "first", Content: "Original", Status: models.PostStatusDraft}}
+	revisions := &fakeRevisionRepo{}
+	svc := newTestPostService(repo, testPostDeps{revisions: revisions})
+
+	title, content, status := "Second", "Edited", "published"
+	_, err := svc.Update(context.Background(), UpdatePostInput{PostID: "p1", ActorID: "owner", ActorRole: "editor", Title: &title, Content: &content, Status: &status})
+	if err != nil {
+		t.Fatalf("expected update to succeed: %v", err)
+	}
+
+	restored, err := svc.RestoreRevision(context.Background(), PostRevisionInput{PostID: "p1", Revision: 1, ActorID: "owner", ActorRole: "author"})
+	if err != nil {
+		t.Fatalf("expected restore to succeed: %v", err)
+	}
+	if restored.Title != "First" || restored.Content != "Original" || restored.Slug != "first" {
+		t.Fatalf("expected revision text restored, got %+v", restored)
+	}
+	if restored.Status != models.PostStatusPublished {
+		t.Fatalf("expected status to stay published, got %q", restored.Status)
+	}
+	if len(revisions.revisions) != 2 || revisions.revisions[1].Content != "Edited" {
+		t.Fatalf("expected replaced version kept as revision 2, got %+v", revisions.revisions)
+	}
+}
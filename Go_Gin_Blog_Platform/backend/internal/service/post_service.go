@@ -4,16 +4,27 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"html"
 	"strings"
 	"time"
 
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/policy"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/render"
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
 )
 
+const maxSearchQueryLength = 200
+
+const (
+	ContentFormatMarkdown = "markdown"
+	ContentFormatHTML     = "html"
+)
+
 var (
 	ErrPostNotFound = errors.New("post not found")
 	ErrForbidden    = errors.New("forbidden")
+	ErrConflict     = errors.New("conflict")
 )
 
 type CreatePostInput struct {
@@ -22,6 +33,9 @@ type CreatePostInput struct {
 	Title     string
 	Content   string
 	Status    string
+	PublishAt *time.Time
+	Tags      []string
+	Category  string
 }
 
 type UpdatePostInput struct {
@@ -31,6 +45,9 @@ type UpdatePostInput struct {
 	Title     *string
 	Content   *string
 	Status    *string
+	PublishAt *time.Time
+	Tags      *[]string
+	Category  *string
 }
 
 type DeletePostInput struct {
@@ -39,19 +56,95 @@ type DeletePostInput struct {
 	ActorRole string
 }
 
+type RestorePostInput struct {
+	PostID    string
+	ActorID   string
+	ActorRole string
+}
+
+type PurgePostInput struct {
+	PostID    string
+	ActorID   string
+	ActorRole string
+}
+
+type GetPostBySlugInput struct {
+	Slug       string
+	ViewerID   string
+	ViewerRole string
+}
+
+type GetPostInput struct {
+	PostID     string
+	ViewerID   string
+	ViewerRole string
+	Format     string
+}
+
 type ListPostsInput struct {
-	Page  int
-	Limit int
+	Page       int
+	Limit      int
+	Status     string
+	Tag        string
+	Category   string
+	Author     string
+	Format     string
+	ViewerID   string
+	ViewerRole string
+}
+
+type ListMyPostsInput struct {
+	ActorID string
+	Status  string
+	Page    int
+	Limit   int
+}
+
+type ListTrashInput struct {
+	ActorID string
+	Page    int
+	Limit   int
 }
 
 type PostItem struct {
 	ID        string            `json:"id"`
 	AuthorID  string            `json:"author_id"`
 	Title     string            `json:"title"`
+	Slug      string            `json:"slug"`
 	Content   string            `json:"content"`
+	Format    string            `json:"format"`
 	Status    models.PostStatus `json:"status"`
+	PublishAt *time.Time        `json:"publish_at"`
+	Tags      []TagRef          `json:"tags"`
+	Category  *CategoryRef      `json:"category"`
 	CreatedAt time.Time         `json:"created_at"`
 	UpdatedAt time.Time         `json:"updated_at"`
+
+	ReviewerID  *string    `json:"reviewer_id,omitempty"`
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
+}
+
+type SearchPostsInput struct {
+	Query string
+	Page  int
+	Limit int
+}
+
+type PostSearchItem struct {
+	PostItem
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+type TagRef struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type CategoryRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
 }
 
 type Pagination struct {
@@ -62,15 +155,41 @@ type Pagination struct {
 }
 
 type PostService struct {
-	repo repository.PostRepository
+	repo      repository.PostRepository
+	taxonomy  repository.TaxonomyRepository
+	revisions repository.PostRevisionRepository
+	reviews   repository.PostReviewRepository
+	users     repository.UserRepository
+	audit     *AuditService
+	tx        repository.Transactor
+	policy    *policy.Policy
 }
 
-func NewPostService(repo repository.PostRepository) *PostService {
-	return &PostService{repo: repo}
+func NewPostService(
+	repo repository.PostRepository,
+	taxonomy repository.TaxonomyRepository,
+	revisions repository.PostRevisionRepository,
+	reviews repository.PostReviewRepository,
+	users repository.UserRepository,
+	audit *AuditService,
+	tx repository.Transactor,
+	authz *policy.Policy,
+) *PostService {
+	return &PostService{
+		repo:      repo,
+		taxonomy:  taxonomy,
+		revisions: revisions,
+		reviews:   reviews,
+		users:     users,
+		audit:     audit,
+		tx:        tx,
+		policy:    authz,
+	}
 }
 
 func (s *PostService) Create(ctx context.Context, input CreatePostInput) (PostItem, error) {
-	if !canWritePosts(input.ActorRole) {
+	actor := policy.Actor{ID: input.ActorID, Role: input.ActorRole}
+	if !s.policy.Authorize(actor, policy.PostCreate, nil) {
 		return PostItem{}, ErrForbidden
 	}
 
@@ -80,48 +199,239 @@ func (s *PostService) Create(ctx context.Context, input CreatePostInput) (PostIt
 		return PostItem{}, fmt.Errorf("title and content are required: %w", ErrValidation)
 	}
 
-	status, err := normalizeStatus(input.Status)
+	status, err := normalizeStatus(input.Status, input.PublishAt, s.policy.Authorize(actor, policy.PostPublish, nil))
+	if err != nil {
+		return PostItem{}, err
+	}
+	if status != models.PostStatusDraft {
+		if err := s.checkTransition(actor, input.ActorID, models.PostStatusDraft, status); err != nil {
+			return PostItem{}, err
+		}
+	}
+
+	tags, err := normalizeTags(input.Tags)
+	if err != nil {
+		return PostItem{}, err
+	}
+
+	categoryID, err := s.resolveCategory(ctx, input.Category)
+	if err != nil {
+		return PostItem{}, err
+	}
+
+	contentHTML, err := render.Markdown(content)
 	if err != nil {
 		return PostItem{}, err
 	}
 
 	post := &models.Post{
-		AuthorID: input.ActorID,
-		Title:    title,
-		Content:  content,
-		Status:   status,
+		AuthorID:    input.ActorID,
+		Title:       title,
+		Content:     content,
+		ContentHTML: contentHTML,
+		Status:      status,
+		PublishAt:   publishAtFor(status, input.PublishAt),
+		CategoryID:  categoryID,
+	}
+	if status == models.PostStatusInReview {
+		submittedAt := time.Now().UTC()
+		post.SubmittedAt = &submittedAt
 	}
 
-	if err := s.repo.Create(ctx, post); err != nil {
-		return PostItem{}, fmt.Errorf("create post: %w", err)
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.createWithUniqueSlug(ctx, post); err != nil {
+			return err
+		}
+		return s.replaceTags(ctx, post.ID, tags)
+	})
+	if err != nil {
+		return PostItem{}, err
 	}
 
-	return toPostItem(*post), nil
+	created, err := s.repo.GetByID(ctx, post.ID)
+	if err != nil {
+		return PostItem{}, fmt.Errorf("reload created post: %w", err)
+	}
+	return toPostItem(*created), nil
+}
+
+func (s *PostService) createWithUniqueSlug(ctx context.Context, post *models.Post) error {
+	// A concurrent create can claim the same slug between the availability
+	// check and the insert; the unique index catches it and we pick again.
+	for attempt := 0; ; attempt++ {
+		slug, err := s.uniqueSlug(ctx, postSlugBase(post.Title), "")
+		if err != nil {
+			return err
+		}
+		post.Slug = slug
+
+		err = s.repo.Create(ctx, post)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, repository.ErrDuplicate) || attempt >= 2 {
+			return fmt.Errorf("create post: %w", err)
+		}
+	}
 }
 
-func (s *PostService) GetByID(ctx context.Context, postID string) (PostItem, error) {
-	post, err := s.repo.GetByID(ctx, strings.TrimSpace(postID))
+func (s *PostService) GetByID(ctx context.Context, input GetPostInput) (PostItem, error) {
+	format, err := parseContentFormat(input.Format)
+	if err != nil {
+		return PostItem{}, err
+	}
+
+	post, err := s.repo.GetByID(ctx, strings.TrimSpace(input.PostID))
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return PostItem{}, ErrPostNotFound
 		}
 		return PostItem{}, fmt.Errorf("get post by id: %w", err)
 	}
+
+	// Unpublished posts are reported as missing so their IDs can't be probed.
+	if !canViewPost(s.policy, input.ViewerRole, input.ViewerID, *post) {
+		return PostItem{}, ErrPostNotFound
+	}
+
+	return toFormattedPostItem(*post, format)
+}
+
+// GetBySlug resolves current and historical slugs. Callers detect a historical
+// match by comparing the requested slug with the returned canonical Slug.
+func (s *PostService) GetBySlug(ctx context.Context, input GetPostBySlugInput) (PostItem, error) {
+	slug := strings.ToLower(strings.TrimSpace(input.Slug))
+	if slug == "" {
+		return PostItem{}, ErrPostNotFound
+	}
+
+	post, err := s.repo.GetBySlug(ctx, slug)
+	if errors.Is(err, repository.ErrNotFound) {
+		post, err = s.repo.GetByHistoricalSlug(ctx, slug)
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return PostItem{}, ErrPostNotFound
+		}
+		return PostItem{}, fmt.Errorf("get post by slug: %w", err)
+	}
+
+	if !canViewPost(s.policy, input.ViewerRole, input.ViewerID, *post) {
+		return PostItem{}, ErrPostNotFound
+	}
 	return toPostItem(*post), nil
 }
 
 func (s *PostService) List(ctx context.Context, input ListPostsInput) ([]PostItem, Pagination, error) {
+	format, err := parseContentFormat(input.Format)
+	if err != nil {
+		return nil, Pagination{}, err
+	}
+
+	tagSlug, err := parseSlugFilter("tag", input.Tag)
+	if err != nil {
+		return nil, Pagination{}, err
+	}
+	categorySlug, err := parseSlugFilter("category", input.Category)
+	if err != nil {
+		return nil, Pagination{}, err
+	}
+
+	filter := repository.PostListFilter{
+		AuthorID:     strings.TrimSpace(input.Author),
+		Statuses:     []models.PostStatus{models.PostStatusPublished},
+		TagSlug:      tagSlug,
+		CategorySlug: categorySlug,
+	}
+	if strings.TrimSpace(input.Status) != "" {
+		status, err := parseStatusFilter(input.Status)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+		if status != models.PostStatusPublished && !s.policy.Authorize(policy.Actor{ID: input.ViewerID, Role: input.ViewerRole}, policy.PostReadAny, nil) {
+			return nil, Pagination{}, ErrForbidden
+		}
+		filter.Statuses = []models.PostStatus{status}
+	}
+
+	return s.list(ctx, filter, input.Page, input.Limit, format)
+}
+
+func (s *PostService) Search(ctx context.Context, input SearchPostsInput) ([]PostSearchItem, Pagination, error) {
+	query := strings.TrimSpace(input.Query)
+	if query == "" {
+		return nil, Pagination{}, fmt.Errorf("search query is required: %w", ErrValidation)
+	}
+	if len(query) > maxSearchQueryLength {
+		return nil, Pagination{}, fmt.Errorf("search query exceeds %d characters: %w", maxSearchQueryLength, ErrValidation)
+	}
+
 	page, limit := normalizePagination(input.Page, input.Limit)
 	offset := (page - 1) * limit
 
-	posts, total, err := s.repo.List(ctx, limit, offset)
+	filter := repository.PostListFilter{Statuses: []models.PostStatus{models.PostStatusPublished}}
+	results, total, err := s.repo.Search(ctx, query, filter, limit, offset)
+	if err != nil {
+		return nil, Pagination{}, fmt.Errorf("search posts: %w", err)
+	}
+
+	items := make([]PostSearchItem, 0, len(results))
+	for _, result := range results {
+		items = append(items, PostSearchItem{
+			PostItem: toPostItem(result.Post),
+			Rank:     result.Rank,
+			Snippet:  highlightSnippet(result.Snippet),
+		})
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	return items, Pagination{Page: page, Limit: limit, Total: total, TotalPages: totalPages}, nil
+}
+
+func (s *PostService) ListMine(ctx context.Context, input ListMyPostsInput) ([]PostItem, Pagination, error) {
+	actorID := strings.TrimSpace(input.ActorID)
+	if actorID == "" {
+		return nil, Pagination{}, ErrForbidden
+	}
+
+	filter := repository.PostListFilter{AuthorID: actorID}
+	if strings.TrimSpace(input.Status) != "" {
+		status, err := parseStatusFilter(input.Status)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+		filter.Statuses = []models.PostStatus{status}
+	}
+
+	return s.list(ctx, filter, input.Page, input.Limit, ContentFormatMarkdown)
+}
+
+func (s *PostService) ListTrash(ctx context.Context, input ListTrashInput) ([]PostItem, Pagination, error) {
+	actorID := strings.TrimSpace(input.ActorID)
+	if actorID == "" {
+		return nil, Pagination{}, ErrForbidden
+	}
+
+	filter := repository.PostListFilter{AuthorID: actorID, Trashed: true}
+	return s.list(ctx, filter, input.Page, input.Limit, ContentFormatMarkdown)
+}
+
+func (s *PostService) list(ctx context.Context, filter repository.PostListFilter, page, limit int, format string) ([]PostItem, Pagination, error) {
+	page, limit = normalizePagination(page, limit)
+	offset := (page - 1) * limit
+
+	posts, total, err := s.repo.List(ctx, filter, limit, offset)
 	if err != nil {
 		return nil, Pagination{}, fmt.Errorf("list posts: %w", err)
 	}
 
 	items := make([]PostItem, 0, len(posts))
 	for _, post := range posts {
-		items = append(items, toPostItem(post))
+		item, err := toFormattedPostItem(post, format)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+		items = append(items, item)
 	}
 
 	totalPages := int((total + int64(limit) - 1) / int64(limit))
@@ -129,10 +439,6 @@ func (s *PostService) List(ctx context.Context, input ListPostsInput) ([]PostIte
 }
 
 func (s *PostService) Update(ctx context.Context, input UpdatePostInput) (PostItem, error) {
-	if !canWritePosts(input.ActorRole) {
-		return PostItem{}, ErrForbidden
-	}
-
 	post, err := s.repo.GetByID(ctx, strings.TrimSpace(input.PostID))
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
@@ -141,17 +447,27 @@ func (s *PostService) Update(ctx context.Context, input UpdatePostInput) (PostIt
 		return PostItem{}, fmt.Errorf("get existing post: %w", err)
 	}
 
-	if !canModifyPost(input.ActorRole, input.ActorID, post.AuthorID) {
+	actor := policy.Actor{ID: input.ActorID, Role: input.ActorRole}
+	if !s.policy.Authorize(actor, policy.PostEditAny, &policy.Resource{OwnerID: post.AuthorID}) {
 		return PostItem{}, ErrForbidden
 	}
 
 	updates := map[string]any{}
+	target := post.Status
 	if input.Title != nil {
 		title := strings.TrimSpace(*input.Title)
 		if title == "" {
 			return PostItem{}, fmt.Errorf("title cannot be empty: %w", ErrValidation)
 		}
 		updates["title"] = title
+
+		if base := postSlugBase(title); !slugMatchesBase(post.Slug, base) {
+			slug, err := s.uniqueSlug(ctx, base, post.ID)
+			if err != nil {
+				return PostItem{}, err
+			}
+			updates["slug"] = slug
+		}
 	}
 	if input.Content != nil {
 		content := strings.TrimSpace(*input.Content)
@@ -160,22 +476,116 @@ func (s *PostService) Update(ctx context.Context, input UpdatePostInput) (PostIt
 		}
 		updates["content"] = content
 	}
-	if input.Status != nil {
-		status, err := normalizeStatus(*input.Status)
+	if input.Status != nil || input.PublishAt != nil {
+		// A bare publish_at reschedules the post and is only valid while it
+		// is still scheduled.
+		requested := string(post.Status)
+		if input.Status != nil {
+			requested = *input.Status
+		}
+		status, err := normalizeStatus(requested, input.PublishAt, s.policy.Authorize(actor, policy.PostPublish, nil))
 		if err != nil {
 			return PostItem{}, err
 		}
+		target = status
 		updates["status"] = status
+		updates["publish_at"] = publishAtFor(status, input.PublishAt)
 	}
 
-	if len(updates) == 0 {
+	if input.Category != nil {
+		categoryID, err := s.resolveCategory(ctx, *input.Category)
+		if err != nil {
+			return PostItem{}, err
+		}
+		updates["category_id"] = categoryID
+	}
+
+	var tags []models.Tag
+	if input.Tags != nil {
+		tags, err = normalizeTags(*input.Tags)
+		if err != nil {
+			return PostItem{}, err
+		}
+	}
+
+	if len(updates) == 0 && input.Tags == nil {
 		return PostItem{}, fmt.Errorf("no update fields provided: %w", ErrValidation)
 	}
+	edits := input.Title != nil || input.Content != nil || input.Category != nil || input.Tags != nil
+	if err := s.checkChange(actor, post, target, edits); err != nil {
+		return PostItem{}, err
+	}
+	if target != post.Status {
+		trackSubmission(updates, target)
+	}
+
+	var replaceTags *[]models.Tag
+	if input.Tags != nil {
+		replaceTags = &tags
+	}
+	return s.applyUpdate(ctx, post, input.ActorID, updates, replaceTags)
+}
+
+// applyUpdate snapshots the stored version of post as a revision and applies
+// updates in the same transaction, so no edit can lose the previous text. The
+// row is locked and re-read first, so concurrent edits each snapshot what the
+// one before them wrote; a status that moved since post was read fails with
+// ErrConflict. A status change is also recorded in the post's review history.
+func (s *PostService) applyUpdate(ctx context.Context, post *models.Post, editorID string, updates map[string]any, tags *[]models.Tag) (PostItem, error) {
+	if content, ok := updates["content"].(string); ok {
+		contentHTML, err := render.Markdown(content)
+		if err != nil {
+			return PostItem{}, err
+		}
+		updates["content_html"] = contentHTML
+	}
 
-	if err := s.repo.Update(ctx, post.ID, updates); err != nil {
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, err := s.lockPost(ctx, post)
+		if err != nil {
+			return err
+		}
+		revision := &models.PostRevision{
+			PostID:   current.ID,
+			EditorID: &editorID,
+			Title:    current.Title,
+			Content:  current.Content,
+			Status:   current.Status,
+		}
+		if err := s.revisions.Create(ctx, revision); err != nil {
+			return err
+		}
+		if err := s.repo.Update(ctx, post.ID, updates); err != nil {
+			return err
+		}
+		if status, ok := updates["status"].(models.PostStatus); ok && status != current.Status {
+			event := &models.PostReviewEvent{
+				PostID:     post.ID,
+				ActorID:    &editorID,
+				Action:     transitionAction(current.Status, status, editorID == current.AuthorID),
+				FromStatus: current.Status,
+				ToStatus:   status,
+				ReviewerID: current.ReviewerID,
+			}
+			if err := s.reviews.Create(ctx, event); err != nil {
+				return err
+			}
+			if err := s.auditReview(ctx, current, event); err != nil {
+				return err
+			}
+		}
+		if tags != nil {
+			return s.replaceTags(ctx, post.ID, *tags)
+		}
+		return nil
+	})
+	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return PostItem{}, ErrPostNotFound
 		}
+		if errors.Is(err, repository.ErrDuplicate) {
+			return PostItem{}, fmt.Errorf("post was changed concurrently, retry the update: %w", ErrConflict)
+		}
 		return PostItem{}, fmt.Errorf("update post: %w", err)
 	}
 
@@ -190,10 +600,6 @@ func (s *PostService) Update(ctx context.Context, input UpdatePostInput) (PostIt
 }
 
 func (s *PostService) Delete(ctx context.Context, input DeletePostInput) error {
-	if !canWritePosts(input.ActorRole) {
-		return ErrForbidden
-	}
-
 	post, err := s.repo.GetByID(ctx, strings.TrimSpace(input.PostID))
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
@@ -202,11 +608,23 @@ func (s *PostService) Delete(ctx context.Context, input DeletePostInput) error {
 		return fmt.Errorf("get existing post: %w", err)
 	}
 
-	if !canModifyPost(input.ActorRole, input.ActorID, post.AuthorID) {
+	if !s.policy.Authorize(policy.Actor{ID: input.ActorID, Role: input.ActorRole}, policy.PostEditAny, &policy.Resource{OwnerID: post.AuthorID}) {
 		return ErrForbidden
 	}
 
-	if err := s.repo.Delete(ctx, post.ID); err != nil {
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Delete(ctx, post.ID); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditRecord{
+			ActorID:    input.ActorID,
+			Action:     AuditPostDelete,
+			TargetType: AuditTargetPost,
+			TargetID:   post.ID,
+			Before:     auditPostState(post),
+		})
+	})
+	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return ErrPostNotFound
 		}
@@ -216,15 +634,184 @@ func (s *PostService) Delete(ctx context.Context, input DeletePostInput) error {
 	return nil
 }
 
+func (s *PostService) Restore(ctx context.Context, input RestorePostInput) (PostItem, error) {
+	post, err := s.repo.GetTrashedByID(ctx, strings.TrimSpace(input.PostID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return PostItem{}, ErrPostNotFound
+		}
+		return PostItem{}, fmt.Errorf("get trashed post: %w", err)
+	}
+
+	// Restoring a post that was live puts it back live.
+	actor := policy.Actor{ID: input.ActorID, Role: input.ActorRole}
+	if !s.policy.Authorize(actor, policy.PostEditAny, &policy.Resource{OwnerID: post.AuthorID}) || !s.mayLeaveIn(actor, post.Status) {
+		return PostItem{}, ErrForbidden
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Restore(ctx, post.ID); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditRecord{
+			ActorID:    input.ActorID,
+			Action:     AuditPostRestore,
+			TargetType: AuditTargetPost,
+			TargetID:   post.ID,
+			After:      auditPostState(post),
+		})
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return PostItem{}, ErrPostNotFound
+		}
+		return PostItem{}, fmt.Errorf("restore post: %w", err)
+	}
+
+	restored, err := s.repo.GetByID(ctx, post.ID)
+	if err != nil {
+		return PostItem{}, fmt.Errorf("reload restored post: %w", err)
+	}
+	return toPostItem(*restored), nil
+}
+
+// Purge permanently removes a post that is already in the trash.
+func (s *PostService) Purge(ctx context.Context, input PurgePostInput) error {
+	if !s.policy.Authorize(policy.Actor{Role: input.ActorRole}, policy.PostPurge, nil) {
+		return ErrForbidden
+	}
+
+	post, err := s.repo.GetTrashedByID(ctx, strings.TrimSpace(input.PostID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrPostNotFound
+		}
+		return fmt.Errorf("get trashed post: %w", err)
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Purge(ctx, post.ID); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditRecord{
+			ActorID:    input.ActorID,
+			Action:     AuditPostPurge,
+			TargetType: AuditTargetPost,
+			TargetID:   post.ID,
+			Before:     auditPostState(post),
+		})
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrPostNotFound
+		}
+		return fmt.Errorf("purge post: %w", err)
+	}
+	return nil
+}
+
+// auditPostState is what the audit log keeps of a post.
+func auditPostState(post *models.Post) map[string]any {
+	return map[string]any{"title": post.Title, "slug": post.Slug, "status": post.Status, "author_id": post.AuthorID}
+}
+
+// resolveCategory maps a category slug from the API to its ID; an empty slug
+// means no category.
+func (s *PostService) resolveCategory(ctx context.Context, slug string) (*string, error) {
+	slug = slugify(slug)
+	if slug == "" {
+		return nil, nil
+	}
+
+	category, err := s.taxonomy.GetCategoryBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("category %q does not exist: %w", slug, ErrValidation)
+		}
+		return nil, fmt.Errorf("resolve category: %w", err)
+	}
+	return &category.ID, nil
+}
+
+func (s *PostService) replaceTags(ctx context.Context, postID string, tags []models.Tag) error {
+	stored, err := s.taxonomy.UpsertTags(ctx, tags)
+	if err != nil {
+		return fmt.Errorf("store tags: %w", err)
+	}
+
+	tagIDs := make([]string, 0, len(stored))
+	for _, tag := range stored {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	if err := s.taxonomy.ReplacePostTags(ctx, postID, tagIDs); err != nil {
+		return fmt.Errorf("attach tags: %w", err)
+	}
+	return nil
+}
+
+// highlightSnippet escapes the post text and only then turns the repository's
+// match markers into <mark> tags, so snippets are safe to render as HTML.
+func highlightSnippet(snippet string) string {
+	escaped := html.EscapeString(snippet)
+	escaped = strings.ReplaceAll(escaped, repository.SnippetStartMarker, "<mark>")
+	return strings.ReplaceAll(escaped, repository.SnippetStopMarker, "</mark>")
+}
+
 func toPostItem(post models.Post) PostItem {
-	return PostItem{
+	item := PostItem{
 		ID:        post.ID,
 		AuthorID:  post.AuthorID,
 		Title:     post.Title,
+		Slug:      post.Slug,
 		Content:   post.Content,
+		Format:    ContentFormatMarkdown,
 		Status:    post.Status,
+		PublishAt: post.PublishAt,
+		Tags:      make([]TagRef, 0, len(post.Tags)),
 		CreatedAt: post.CreatedAt,
 		UpdatedAt: post.UpdatedAt,
+
+		ReviewerID:  post.ReviewerID,
+		SubmittedAt: post.SubmittedAt,
+	}
+	for _, tag := range post.Tags {
+		item.Tags = append(item.Tags, TagRef{Name: tag.Name, Slug: tag.Slug})
+	}
+	if post.Category != nil {
+		item.Category = &CategoryRef{ID: post.Category.ID, Name: post.Category.Name, Slug: post.Category.Slug}
+	}
+	return item
+}
+
+// toFormattedPostItem is toPostItem with the content in the requested format.
+// HTML comes from the cached rendering, or is rendered on the fly for posts
+// saved before content_html existed.
+func toFormattedPostItem(post models.Post, format string) (PostItem, error) {
+	item := toPostItem(post)
+	if format != ContentFormatHTML {
+		return item, nil
+	}
+
+	item.Format = ContentFormatHTML
+	item.Content = post.ContentHTML
+	if item.Content == "" && post.Content != "" {
+		rendered, err := render.Markdown(post.Content)
+		if err != nil {
+			return PostItem{}, err
+		}
+		item.Content = rendered
+	}
+	return item, nil
+}
+
+func parseContentFormat(format string) (string, error) {
+	switch value := strings.ToLower(strings.TrimSpace(format)); value {
+	case "", ContentFormatMarkdown:
+		return ContentFormatMarkdown, nil
+	case ContentFormatHTML:
+		return value, nil
+	default:
+		return "", fmt.Errorf("format must be markdown or html: %w", ErrValidation)
 	}
 }
 
@@ -241,27 +828,94 @@ func normalizePagination(page, limit int) (int, int) {
 	return page, limit
 }
 
-func normalizeStatus(status string) (models.PostStatus, error) {
+// normalizeStatus validates a requested status together with its publish
+// time. An empty status defaults to published, or to scheduled when a publish
+// time is given, for actors who may publish and to draft for everyone else;
+// only scheduled posts may carry a publish time and it must be in the future.
+func normalizeStatus(status string, publishAt *time.Time, mayPublish bool) (models.PostStatus, error) {
 	value := strings.ToLower(strings.TrimSpace(status))
+	if value == "" && !mayPublish {
+		value = string(models.PostStatusDraft)
+	}
 	if value == "" {
-		return models.PostStatusPublished, nil
+		value = string(models.PostStatusPublished)
+		if publishAt != nil {
+			value = string(models.PostStatusScheduled)
+		}
 	}
 
 	s := models.PostStatus(value)
-	if s != models.PostStatusDraft && s != models.PostStatusPublished {
-		return "", fmt.Errorf("status must be draft or published: %w", ErrValidation)
+	if !isKnownStatus(s) {
+		return "", fmt.Errorf("status must be draft, in_review, approved, published or scheduled: %w", ErrValidation)
+	}
+
+	if s != models.PostStatusScheduled {
+		if publishAt != nil {
+			return "", fmt.Errorf("publish_at is only allowed for scheduled posts: %w", ErrValidation)
+		}
+		return s, nil
+	}
+	if publishAt == nil {
+		return "", fmt.Errorf("publish_at is required for scheduled posts: %w", ErrValidation)
+	}
+	if !publishAt.After(time.Now()) {
+		return "", fmt.Errorf("publish_at must be in the future: %w", ErrValidation)
 	}
 	return s, nil
 }
 
-func canWritePosts(role string) bool {
-	role = strings.ToLower(strings.TrimSpace(role))
-	return role == string(models.RoleAdmin) || role == string(models.RoleAuthor)
+func publishAtFor(status models.PostStatus, publishAt *time.Time) *time.Time {
+	if status != models.PostStatusScheduled || publishAt == nil {
+		return nil
+	}
+	utc := publishAt.UTC()
+	return &utc
 }
 
-func canModifyPost(actorRole, actorID, authorID string) bool {
-	if strings.EqualFold(strings.TrimSpace(actorRole), string(models.RoleAdmin)) {
+func parseStatusFilter(status string) (models.PostStatus, error) {
+	value := strings.ToLower(strings.TrimSpace(status))
+	s := models.PostStatus(value)
+	if !isKnownStatus(s) {
+		return "", fmt.Errorf("status filter must be draft, in_review, approved, published or scheduled: %w", ErrValidation)
+	}
+	return s, nil
+}
+
+// parseSlugFilter slugifies a tag or category filter. A filter with no letters
+// or digits is rejected rather than dropped, which would list every post.
+func parseSlugFilter(name, value string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return "", nil
+	}
+	slug := slugify(value)
+	if slug == "" {
+		return "", fmt.Errorf("%s filter %q has no letters or digits: %w", name, value, ErrValidation)
+	}
+	return slug, nil
+}
+
+func isKnownStatus(status models.PostStatus) bool {
+	switch status {
+	case models.PostStatusDraft, models.PostStatusInReview, models.PostStatusApproved,
+		models.PostStatusPublished, models.PostStatusScheduled:
+		return true
+	}
+	return false
+}
+
+// mayLeaveIn reports whether actor may leave a post in status: a live or
+// scheduled post takes post.publish.
+func (s *PostService) mayLeaveIn(actor policy.Actor, status models.PostStatus) bool {
+	return !isLive(status) || s.policy.Authorize(actor, policy.PostPublish, nil)
+}
+
+func canViewPost(authz *policy.Policy, viewerRole, viewerID string, post models.Post) bool {
+	if post.Status == models.PostStatusPublished {
+		return true
+	}
+	viewer := policy.Actor{ID: viewerID, Role: viewerRole}
+	if underReview(post.Status) && authz.Authorize(viewer, policy.PostReview, nil) {
 		return true
 	}
-	return strings.TrimSpace(actorID) != "" && strings.TrimSpace(actorID) == strings.TrimSpace(authorID)
+	return authz.Authorize(viewer, policy.PostReadAny, &policy.Resource{OwnerID: post.AuthorID})
 }
@@ -3,19 +3,72 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/policy"
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+	"gorm.io/gorm"
 )
 
+func defaultPolicy() *policy.Policy {
+	return policy.New(policy.DefaultGrants())
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// testPostDeps overrides the collaborators newTestPostService wires in; nil
+// fields get empty fakes.
+type testPostDeps struct {
+	taxonomy  *fakeTaxonomyRepo
+	revisions *fakeRevisionRepo
+	reviews   *fakeReviewRepo
+	users     *fakeUserRepo
+	audit     *AuditService
+	policy    *policy.Policy
+}
+
+func newTestPostService(repo *fakePostRepo, deps testPostDeps) *PostService {
+	if deps.taxonomy == nil {
+		deps.taxonomy = &fakeTaxonomyRepo{}
+	}
+	if deps.revisions == nil {
+		deps.revisions = &fakeRevisionRepo{}
+	}
+	if deps.reviews == nil {
+		deps.reviews = &fakeReviewRepo{}
+	}
+	if deps.users == nil {
+		deps.users = &fakeUserRepo{}
+	}
+	if deps.audit == nil {
+		deps.audit = newTestAudit()
+	}
+	if deps.policy == nil {
+		deps.policy = defaultPolicy()
+	}
+	return NewPostService(repo, deps.taxonomy, deps.revisions, deps.reviews, deps.users, deps.audit, fakeTransactor{}, deps.policy)
+}
+
 type fakePostRepo struct {
 	post       models.Post
 	listTotal  int64
 	listPosts  []models.Post
 	lastLimit  int
 	lastOffset int
+	lastFilter repository.PostListFilter
+	takenSlugs map[string]bool
+	history    map[string]string
+	trashed    *models.Post
+	// beforeLock stands in for a write that commits just before the row is
+	// locked.
+	beforeLock func(post *models.Post)
 }
 
 func (f *fakePostRepo) Create(_ context.Context, post *models.Post) error {
@@ -37,7 +90,41 @@ func (f *fakePostRepo) GetByID(_ context.Context, id string) (*models.Post, erro
 	return &copy, nil
 }
 
-func (f *fakePostRepo) List(_ context.Context, limit, offset int) ([]models.Post, int64, error) {
+func (f *fakePostRepo) GetByIDForUpdate(ctx context.Context, id string) (*models.Post, error) {
+	if f.beforeLock != nil {
+		f.beforeLock(&f.post)
+	}
+	return f.GetByID(ctx, id)
+}
+
+func (f *fakePostRepo) GetBySlug(_ context.Context, slug string) (*models.Post, error) {
+	if f.post.ID == "" || slug != f.post.Slug {
+		return nil, repository.ErrNotFound
+	}
+	copy := f.post
+	return &copy, nil
+}
+
+func (f *fakePostRepo) GetByHistoricalSlug(_ context.Context, slug string) (*models.Post, error) {
+	if f.post.ID == "" || f.history[slug] != f.post.ID {
+		return nil, repository.ErrNotFound
+	}
+	copy := f.post
+	return &copy, nil
+}
+
+func (f *fakePostRepo) SlugTaken(_ context.Context, slug, excludePostID string) (bool, error) {
+	if f.post.ID != "" && f.post.ID != excludePostID && f.post.Slug == slug {
+		return true, nil
+	}
+	if owner, ok := f.history[slug]; ok && owner != excludePostID {
+		return true, nil
+	}
+	return f.takenSlugs[slug], nil
+}
+
+func (f *fakePostRepo) List(_ context.Context, filter repository.PostListFilter, limit, offset int) ([]models.Post, int64, error) {
+	f.lastFilter = filter
 	f.lastLimit = limit
 	f.lastOffset = offset
 	if f.listPosts != nil {
@@ -46,6 +133,14 @@ func (f *fakePostRepo) List(_ context.Context, limit, offset int) ([]models.Post
 	return []models.Post{f.post}, 1, nil
 }
 
+func (f *fakePostRepo) Search(_ context.Context, query string, filter repository.PostListFilter, limit, offset int) ([]repository.PostSearchResult, int64, error) {
+	f.lastFilter = filter
+	f.lastLimit = limit
+	f.lastOffset = offset
+	results, total := repository.SearchPostsInMemory(f.listPosts, query, filter, limit, offset)
+	return results, total, nil
+}
+
 func (f *fakePostRepo) Update(_ context.Context, id string, updates map[string]any) error {
 	if f.post.ID == "" || id != f.post.ID {
 		return repository.ErrNotFound
@@ -53,12 +148,35 @@ func (f *fakePostRepo) Update(_ context.Context, id string, updates map[string]a
 	if v, ok := updates["title"].(string); ok {
 		f.post.Title = v
 	}
+	if v, ok := updates["slug"].(string); ok && v != f.post.Slug {
+		if f.history == nil {
+			f.history = map[string]string{}
+		}
+		f.history[f.post.Slug] = f.post.ID
+		f.post.Slug = v
+	}
 	if v, ok := updates["content"].(string); ok {
 		f.post.Content = v
 	}
+	if v, ok := updates["content_html"].(string); ok {
+		f.post.ContentHTML = v
+	}
 	if v, ok := updates["status"].(models.PostStatus); ok {
 		f.post.Status = v
 	}
+	if v, ok := updates["publish_at"]; ok {
+		f.post.PublishAt, _ = v.(*time.Time)
+	}
+	if v, ok := updates["submitted_at"]; ok {
+		if at, ok := v.(time.Time); ok {
+			f.post.SubmittedAt = &at
+		} else {
+			f.post.SubmittedAt = nil
+		}
+	}
+	if v, ok := updates["reviewer_id"].(string); ok {
+		f.post.ReviewerID = &v
+	}
 	f.post.UpdatedAt = time.Now().UTC()
 	return nil
 }
@@ -67,12 +185,39 @@ func (f *fakePostRepo) Delete(_ context.Context, id string) error {
 	if f.post.ID == "" || id != f.post.ID {
 		return repository.ErrNotFound
 	}
+	trashed := f.post
+	f.trashed = &trashed
 	f.post = models.Post{}
 	return nil
 }
 
+func (f *fakePostRepo) GetTrashedByID(_ context.Context, id string) (*models.Post, error) {
+	if f.trashed == nil || f.trashed.ID != id {
+		return nil, repository.ErrNotFound
+	}
+	copy := *f.trashed
+	return &copy, nil
+}
+
+func (f *fakePostRepo) Restore(_ context.Context, id string) error {
+	if f.trashed == nil || f.trashed.ID != id {
+		return repository.ErrNotFound
+	}
+	f.post = *f.trashed
+	f.trashed = nil
+	return nil
+}
+
+func (f *fakePostRepo) Purge(_ context.Context, id string) error {
+	if f.trashed == nil || f.trashed.ID != id {
+		return repository.ErrNotFound
+	}
+	f.trashed = nil
+	return nil
+}
+
 func TestPostServiceCreateRejectsReader(t *testing.T) {
-	svc := NewPostService(&fakePostRepo{})
+	svc := newTestPostService(&fakePostRepo{}, testPostDeps{})
 
 	_, err := svc.Create(context.Background(), CreatePostInput{
 		ActorID:   "u1",
@@ -87,7 +232,7 @@ func TestPostServiceCreateRejectsReader(t *testing.T) {
 
 func TestPostServiceUpdateEnforcesOwnership(t *testing.T) {
 	repo := &fakePostRepo{post: models.Post{ID: "p1", AuthorID: "owner", Title: "Old", Content: "Text", Status: models.PostStatusPublished}}
-	svc := NewPostService(repo)
+	svc := newTestPostService(repo, testPostDeps{})
 
 	title := "New"
 	_, err := svc.Update(context.Background(), UpdatePostInput{
@@ -102,9 +247,38 @@ func TestPostServiceUpdateEnforcesOwnership(t *testing.T) {
 	}
 }
 
+func TestPostServiceFollowsRolePermissions(t *testing.T) {
+	authz := defaultPolicy()
+	repo := &fakePostRepo{post: models.Post{ID: "p1", AuthorID: "owner", Title: "Old", Content: "Text", Status: models.PostStatusPublished}}
+	svc := newTestPostService(repo, testPostDeps{policy: authz})
+	ctx := context.Background()
+
+	title := "Edited"
+	if _, err := svc.Update(ctx, UpdatePostInput{PostID: "p1", ActorID: "u2", ActorRole: "editor", Title: &title}); err != nil {
+		t.Fatalf("expected an editor to edit someone else's post: %v", err)
+	}
+
+	draft := CreatePostInput{ActorID: "u1", ActorRole: "contributor", Title: "Hello", Content: "World", Status: "draft"}
+	if _, err := svc.Create(ctx, draft); err != nil {
+		t.Fatalf("expected a contributor to write drafts: %v", err)
+	}
+	published := draft
+	published.Status = "published"
+	if _, err := svc.Create(ctx, published); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected a contributor not to publish, got %v", err)
+	}
+
+	grants := policy.DefaultGrants()
+	grants["contributor"] = append(grants["contributor"], policy.PostPublish)
+	authz.Replace(grants)
+	if _, err := svc.Create(ctx, published); err != nil {
+		t.Fatalf("expected a granted permission to apply without a restart: %v", err)
+	}
+}
+
 func TestPostServiceListAppliesPaginationDefaults(t *testing.T) {
 	repo := &fakePostRepo{listPosts: []models.Post{}, listTotal: 120}
-	svc := NewPostService(repo)
+	svc := newTestPostService(repo, testPostDeps{})
 
 	_, meta, err := svc.List(context.Background(), ListPostsInput{Page: 0, Limit: 500})
 	if err != nil {
@@ -121,3 +295,432 @@ func TestPostServiceListAppliesPaginationDefaults(t *testing.T) {
 		t.Fatalf("unexpected pagination metadata: %+v", meta)
 	}
 }
+
+func TestPostServiceListOnlyReturnsPublishedByDefault(t *testing.T) {
+	repo := &fakePostRepo{listPosts: []models.Post{}}
+	svc := newTestPostService(repo, testPostDeps{})
+
+	if _, _, err := svc.List(context.Background(), ListPostsInput{ViewerID: "u1", ViewerRole: "author"}); err != nil {
+		t.Fatalf("expected list to succeed: %v", err)
+	}
+
+	statuses := repo.lastFilter.Statuses
+	if len(statuses) != 1 || statuses[0] != models.PostStatusPublished {
+		t.Fatalf("expected published-only filter, got %+v", repo.lastFilter)
+	}
+}
+
+func TestPostServiceListDraftFilterRequiresAdmin(t *testing.T) {
+	repo := &fakePostRepo{listPosts: []models.Post{}}
+	svc := newTestPostService(repo, testPostDeps{})
+
+	_, _, err := svc.List(context.Background(), ListPostsInput{Status: "draft", ViewerID: "u1", ViewerRole: "author"})
+	if !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for non-admin draft filter, got %v", err)
+	}
+
+	if _, _, err := svc.List(context.Background(), ListPostsInput{Status: "draft", ViewerID: "a1", ViewerRole: "admin"}); err != nil {
+		t.Fatalf("expected admin draft listing to succeed: %v", err)
+	}
+	if repo.lastFilter.Statuses[0] != models.PostStatusDraft {
+		t.Fatalf("expected draft filter for admin, got %+v", repo.lastFilter)
+	}
+}
+
+func TestPostServiceListMineScopesToActor(t *testing.T) {
+	repo := &fakePostRepo{listPosts: []models.Post{}}
+	svc := newTestPostService(repo, testPostDeps{})
+
+	if _, _, err := svc.ListMine(context.Background(), ListMyPostsInput{ActorID: "u1", Status: "draft"}); err != nil {
+		t.Fatalf("expected list mine to succeed: %v", err)
+	}
+	if repo.lastFilter.AuthorID != "u1" || len(repo.lastFilter.Statuses) != 1 || repo.lastFilter.Statuses[0] != models.PostStatusDraft {
+		t.Fatalf("unexpected filter: %+v", repo.lastFilter)
+	}
+
+	_, _, err := svc.ListMine(context.Background(), ListMyPostsInput{ActorID: "u1", Status: "archived"})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for unknown status, got %v", err)
+	}
+}
+
+func TestPostServiceGetByIDHidesDraftsFromOthers(t *testing.T) {
+	repo := &fakePostRepo{post: models.Post{ID: "p1", AuthorID: "owner", Title: "T", Content: "C", Status: models.PostStatusDraft}}
+	svc := newTestPostService(repo, testPostDeps{})
+
+	if _, err := svc.GetByID(context.Background(), GetPostInput{PostID: "p1"}); !errors.Is(err, ErrPostNotFound) {
+		t.Fatalf("expected ErrPostNotFound for anonymous viewer, got %v", err)
+	}
+	if _, err := svc.GetByID(context.Background(), GetPostInput{PostID: "p1", ViewerID: "other", ViewerRole: "author"}); !errors.Is(err, ErrPostNotFound) {
+		t.Fatalf("expected ErrPostNotFound for other author, got %v", err)
+	}
+	if _, err := svc.GetByID(context.Background(), GetPostInput{PostID: "p1", ViewerID: "owner", ViewerRole: "author"}); err != nil {
+		t.Fatalf("expected owner to see draft: %v", err)
+	}
+	if _, err := svc.GetByID(context.Background(), GetPostInput{PostID: "p1", ViewerID: "a1", ViewerRole: "admin"}); err != nil {
+		t.Fatalf("expected admin to see draft: %v", err)
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	cases := map[string]string{
+		"Hello, World!":          "hello-world",
+		"  Go 1.21 -- released ": "go-1-21-released",
+		"Crème Brûlée Recipes":   "creme-brulee-recipes",
+		"!!!":                    "",
+	}
+	for title, want := range cases {
+		if got := slugify(title); got != want {
+			t.Errorf("slugify(%q) = %q, want %q", title, got, want)
+		}
+	}
+
+	if got := slugify(strings.Repeat("word ", 40)); len(got) > maxSlugLength || strings.HasSuffix(got, "-") {
+		t.Errorf("expected truncated slug without trailing hyphen, got %q", got)
+	}
+}
+
+func TestPostServiceCreateSuffixesCollidingSlugs(t *testing.T) {
+	repo := &fakePostRepo{takenSlugs: map[string]bool{"hello-world": true, "hello-world-2": true}}
+	svc := newTestPostService(repo, testPostDeps{})
+
+	post, err := svc.Create(context.Background(), CreatePostInput{
+		ActorID:   "u1",
+		ActorRole: "author",
+		Title:     "Hello World",
+		Content:   "Body",
+		Status:    "draft",
+	})
+	if err != nil {
+		t.Fatalf("expected create to succeed: %v", err)
+	}
+	if post.Slug != "hello-world-3" {
+		t.Fatalf("expected deterministic suffix hello-world-3, got %q", post.Slug)
+	}
+}
+
+func TestPostServiceUpdateTitleKeepsOldSlugResolvable(t *testing.T) {
+	repo := &fakePostRepo{post: models.Post{ID: "p1", AuthorID: "owner", Title: "Old Title", Slug: "old-title", Content: "Text", Status: models.PostStatusPublished}}
+	svc := newTestPostService(repo, testPostDeps{})
+
+	title := "New Title"
+	updated, err := svc.Update(context.Background(), UpdatePostInput{PostID: "p1", ActorID: "owner", ActorRole: "author", Title: &title})
+	if err != nil {
+		t.Fatalf("expected update to succeed: %v", err)
+	}
+	if updated.Slug != "new-title" {
+		t.Fatalf("expected slug new-title, got %q", updated.Slug)
+	}
+
+	resolved, err := svc.GetBySlug(context.Background(), GetPostBySlugInput{Slug: "old-title"})
+	if err != nil {
+		t.Fatalf("expected old slug to resolve: %v", err)
+	}
+	if resolved.ID != "p1" || resolved.Slug != "new-title" {
+		t.Fatalf("expected canonical slug for old slug lookup, got %+v", resolved)
+	}
+}
+
+func TestPostServiceUpdateTitleKeepsSuffixedSlug(t *testing.T) {
+	repo := &fakePostRepo{post: models.Post{ID: "p1", AuthorID: "owner", Title: "Hello", Slug: "hello-2", Content: "Text", Status: models.PostStatusPublished}}
+	svc := newTestPostService(repo, testPostDeps{})
+
+	title := "hello!"
+	updated, err := svc.Update(context.Background(), UpdatePostInput{PostID: "p1", ActorID: "owner", ActorRole: "author", Title: &title})
+	if err != nil {
+		t.Fatalf("expected update to succeed: %v", err)
+	}
+	if updated.Slug != "hello-2" || len(repo.history) != 0 {
+		t.Fatalf("expected slug to stay hello-2 without history, got %q (%v)", updated.Slug, repo.history)
+	}
+}
+
+func TestPostServiceCreateAttachesNormalizedTags(t *testing.T) {
+	repo := &fakePostRepo{}
+	taxonomy := &fakeTaxonomyRepo{categories: []models.Category{{ID: "c1", Name: "Backend", Slug: "backend"}}}
+	svc := newTestPostService(repo, testPostDeps{taxonomy: taxonomy})
+
+	_, err := svc.Create(context.Background(), CreatePostInput{
+		ActorID:   "u1",
+		ActorRole: "author",
+		Title:     "Tagged",
+		Content:   "Body",
+		Status:    "draft",
+		Tags:      []string{" Go ", "go", "Web  Dev"},
+		Category:  "Backend",
+	})
+	if err != nil {
+		t.Fatalf("expected create to succeed: %v", err)
+	}
+
+	if got := taxonomy.postTags["post-1"]; len(got) != 2 {
+		t.Fatalf("expected two de-duplicated tags, got %v", got)
+	}
+	if repo.post.CategoryID == nil || *repo.post.CategoryID != "c1" {
+		t.Fatalf("expected category c1 to be set, got %v", repo.post.CategoryID)
+	}
+}
+
+func TestPostServiceCreateRejectsUnknownCategory(t *testing.T) {
+	svc := newTestPostService(&fakePostRepo{}, testPostDeps{})
+
+	_, err := svc.Create(context.Background(), CreatePostInput{
+		ActorID:   "u1",
+		ActorRole: "author",
+		Title:     "Hello",
+		Content:   "World",
+		Category:  "missing",
+	})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation, got %v", err)
+	}
+}
+
+func TestPostServiceListPassesTaxonomyFilters(t *testing.T) {
+	repo := &fakePostRepo{listPosts: []models.Post{}}
+	svc := newTestPostService(repo, testPostDeps{})
+
+	if _, _, err := svc.List(context.Background(), ListPostsInput{Tag: "Go", Category: "backend"}); err != nil {
+		t.Fatalf("expected list to succeed: %v", err)
+	}
+	if repo.lastFilter.TagSlug != "go" || repo.lastFilter.CategorySlug != "backend" {
+		t.Fatalf("unexpected taxonomy filter: %+v", repo.lastFilter)
+	}
+}
+
+func TestPostServiceListRejectsFiltersWithoutSlug(t *testing.T) {
+	svc := newTestPostService(&fakePostRepo{listPosts: []models.Post{}}, testPostDeps{})
+
+	for _, input := range []ListPostsInput{{Tag: "!!!"}, {Category: " -- "}} {
+		if _, _, err := svc.List(context.Background(), input); !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected ErrValidation for %+v, got %v", input, err)
+		}
+	}
+}
+
+func TestPostServiceSearchRanksAndEscapesSnippets(t *testing.T) {
+	repo := &fakePostRepo{listPosts: []models.Post{
+		{ID: "p1", Title: "Cooking notes", Content: "A <script>golang</script> aside", Status: models.PostStatusPublished},
+		{ID: "p2", Title: "Golang generics", Content: "Generics in golang arrived in 1.18", Status: models.PostStatusPublished},
+	}}
+	svc := newTestPostService(repo, testPostDeps{})
+
+	results, meta, err := svc.Search(context.Background(), SearchPostsInput{Query: "golang", Page: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("expected search to succeed: %v", err)
+	}
+	if meta.Total != 2 || len(results) != 2 {
+		t.Fatalf("expected two results, got %d (meta %+v)", len(results), meta)
+	}
+	if results[0].ID != "p2" {
+		t.Fatalf("expected title match to rank first, got %s", results[0].ID)
+	}
+	if !strings.Contains(results[1].Snippet, "&lt;script&gt;<mark>golang</mark>") {
+		t.Fatalf("expected escaped snippet with highlight, got %q", results[1].Snippet)
+	}
+}
+
+func TestPostServiceSearchHidesUnpublishedPosts(t *testing.T) {
+	publishAt := time.Now().Add(time.Hour)
+	repo := &fakePostRepo{listPosts: []models.Post{
+		{ID: "live", AuthorID: "u1", Title: "Golang tips", Content: "Body", Status: models.PostStatusPublished},
+		{ID: "draft", AuthorID: "u2", Title: "Golang draft", Content: "Body", Status: models.PostStatusDraft},
+		{ID: "scheduled", AuthorID: "u2", Title: "Golang later", Content: "Body", Status: models.PostStatusScheduled, PublishAt: &publishAt},
+		{ID: "trashed", AuthorID: "u2", Title: "Golang trash", Content: "Body", Status: models.PostStatusPublished, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}},
+	}}
+	svc := newTestPostService(repo, testPostDeps{})
+
+	results, meta, err := svc.Search(context.Background(), SearchPostsInput{Query: "golang", Page: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("expected search to succeed: %v", err)
+	}
+	if meta.Total != 1 || len(results) != 1 || results[0].ID != "live" {
+		t.Fatalf("expected only the live published post, got %+v (meta %+v)", results, meta)
+	}
+}
+
+func TestPostServiceSearchRequiresQuery(t *testing.T) {
+	svc := newTestPostService(&fakePostRepo{}, testPostDeps{})
+
+	if _, _, err := svc.Search(context.Background(), SearchPostsInput{Query: "   "}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for empty query, got %v", err)
+	}
+}
+
+func TestNormalizeStatusScheduledRequiresFuturePublishAt(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	cases := []struct {
+		name      string
+		status    string
+		publishAt *time.Time
+		noPublish bool
+		want      models.PostStatus
+		wantErr   bool
+	}{
+		{name: "default published", status: "", want: models.PostStatusPublished},
+		{name: "default draft without post.publish", status: "", noPublish: true, want: models.PostStatusDraft},
+		{name: "publish_at implies scheduled", status: "", publishAt: &future, want: models.PostStatusScheduled},
+		{name: "scheduled in future", status: "Scheduled", publishAt: &future, want: models.PostStatusScheduled},
+		{name: "scheduled without publish_at", status: "scheduled", wantErr: true},
+		{name: "scheduled in past", status: "scheduled", publishAt: &past, wantErr: true},
+		{name: "publish_at on draft", status: "draft", publishAt: &future, wantErr: true},
+		{name: "unknown status", status: "archived", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		got, err := normalizeStatus(tc.status, tc.publishAt, !tc.noPublish)
+		if tc.wantErr {
+			if !errors.Is(err, ErrValidation) {
+				t.Fatalf("%s: expected ErrValidation, got %v", tc.name, err)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Fatalf("%s: expected %q, got %q (%v)", tc.name, tc.want, got, err)
+		}
+	}
+}
+
+func TestPostServiceUpdateReschedulesAndClearsPublishAt(t *testing.T) {
+	first := time.Now().Add(time.Hour).UTC()
+	repo := &fakePostRepo{post: models.Post{ID: "p1", AuthorID: "owner", Title: "T", Slug: "t", Content: "C", Status: models.PostStatusScheduled, PublishAt: &first}}
+	svc := newTestPostService(repo, testPostDeps{})
+
+	later := time.Now().Add(48 * time.Hour)
+	updated, err := svc.Update(context.Background(), UpdatePostInput{PostID: "p1", ActorID: "owner", ActorRole: "author", PublishAt: &later})
+	if err != nil {
+		t.Fatalf("expected reschedule to succeed: %v", err)
+	}
+	if updated.Status != models.PostStatusScheduled || updated.PublishAt == nil || !updated.PublishAt.Equal(later) {
+		t.Fatalf("expected post rescheduled, got %+v", updated)
+	}
+
+	draft := "draft"
+	updated, err = svc.Update(context.Background(), UpdatePostInput{PostID: "p1", ActorID: "owner", ActorRole: "author", Status: &draft})
+	if err != nil {
+		t.Fatalf("expected unschedule to succeed: %v", err)
+	}
+	if updated.Status != models.PostStatusDraft || updated.PublishAt != nil {
+		t.Fatalf("expected publish_at cleared for draft, got %+v", updated)
+	}
+
+	_, err = svc.Update(context.Background(), UpdatePostInput{PostID: "p1", ActorID: "owner", ActorRole: "author", PublishAt: &later})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected publish_at on a draft to be rejected, got %v", err)
+	}
+}
+
+func TestPostServiceDeleteMovesPostToTrashAndRestores(t *testing.T) {
+	repo := &fakePostRepo{post: models.Post{ID: "p1", AuthorID: "owner", Title: "T", Slug: "t", Content: "C", Status: models.PostStatusPublished}}
+	svc := newTestPostService(repo, testPostDeps{})
+	ctx := context.Background()
+
+	if err := svc.Delete(ctx, DeletePostInput{PostID: "p1", ActorID: "owner", ActorRole: "author"}); err != nil {
+		t.Fatalf("expected delete to succeed: %v", err)
+	}
+	if _, err := svc.GetByID(ctx, GetPostInput{PostID: "p1", ViewerID: "owner", ViewerRole: "author"}); !errors.Is(err, ErrPostNotFound) {
+		t.Fatalf("expected trashed post to be hidden, got %v", err)
+	}
+
+	_, err := svc.Restore(ctx, RestorePostInput{PostID: "p1", ActorID: "someone-else", ActorRole: "author"})
+	if !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected non-owner restore to be forbidden, got %v", err)
+	}
+
+	restored, err := svc.Restore(ctx, RestorePostInput{PostID: "p1", ActorID: "owner", ActorRole: "author"})
+	if err != nil {
+		t.Fatalf("expected restore to succeed: %v", err)
+	}
+	if restored.ID != "p1" || restored.Slug != "t" {
+		t.Fatalf("unexpected restored post: %+v", restored)
+	}
+}
+
+func TestPostServicePurgeIsAdminOnlyAndRequiresTrash(t *testing.T) {
+	repo := &fakePostRepo{post: models.Post{ID: "p1", AuthorID: "owner", Title: "T", Content: "C", Status: models.PostStatusPublished}}
+	events := &fakeAuditRepo{}
+	svc := newTestPostService(repo, testPostDeps{audit: NewAuditService(events, defaultPolicy())})
+	ctx := context.Background()
+
+	if err := svc.Purge(ctx, PurgePostInput{PostID: "p1", ActorRole: "admin"}); !errors.Is(err, ErrPostNotFound) {
+		t.Fatalf("expected purge of a live post to report not found, got %v", err)
+	}
+
+	if err := svc.Delete(ctx, DeletePostInput{PostID: "p1", ActorID: "owner", ActorRole: "author"}); err != nil {
+		t.Fatalf("expected delete to succeed: %v", err)
+	}
+	if err := svc.Purge(ctx, PurgePostInput{PostID: "p1", ActorRole: "author"}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected author purge to be forbidden, got %v", err)
+	}
+	if err := svc.Purge(ctx, PurgePostInput{PostID: "p1", ActorRole: "admin"}); err != nil {
+		t.Fatalf("expected admin purge to succeed: %v", err)
+	}
+	if repo.trashed != nil {
+		t.Fatalf("expected post removed from trash")
+	}
+	purge := events.events[len(events.events)-1]
+	if purge.Action != AuditPostPurge || !strings.Contains(string(purge.Before), `"title":"T"`) {
+		t.Fatalf("expected the purge to record what was purged, got %s with before %s", purge.Action, purge.Before)
+	}
+}
+
+func TestPostServiceListTrashFiltersOwnTrashedPosts(t *testing.T) {
+	repo := &fakePostRepo{listPosts: []models.Post{}}
+	svc := newTestPostService(repo, testPostDeps{})
+
+	if _, _, err := svc.ListTrash(context.Background(), ListTrashInput{ActorID: "u1"}); err != nil {
+		t.Fatalf("expected list trash to succeed: %v", err)
+	}
+	if !repo.lastFilter.Trashed || repo.lastFilter.AuthorID != "u1" {
+		t.Fatalf("unexpected trash filter: %+v", repo.lastFilter)
+	}
+}
+
+func TestPostServiceStoresRenderedHTMLAndServesFormats(t *testing.T) {
+	repo := &fakePostRepo{}
+	svc := newTestPostService(repo, testPostDeps{})
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, CreatePostInput{ActorID: "u1", ActorRole: "editor", Title: "Hello", Content: "**bold** <script>alert(1)</script>"})
+	if err != nil {
+		t.Fatalf("expected create to succeed: %v", err)
+	}
+	if repo.post.ContentHTML != "<p><strong>bold</strong> </p>\n" {
+		t.Fatalf("unexpected cached html: %q", repo.post.ContentHTML)
+	}
+	if created.Format != ContentFormatMarkdown || created.Content != "**bold** <script>alert(1)</script>" {
+		t.Fatalf("expected markdown by default, got %+v", created)
+	}
+
+	content := "_new_"
+	if _, err := svc.Update(ctx, UpdatePostInput{PostID: created.ID, ActorID: "u1", ActorRole: "author", Content: &content}); err != nil {
+		t.Fatalf("expected update to succeed: %v", err)
+	}
+
+	item, err := svc.GetByID(ctx, GetPostInput{PostID: created.ID, Format: "HTML"})
+	if err != nil {
+		t.Fatalf("expected html read to succeed: %v", err)
+	}
+	if item.Format != ContentFormatHTML || item.Content != "<p><em>new</em></p>\n" {
+		t.Fatalf("expected re-rendered html, got %+v", item)
+	}
+
+	if _, err := svc.GetByID(ctx, GetPostInput{PostID: created.ID, Format: "pdf"}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected unknown format to be rejected, got %v", err)
+	}
+}
+
+func TestPostServiceRendersLegacyPostsWithoutCachedHTML(t *testing.T) {
+	repo := &fakePostRepo{post: models.Post{ID: "p1", AuthorID: "u1", Title: "Old", Content: "# Title", Status: models.PostStatusPublished}}
+	svc := newTestPostService(repo, testPostDeps{})
+
+	item, err := svc.GetByID(context.Background(), GetPostInput{PostID: "p1", Format: "html"})
+	if err != nil {
+		t.Fatalf("expected html read to succeed: %v", err)
+	}
+	if item.Content != "<h1>Title</h1>\n" {
+		t.Fatalf("expected on-the-fly rendering, got %q", item.Content)
+	}
+}
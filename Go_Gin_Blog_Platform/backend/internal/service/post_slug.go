@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	maxSlugLength   = 80
+	maxSlugAttempts = 100
+	fallbackSlug    = "post"
+)
+
+func slugify(title string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	for _, r := range norm.NFKD.String(strings.ToLower(title)) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteRune(r)
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks left over from decomposing accented letters.
+		default:
+			pendingHyphen = true
+		}
+		if b.Len() >= maxSlugLength {
+			break
+		}
+	}
+
+	slug := strings.Trim(b.String(), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	return slug
+}
+
+func postSlugBase(title string) string {
+	if slug := slugify(title); slug != "" {
+		return slug
+	}
+	return fallbackSlug
+}
+
+// uniqueSlug returns the first of base, base-2, base-3, ... that is not used by
+// another post, so the same title always resolves to the same candidate order.
+func (s *PostService) uniqueSlug(ctx context.Context, base, postID string) (string, error) {
+	for attempt := 1; attempt <= maxSlugAttempts; attempt++ {
+		candidate := base
+		if attempt > 1 {
+			candidate = base + "-" + strconv.Itoa(attempt)
+		}
+
+		taken, err := s.repo.SlugTaken(ctx, candidate, postID)
+		if err != nil {
+			return "", fmt.Errorf("check slug availability: %w", err)
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no free slug for %q after %d attempts", base, maxSlugAttempts)
+}
+
+// slugMatchesBase reports whether slug is base or one of its numeric suffixes,
+// in which case a title edit does not need a new slug.
+func slugMatchesBase(slug, base string) bool {
+	if slug == base {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(slug, base+"-")
+	if !ok {
+		return false
+	}
+	n, err := strconv.Atoi(suffix)
+	return err == nil && n > 1
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/policy"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+var (
+	ErrRoleNotFound  = errors.New("role not found")
+	ErrRoleExists    = errors.New("role already exists")
+	ErrRoleInUse     = errors.New("role is assigned to users")
+	ErrRoleImmutable = errors.New("role cannot be changed")
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,31}$`)
+
+type RoleItem struct {
+	Name        string              `json:"name"`
+	BuiltIn     bool                `json:"built_in"`
+	Permissions []policy.Permission `json:"permissions"`
+}
+
+// RoleService edits the role to permission mapping and keeps the in-memory
+// policy in step with it.
+type RoleService struct {
+	roles  repository.RoleRepository
+	audit  *AuditService
+	policy *policy.Policy
+	tx     repository.Transactor
+}
+
+func NewRoleService(roles repository.RoleRepository, audit *AuditService, authz *policy.Policy, tx repository.Transactor) *RoleService {
+	return &RoleService{roles: roles, audit: audit, policy: authz, tx: tx}
+}
+
+// Reload replaces the policy's grants with the stored ones. Instances call it
+// periodically to pick up edits made elsewhere.
+func (s *RoleService) Reload(ctx context.Context) error {
+	roles, err := s.roles.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	grants := make(policy.Grants, len(roles))
+	for _, role := range roles {
+		permissions := make([]policy.Permission, 0, len(role.Permissions))
+		for _, row := range role.Permissions {
+			permissions = append(permissions, policy.Permission(row.Permission))
+		}
+		grants[role.Name] = permissions
+	}
+	s.policy.Replace(grants)
+	return nil
+}
+
+func (s *RoleService) List(ctx context.Context) ([]RoleItem, error) {
+	roles, err := s.roles.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list roles: %w", err)
+	}
+
+	items := make([]RoleItem, 0, len(roles))
+	for i := range roles {
+		items = append(items, roleItem(&roles[i]))
+	}
+	return items, nil
+}
+
+// Create adds a custom role. actorRole must hold every permission granted, so
+// managing roles never hands out more than the actor already has.
+func (s *RoleService) Create(ctx context.Context, actorID, actorRole, name string, permissions []string) (RoleItem, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !roleNamePattern.MatchString(name) {
+		return RoleItem{}, fmt.Errorf("role name must be 2-32 lowercase letters, digits, '-' or '_', starting with a letter: %w", ErrValidation)
+	}
+	granted, err := normalizePermissions(permissions)
+	if err != nil {
+		return RoleItem{}, err
+	}
+	if !s.canGrant(actorRole, granted) {
+		return RoleItem{}, ErrForbidden
+	}
+
+	role := &models.RoleDefinition{Name: name}
+	for _, permission := range granted {
+		role.Permissions = append(role.Permissions, models.RolePermission{Role: name, Permission: permission})
+	}
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.roles.Create(ctx, role); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditRecord{
+			ActorID:    actorID,
+			Action:     AuditRoleCreate,
+			TargetType: AuditTargetRole,
+			TargetID:   name,
+			After:      map[string]any{"permissions": granted},
+		})
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return RoleItem{}, ErrRoleExists
+		}
+		return RoleItem{}, fmt.Errorf("create role: %w", err)
+	}
+	if err := s.Reload(ctx); err != nil {
+		return RoleItem{}, err
+	}
+	return roleItem(role), nil
+}
+
+// SetPermissions replaces what a role grants. The superuser role always holds
+// every permission and cannot be edited. Like assigning a role, it takes an
+// actorRole that covers the role as it is and holds every permission granted.
+func (s *RoleService) SetPermissions(ctx context.Context, actorID, actorRole, name string, permissions []string) (RoleItem, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == policy.SuperuserRole {
+		return RoleItem{}, ErrRoleImmutable
+	}
+	granted, err := normalizePermissions(permissions)
+	if err != nil {
+		return RoleItem{}, err
+	}
+	if !s.canGrant(actorRole, granted) {
+		return RoleItem{}, ErrForbidden
+	}
+
+	var updated *models.RoleDefinition
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, err := s.roles.Get(ctx, name)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrRoleNotFound
+			}
+			return err
+		}
+		if !s.policy.Covers(actorRole, name) {
+			return ErrForbidden
+		}
+		if err := s.roles.ReplacePermissions(ctx, name, granted); err != nil {
+			return err
+		}
+		if updated, err = s.roles.Get(ctx, name); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditRecord{
+			ActorID:    actorID,
+			Action:     AuditRoleUpdate,
+			TargetType: AuditTargetRole,
+			TargetID:   name,
+			Before:     map[string]any{"permissions": roleItem(current).Permissions},
+			After:      map[string]any{"permissions": granted},
+		})
+	})
+	if err != nil {
+		return RoleItem{}, err
+	}
+	if err := s.Reload(ctx); err != nil {
+		return RoleItem{}, err
+	}
+	return roleItem(updated), nil
+}
+
+// Delete removes a custom role nobody holds.
+func (s *RoleService) Delete(ctx context.Context, actorID, name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	role, err := s.roles.Get(ctx, name)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrRoleNotFound
+		}
+		return err
+	}
+	if role.BuiltIn {
+		return ErrRoleImmutable
+	}
+	users, err := s.roles.CountUsers(ctx, name)
+	if err != nil {
+		return err
+	}
+	if users > 0 {
+		return ErrRoleInUse
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.roles.Delete(ctx, name); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditRecord{
+			ActorID:    actorID,
+			Action:     AuditRoleDelete,
+			TargetType: AuditTargetRole,
+			TargetID:   name,
+			Before:     map[string]any{"permissions": roleItem(role).Permissions},
+		})
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrRoleNotFound
+		}
+		return err
+	}
+	return s.Reload(ctx)
+}
+
+func (s *RoleService) canGrant(actorRole string, permissions []string) bool {
+	held := make([]policy.Permission, 0, len(permissions))
+	for _, permission := range permissions {
+		held = append(held, policy.Permission(permission))
+	}
+	return s.policy.Holds(actorRole, held...)
+}
+
+func normalizePermissions(permissions []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(permissions))
+	normalized := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		permission = strings.ToLower(strings.TrimSpace(permission))
+		if !policy.IsKnown(policy.Permission(permission)) {
+			return nil, fmt.Errorf("unknown permission %q: %w", permission, ErrValidation)
+		}
+		if _, dup := seen[permission]; dup {
+			continue
+		}
+		seen[permission] = struct{}{}
+		normalized = append(normalized, permission)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+func roleItem(role *models.RoleDefinition) RoleItem {
+	item := RoleItem{Name: role.Name, BuiltIn: role.BuiltIn, Permissions: []policy.Permission{}}
+	if role.Name == policy.SuperuserRole {
+		item.Permissions = policy.All()
+		return item
+	}
+	for _, row := range role.Permissions {
+		item.Permissions = append(item.Permissions, policy.Permission(row.Permission))
+	}
+	return item
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/policy"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+type fakeRoleRepo struct {
+	roles map[string]*models.RoleDefinition
+	users map[string]int64
+}
+
+func newFakeRoleRepo() *fakeRoleRepo {
+	repo := &fakeRoleRepo{roles: map[string]*models.RoleDefinition{}, users: map[string]int64{}}
+	for role, permissions := range policy.DefaultGrants() {
+		definition := &models.RoleDefinition{Name: role, BuiltIn: true}
+		for _, permission := range permissions {
+			definition.Permissions = append(definition.Permissions, models.RolePermission{Role: role, Permission: string(permission)})
+		}
+		repo.roles[role] = definition
+	}
+	return repo
+}
+
+func (f *fakeRoleRepo) List(_ context.Context) ([]models.RoleDefinition, error) {
+	roles := make([]models.RoleDefinition, 0, len(f.roles))
+	for _, role := range f.roles {
+		roles = append(roles, *role)
+	}
+	return roles, nil
+}
+
+func (f *fakeRoleRepo) Get(_ context.Context, name string) (*models.RoleDefinition, error) {
+	role, ok := f.roles[name]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copy := *role
+	return &copy, nil
+}
+
+func (f *fakeRoleRepo) Create(_ context.Context, role *models.RoleDefinition) error {
+	if _, ok := f.roles[role.Name]; ok {
+		return repository.ErrDuplicate
+	}
+	copy := *role
+	f.roles[role.Name] = &copy
+	return nil
+}
+
+func (f *fakeRoleRepo) ReplacePermissions(_ context.Context, name string, permissions []string) error {
+	role := f.roles[name]
+	role.Permissions = nil
+	for _, permission := range permissions {
+		role.Permissions = append(role.Permissions, models.RolePermission{Role: name, Permission: permission})
+	}
+	return nil
+}
+
+func (f *fakeRoleRepo) Delete(_ context.Context, name string) error {
+	if _, ok := f.roles[name]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(f.roles, name)
+	return nil
+}
+
+func (f *fakeRoleRepo) CountUsers(_ context.Context, name string) (int64, error) {
+	return f.users[name], nil
+}
+
+func TestRoleServiceEditsApplyToThePolicy(t *testing.T) {
+	repo := newFakeRoleRepo()
+	authz := policy.New(nil)
+	svc := NewRoleService(repo, newTestAudit(), authz, fakeTransactor{})
+	ctx := context.Background()
+
+	if err := svc.Reload(ctx); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if !authz.Authorize(policy.Actor{Role: "editor"}, policy.CommentModerate, nil) {
+		t.Fatalf("expected the stored grants to be loaded")
+	}
+
+	created, err := svc.Create(ctx, "admin-1", "admin", " Moderator ", []string{"comment.moderate", "comment.moderate"})
+	if err != nil {
+		t.Fatalf("create role: %v", err)
+	}
+	if created.Name != "moderator" || len(created.Permissions) != 1 {
+		t.Fatalf("unexpected role %+v", created)
+	}
+	if !authz.Authorize(policy.Actor{Role: "moderator"}, policy.CommentModerate, nil) {
+		t.Fatalf("expected a new role to apply at once")
+	}
+
+	if _, err := svc.SetPermissions(ctx, "admin-1", "admin", "author", []string{"post.create"}); err != nil {
+		t.Fatalf("set permissions: %v", err)
+	}
+	if authz.Authorize(policy.Actor{Role: "author"}, policy.PostPublish, nil) {
+		t.Fatalf("expected a revoked permission to apply at once")
+	}
+}
+
+func TestRoleServiceRejectsUnsafeEdits(t *testing.T) {
+	repo := newFakeRoleRepo()
+	repo.users["moderator"] = 2
+	repo.roles["moderator"] = &models.RoleDefinition{Name: "moderator"}
+	svc := NewRoleService(repo, newTestAudit(), policy.New(nil), fakeTransactor{})
+	ctx := context.Background()
+
+	cases := map[string]struct {
+		err  error
+		want error
+	}{
+		"unknown permission":  {err: func() error { _, err := svc.Create(ctx, "admin-1", "admin", "ops", []string{"post.fly"}); return err }(), want: ErrValidation},
+		"bad name":            {err: func() error { _, err := svc.Create(ctx, "admin-1", "admin", "Ops Team", nil); return err }(), want: ErrValidation},
+		"duplicate":           {err: func() error { _, err := svc.Create(ctx, "admin-1", "admin", "editor", nil); return err }(), want: ErrRoleExists},
+		"edit superuser":      {err: func() error { _, err := svc.SetPermissions(ctx, "admin-1", "admin", "admin", nil); return err }(), want: ErrRoleImmutable},
+		"edit missing":        {err: func() error { _, err := svc.SetPermissions(ctx, "admin-1", "admin", "ghost", nil); return err }(), want: ErrRoleNotFound},
+		"delete built-in":     {err: svc.Delete(ctx, "admin-1", "reader"), want: ErrRoleImmutable},
+		"delete role in use":  {err: svc.Delete(ctx, "admin-1", "moderator"), want: ErrRoleInUse},
+		"delete missing role": {err: svc.Delete(ctx, "admin-1", "ghost"), want: ErrRoleNotFound},
+	}
+	for name, tc := range cases {
+		if !errors.Is(tc.err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", name, tc.want, tc.err)
+		}
+	}
+}
+
+func TestRoleServiceOnlyGrantsPermissionsTheActorHolds(t *testing.T) {
+	repo := newFakeRoleRepo()
+	authz := policy.New(nil)
+	svc := NewRoleService(repo, newTestAudit(), authz, fakeTransactor{})
+	ctx := context.Background()
+	if err := svc.Reload(ctx); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if _, err := svc.Create(ctx, "admin-1", "admin", "roles", []string{"role.manage", "comment.moderate"}); err != nil {
+		t.Fatalf("create role: %v", err)
+	}
+
+	if _, err := svc.SetPermissions(ctx, "lead-1", "roles", "roles", []string{"role.manage", "user.role.assign"}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected granting user.role.assign to be forbidden, got %v", err)
+	}
+	if _, err := svc.Create(ctx, "lead-1", "roles", "helper", []string{"user.role.assign"}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected creating a role with user.role.assign to be forbidden, got %v", err)
+	}
+	if _, err := svc.SetPermissions(ctx, "lead-1", "roles", "editor", nil); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected editing a role the actor does not cover to be forbidden, got %v", err)
+	}
+	if authz.Authorize(policy.Actor{Role: "roles"}, policy.UserRoleAssign, nil) {
+		t.Fatalf("expected the refused grant not to apply")
+	}
+
+	if _, err := svc.Create(ctx, "lead-1", "roles", "helper", []string{"comment.moderate"}); err != nil {
+		t.Fatalf("expected permissions the actor holds to be grantable: %v", err)
+	}
+}
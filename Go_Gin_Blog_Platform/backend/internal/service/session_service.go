@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+// SessionItem is one signed-in device: a refresh token family and the client
+// that last refreshed it.
+type SessionItem struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+type SessionService struct {
+	users         repository.UserRepository
+	refreshTokens repository.RefreshTokenRepository
+	pats          repository.PersonalAccessTokenRepository
+	audit         *AuditService
+	tx            repository.Transactor
+	now           func() time.Time
+}
+
+func NewSessionService(
+	users repository.UserRepository,
+	refreshTokens repository.RefreshTokenRepository,
+	pats repository.PersonalAccessTokenRepository,
+	audit *AuditService,
+	tx repository.Transactor,
+) *SessionService {
+	return &SessionService{
+		users:         users,
+		refreshTokens: refreshTokens,
+		pats:          pats,
+		audit:         audit,
+		tx:            tx,
+		now:           func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// List returns the user's active sessions, flagging currentSessionID (the
+// caller's own session, if any) as current.
+func (s *SessionService) List(ctx context.Context, userID, currentSessionID string) ([]SessionItem, error) {
+	if err := s.ensureUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.refreshTokens.ListActiveByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+
+	items := make([]SessionItem, 0, len(tokens))
+	for _, token := range tokens {
+		items = append(items, SessionItem{
+			ID:         token.FamilyID,
+			UserAgent:  token.UserAgent,
+			IPAddress:  token.IPAddress,
+			LastUsedAt: token.LastUsedAt,
+			ExpiresAt:  token.ExpiresAt,
+			Current:    currentSessionID != "" && token.FamilyID == currentSessionID,
+		})
+	}
+	return items, nil
+}
+
+// Revoke signs the user out of one session on behalf of actorID, the user
+// themselves or an admin. Access tokens already issued for it stay valid until
+// they expire.
+func (s *SessionService) Revoke(ctx context.Context, actorID, userID, sessionID string) error {
+	if strings.TrimSpace(sessionID) == "" {
+		return fmt.Errorf("session id is required: %w", ErrValidation)
+	}
+	if err := s.ensureUser(ctx, userID); err != nil {
+		return err
+	}
+
+	tokens, err := s.refreshTokens.ListActiveByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("list sessions: %w", err)
+	}
+	found := false
+	for _, token := range tokens {
+		if token.FamilyID == sessionID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ErrSessionNotFound
+	}
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.refreshTokens.RevokeFamily(ctx, sessionID); err != nil {
+			return fmt.Errorf("revoke session: %w", err)
+		}
+		return s.audit.Record(ctx, AuditRecord{
+			ActorID:    actorID,
+			Action:     AuditSessionRevoke,
+			TargetType: AuditTargetSession,
+			TargetID:   sessionID,
+			Before:     map[string]string{"user_id": userID},
+		})
+	})
+}
+
+// RevokeAll signs the user out everywhere, including the calling session, and
+// revokes their personal access tokens, which would otherwise outlive it.
+// actorID is the user themselves or the admin forcing the logout.
+func (s *SessionService) RevokeAll(ctx context.Context, actorID, userID string) error {
+	if err := s.ensureUser(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		refreshTokens, err := s.refreshTokens.RevokeAllForUser(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("revoke all sessions: %w", err)
+		}
+		accessTokens, err := s.pats.RevokeAllForUser(ctx, userID, s.now())
+		if err != nil {
+			return fmt.Errorf("revoke personal access tokens: %w", err)
+		}
+		return s.audit.Record(ctx, AuditRecord{
+			ActorID:    actorID,
+			Action:     AuditSessionRevokeAll,
+			TargetType: AuditTargetUser,
+			TargetID:   userID,
+			After:      map[string]int64{"refresh_tokens": refreshTokens, "personal_access_tokens": accessTokens},
+		})
+	})
+}
+
+func (s *SessionService) ensureUser(ctx context.Context, userID string) error {
+	if strings.TrimSpace(userID) == "" {
+		return fmt.Errorf("user id is required: %w", ErrValidation)
+	}
+	if _, err := s.users.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("get user: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSessionServiceListsAndRevokesSessions(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+
+	_, laptop, err := f.svc.Login(ctx, LoginInput{Email: "alice@example.com", Password: "correct-horse", IPAddress: "198.51.100.7", UserAgent: "Firefox"})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if _, _, err := f.svc.Login(ctx, LoginInput{Email: "alice@example.com", Password: "correct-horse", IPAddress: "203.0.113.9", UserAgent: "Safari"}); err != nil {
+		t.Fatalf("second login: %v", err)
+	}
+	if _, err := f.svc.Refresh(ctx, RefreshInput{RefreshToken: laptop.RefreshToken, IPAddress: "198.51.100.8", UserAgent: "Firefox"}); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	sessions := NewSessionService(f.users, f.refresh, f.pats, newTestAudit(), fakeTransactor{})
+	laptopSession := f.refresh.tokens[0].FamilyID
+
+	items, err := sessions.List(ctx, "u1", laptopSession)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected one session per login, got %+v", items)
+	}
+	if items[0].ID != laptopSession || !items[0].Current || items[0].IPAddress != "198.51.100.8" {
+		t.Fatalf("expected the refreshed laptop session first and current, got %+v", items[0])
+	}
+	if items[1].UserAgent != "Safari" || items[1].Current {
+		t.Fatalf("unexpected second session: %+v", items[1])
+	}
+
+	if err := sessions.Revoke(ctx, "u1", "u1", "unknown"); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("expected ErrSessionNotFound, got %v", err)
+	}
+	if err := sessions.Revoke(ctx, "u1", "u1", laptopSession); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	items, _ = sessions.List(ctx, "u1", "")
+	if len(items) != 1 || items[0].UserAgent != "Safari" {
+		t.Fatalf("expected only the other session to remain, got %+v", items)
+	}
+
+	pats := NewPersonalAccessTokenService(f.users, f.pats, newTestAudit(), fakeTransactor{}, defaultPolicy())
+	pat, err := pats.Create(ctx, CreatePersonalAccessTokenInput{UserID: "u1", Name: "ci", Scopes: []string{ScopePostsRead}})
+	if err != nil {
+		t.Fatalf("create personal access token: %v", err)
+	}
+	if err := sessions.RevokeAll(ctx, "u1", "u1"); err != nil {
+		t.Fatalf("revoke all: %v", err)
+	}
+	items, _ = sessions.List(ctx, "u1", "")
+	if len(items) != 0 {
+		t.Fatalf("expected no sessions after revoke-all, got %+v", items)
+	}
+	if _, err := pats.Authenticate(ctx, pat.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected revoke-all to revoke personal access tokens, got %v", err)
+	}
+}
+
+func TestSessionServiceUnknownUser(t *testing.T) {
+	f := newAuthFixture(t)
+	sessions := NewSessionService(f.users, f.refresh, f.pats, newTestAudit(), fakeTransactor{})
+
+	if _, err := sessions.List(context.Background(), "missing", ""); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestSessionServiceRecordsAdminRevocations(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	events := &fakeAuditRepo{}
+	sessions := NewSessionService(f.users, f.refresh, f.pats, NewAuditService(events, defaultPolicy()), fakeTransactor{})
+
+	if _, _, err := f.svc.Login(ctx, LoginInput{Email: "alice@example.com", Password: "correct-horse", UserAgent: "Firefox"}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if _, _, err := f.svc.Login(ctx, LoginInput{Email: "alice@example.com", Password: "correct-horse", UserAgent: "Safari"}); err != nil {
+		t.Fatalf("second login: %v", err)
+	}
+	session := f.refresh.tokens[0].FamilyID
+
+	if err := sessions.Revoke(ctx, "admin-1", "u1", "unknown"); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("expected ErrSessionNotFound, got %v", err)
+	}
+	if len(events.events) != 0 {
+		t.Fatalf("expected a failed revocation not to be recorded, got %+v", events.events)
+	}
+
+	if err := sessions.Revoke(ctx, "admin-1", "u1", session); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if err := sessions.RevokeAll(ctx, "admin-1", "u1"); err != nil {
+		t.Fatalf("revoke all: %v", err)
+	}
+	if len(events.events) != 2 {
+		t.Fatalf("expected two audit events, got %+v", events.events)
+	}
+	revoke, revokeAll := events.events[0], events.events[1]
+	if revoke.Action != AuditSessionRevoke || *revoke.ActorID != "admin-1" || revoke.TargetType != AuditTargetSession || revoke.TargetID != session {
+		t.Fatalf("unexpected session revoke event %+v", revoke)
+	}
+	if revokeAll.Action != AuditSessionRevokeAll || *revokeAll.ActorID != "admin-1" || revokeAll.TargetType != AuditTargetUser || revokeAll.TargetID != "u1" {
+		t.Fatalf("unexpected revoke-all event %+v", revokeAll)
+	}
+}
+
+func TestAuthServiceTruncatesUserAgentOnRuneBoundary(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+
+	// One ASCII byte shifts every three-byte rune across the byte limit.
+	userAgent := "x" + strings.Repeat("€", maxUserAgentLength)
+	if _, _, err := f.svc.Login(ctx, LoginInput{Email: "alice@example.com", Password: "correct-horse", UserAgent: userAgent}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	stored := f.refresh.tokens[0].UserAgent
+	if !utf8.ValidString(stored) {
+		t.Fatalf("expected valid UTF-8, got %q", stored)
+	}
+	if len(stored) > maxUserAgentLength || len(stored) < maxUserAgentLength-2 {
+		t.Fatalf("expected the user agent cut just under %d bytes, got %d", maxUserAgentLength, len(stored))
+	}
+}
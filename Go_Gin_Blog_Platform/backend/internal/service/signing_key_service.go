@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+var ErrSigningKeyNotFound = errors.New("signing key not found")
+
+type SigningKeyItem struct {
+	ID        string                  `json:"kid"`
+	Algorithm string                  `json:"alg"`
+	Status    models.SigningKeyStatus `json:"status"`
+	CreatedAt time.Time               `json:"created_at"`
+	RotatedAt *time.Time              `json:"rotated_at,omitempty"`
+	RetiredAt *time.Time              `json:"retired_at,omitempty"`
+}
+
+type RotateKeyInput struct {
+	Algorithm string
+	// RetireAfter is how long a rotated-out key keeps verifying tokens. It
+	// must be at least the access token lifetime.
+	RetireAfter time.Duration
+}
+
+type RotateKeyResult struct {
+	KeyID   string
+	Retired int64
+}
+
+// SigningKeyService manages the asymmetric keys access tokens are signed with.
+type SigningKeyService struct {
+	keys repository.SigningKeyRepository
+	tx   repository.Transactor
+	now  func() time.Time
+}
+
+func NewSigningKeyService(keys repository.SigningKeyRepository, tx repository.Transactor) *SigningKeyService {
+	return &SigningKeyService{
+		keys: keys,
+		tx:   tx,
+		now:  func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Load returns the keys that still verify tokens; it is the auth.KeyLoader
+// for the API's key ring.
+func (s *SigningKeyService) Load(ctx context.Context) ([]auth.SigningKey, error) {
+	stored, err := s.keys.ListUsable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]auth.SigningKey, 0, len(stored))
+	for _, row := range stored {
+		key, err := auth.ParseSigningKey(row.ID, row.Algorithm, row.PrivateKey, row.Status == models.SigningKeyActive)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+	return keys, nil
+}
+
+func (s *SigningKeyService) List(ctx context.Context) ([]SigningKeyItem, error) {
+	stored, err := s.keys.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list signing keys: %w", err)
+	}
+
+	items := make([]SigningKeyItem, 0, len(stored))
+	for _, row := range stored {
+		items = append(items, SigningKeyItem{
+			ID:        row.ID,
+			Algorithm: row.Algorithm,
+			Status:    row.Status,
+			CreatedAt: row.CreatedAt,
+			RotatedAt: row.RotatedAt,
+			RetiredAt: row.RetiredAt,
+		})
+	}
+	return items, nil
+}
+
+// Rotate makes a new key active and keeps the old one for verification.
+// Previous keys rotated out more than RetireAfter ago are retired in the same
+// step.
+func (s *SigningKeyService) Rotate(ctx context.Context, input RotateKeyInput) (RotateKeyResult, error) {
+	if !isAsymmetricAlgorithm(input.Algorithm) || input.RetireAfter < 0 {
+		return RotateKeyResult{}, fmt.Errorf("rotate key input invalid: %w", ErrValidation)
+	}
+
+	key, err := newStoredSigningKey(input.Algorithm)
+	if err != nil {
+		return RotateKeyResult{}, err
+	}
+
+	var retired int64
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		now := s.now()
+		// Retire before demoting so the key being rotated out now is kept.
+		retired, err = s.keys.RetirePreviousBefore(ctx, now.Add(-input.RetireAfter), now)
+		if err != nil {
+			return err
+		}
+		if err := s.keys.DemoteActive(ctx, now); err != nil {
+			return err
+		}
+		return s.keys.Create(ctx, key)
+	})
+	if err != nil {
+		return RotateKeyResult{}, fmt.Errorf("rotate signing key: %w", err)
+	}
+
+	return RotateKeyResult{KeyID: key.ID, Retired: retired}, nil
+}
+
+// EnsureActiveKey creates a first key when there is none, so a new deployment
+// can start in asymmetric mode without a manual rotation. Instances starting
+// together may race; the loser keeps the winner's key.
+func (s *SigningKeyService) EnsureActiveKey(ctx context.Context, algorithm string) error {
+	if !isAsymmetricAlgorithm(algorithm) {
+		return fmt.Errorf("signing algorithm %q: %w", algorithm, ErrValidation)
+	}
+
+	stored, err := s.keys.ListUsable(ctx)
+	if err != nil {
+		return err
+	}
+	for _, row := range stored {
+		if row.Status == models.SigningKeyActive {
+			return nil
+		}
+	}
+
+	key, err := newStoredSigningKey(algorithm)
+	if err != nil {
+		return err
+	}
+	if err := s.keys.Create(ctx, key); err != nil && !errors.Is(err, repository.ErrDuplicate) {
+		return fmt.Errorf("create first signing key: %w", err)
+	}
+	return nil
+}
+
+// Retire stops a previous key from verifying tokens. The active key cannot be
+// retired; rotate first.
+func (s *SigningKeyService) Retire(ctx context.Context, id string) error {
+	if strings.TrimSpace(id) == "" {
+		return fmt.Errorf("key id is required: %w", ErrValidation)
+	}
+	if err := s.keys.Retire(ctx, id, s.now()); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrSigningKeyNotFound
+		}
+		return fmt.Errorf("retire signing key: %w", err)
+	}
+	return nil
+}
+
+func newStoredSigningKey(algorithm string) (*models.SigningKey, error) {
+	key, err := auth.GenerateSigningKey(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := key.EncodePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return &models.SigningKey{
+		ID:         key.ID,
+		Algorithm:  key.Algorithm,
+		PrivateKey: encoded,
+		Status:     models.SigningKeyActive,
+	}, nil
+}
+
+func isAsymmetricAlgorithm(algorithm string) bool {
+	return algorithm == auth.AlgRS256 || algorithm == auth.AlgEdDSA
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+type fakeSigningKeyRepo struct {
+	keys  []models.SigningKey
+	clock *time.Time
+}
+
+func (f *fakeSigningKeyRepo) ListUsable(ctx context.Context) ([]models.SigningKey, error) {
+	all, _ := f.List(ctx)
+	usable := all[:0]
+	for _, key := range all {
+		if key.Status != models.SigningKeyRetired {
+			usable = append(usable, key)
+		}
+	}
+	return usable, nil
+}
+
+func (f *fakeSigningKeyRepo) List(_ context.Context) ([]models.SigningKey, error) {
+	keys := append([]models.SigningKey(nil), f.keys...)
+	sort.SliceStable(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+func (f *fakeSigningKeyRepo) Create(_ context.Context, key *models.SigningKey) error {
+	for _, existing := range f.keys {
+		if existing.Status == models.SigningKeyActive && key.Status == models.SigningKeyActive {
+			return repository.ErrDuplicate
+		}
+	}
+	key.CreatedAt = *f.clock
+	f.keys = append(f.keys, *key)
+	return nil
+}
+
+func (f *fakeSigningKeyRepo) DemoteActive(_ context.Context, at time.Time) error {
+	for i := range f.keys {
+		if f.keys[i].Status == models.SigningKeyActive {
+			f.keys[i].Status = models.SigningKeyPrevious
+			f.keys[i].RotatedAt = &at
+		}
+	}
+	return nil
+}
+
+func (f *fakeSigningKeyRepo) RetirePreviousBefore(_ context.Context, cutoff, at time.Time) (int64, error) {
+	var retired int64
+	for i := range f.keys {
+		if f.keys[i].Status == models.SigningKeyPrevious && f.keys[i].RotatedAt.Before(cutoff) {
+			f.keys[i].Status = models.SigningKeyRetired
+			f.keys[i].RetiredAt = &at
+			retired++
+		}
+	}
+	return retired, nil
+}
+
+func (f *fakeSigningKeyRepo) Retire(_ context.Context, id string, at time.Time) error {
+	for i := range f.keys {
+		if f.keys[i].ID == id && f.keys[i].Status == models.SigningKeyPrevious {
+			f.keys[i].Status = models.SigningKeyRetired
+			f.keys[i].RetiredAt = &at
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func newSigningKeyFixture() (*SigningKeyService, *fakeSigningKeyRepo, *time.Time) {
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := &fakeSigningKeyRepo{clock: &clock}
+	svc := NewSigningKeyService(repo, fakeTransactor{})
+	svc.now = func() time.Time { return clock }
+	return svc, repo, &clock
+}
+
+func TestSigningKeyServiceRotate(t *testing.T) {
+	svc, repo, clock := newSigningKeyFixture()
+	ctx := context.Background()
+
+	if err := svc.EnsureActiveKey(ctx, auth.AlgEdDSA); err != nil {
+		t.Fatalf("ensure active key: %v", err)
+	}
+	if err := svc.EnsureActiveKey(ctx, auth.AlgEdDSA); err != nil || len(repo.keys) != 1 {
+		t.Fatalf("expected a second ensure to keep the existing key, got %d keys (%v)", len(repo.keys), err)
+	}
+	first := repo.keys[0].ID
+
+	*clock = clock.Add(time.Hour)
+	rotated, err := svc.Rotate(ctx, RotateKeyInput{Algorithm: auth.AlgRS256, RetireAfter: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if rotated.KeyID == first || rotated.Retired != 0 {
+		t.Fatalf("unexpected rotation result %+v", rotated)
+	}
+
+	loaded, err := svc.Load(ctx)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(loaded) != 2 || !loaded[0].Active || loaded[0].ID != rotated.KeyID || loaded[0].Algorithm != auth.AlgRS256 || loaded[1].Active {
+		t.Fatalf("expected the new RS256 key active and the old key kept, got %+v", loaded)
+	}
+
+	*clock = clock.Add(25 * time.Hour)
+	rotated, err = svc.Rotate(ctx, RotateKeyInput{Algorithm: auth.AlgRS256, RetireAfter: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("second rotate: %v", err)
+	}
+	if rotated.Retired != 1 {
+		t.Fatalf("expected the first key to be retired, got %+v", rotated)
+	}
+	loaded, _ = svc.Load(ctx)
+	for _, key := range loaded {
+		if key.ID == first {
+			t.Fatalf("expected the retired key to no longer load")
+		}
+	}
+}
+
+func TestSigningKeyServiceRetire(t *testing.T) {
+	svc, repo, _ := newSigningKeyFixture()
+	ctx := context.Background()
+
+	if _, err := svc.Rotate(ctx, RotateKeyInput{Algorithm: "HS256"}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected HS256 to be refused for key rotation, got %v", err)
+	}
+
+	first, _ := svc.Rotate(ctx, RotateKeyInput{Algorithm: auth.AlgEdDSA, RetireAfter: time.Hour})
+	if err := svc.Retire(ctx, first.KeyID); !errors.Is(err, ErrSigningKeyNotFound) {
+		t.Fatalf("expected the active key not to be retirable, got %v", err)
+	}
+
+	if _, err := svc.Rotate(ctx, RotateKeyInput{Algorithm: auth.AlgEdDSA, RetireAfter: time.Hour}); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if err := svc.Retire(ctx, first.KeyID); err != nil {
+		t.Fatalf("retire: %v", err)
+	}
+
+	items, _ := svc.List(ctx)
+	statuses := map[string]models.SigningKeyStatus{}
+	for _, item := range items {
+		statuses[item.ID] = item.Status
+	}
+	if statuses[first.KeyID] != models.SigningKeyRetired || len(repo.keys) != 2 {
+		t.Fatalf("unexpected keys after retire: %+v", items)
+	}
+}
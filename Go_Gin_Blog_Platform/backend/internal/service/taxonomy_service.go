@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+const (
+	maxTagsPerPost = 10
+	maxTagLength   = 40
+)
+
+var (
+	ErrTagNotFound      = errors.New("tag not found")
+	ErrCategoryNotFound = errors.New("category not found")
+)
+
+type TagSummary struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	PostCount int64  `json:"post_count"`
+}
+
+type CategoryNode struct {
+	ID       string         `json:"id"`
+	ParentID *string        `json:"parent_id"`
+	Name     string         `json:"name"`
+	Slug     string         `json:"slug"`
+	Children []CategoryNode `json:"children"`
+}
+
+type CreateCategoryInput struct {
+	Name     string
+	ParentID string
+}
+
+type TaxonomyService struct {
+	repo  repository.TaxonomyRepository
+	audit *AuditService
+	tx    repository.Transactor
+}
+
+func NewTaxonomyService(repo repository.TaxonomyRepository, audit *AuditService, tx repository.Transactor) *TaxonomyService {
+	return &TaxonomyService{repo: repo, audit: audit, tx: tx}
+}
+
+func (s *TaxonomyService) ListTags(ctx context.Context) ([]TagSummary, error) {
+	counts, err := s.repo.ListTagsWithCounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list tags: %w", err)
+	}
+
+	tags := make([]TagSummary, 0, len(counts))
+	for _, count := range counts {
+		tags = append(tags, TagSummary{ID: count.ID, Name: count.Name, Slug: count.Slug, PostCount: count.PostCount})
+	}
+	return tags, nil
+}
+
+// RenameTag renames a tag on behalf of actorID.
+func (s *TaxonomyService) RenameTag(ctx context.Context, actorID, tagID, name string) (TagSummary, error) {
+	normalized, err := normalizeTags([]string{name})
+	if err != nil {
+		return TagSummary{}, err
+	}
+	if len(normalized) != 1 {
+		return TagSummary{}, fmt.Errorf("tag name is required: %w", ErrValidation)
+	}
+	tag := normalized[0]
+
+	current, err := s.repo.GetTagByID(ctx, strings.TrimSpace(tagID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return TagSummary{}, ErrTagNotFound
+		}
+		return TagSummary{}, fmt.Errorf("load renamed tag: %w", err)
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.RenameTag(ctx, current.ID, tag.Name, tag.Slug); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditRecord{
+			ActorID:    actorID,
+			Action:     AuditTagRename,
+			TargetType: AuditTargetTag,
+			TargetID:   current.ID,
+			Before:     map[string]string{"name": current.Name, "slug": current.Slug},
+			After:      map[string]string{"name": tag.Name, "slug": tag.Slug},
+		})
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return TagSummary{}, ErrTagNotFound
+		}
+		if errors.Is(err, repository.ErrDuplicate) {
+			return TagSummary{}, fmt.Errorf("tag %q already exists, merge instead: %w", tag.Slug, ErrConflict)
+		}
+		return TagSummary{}, fmt.Errorf("rename tag: %w", err)
+	}
+
+	return TagSummary{ID: current.ID, Name: tag.Name, Slug: tag.Slug}, nil
+}
+
+// MergeTags moves the posts of the source tag to the target tag and deletes
+// the source tag on behalf of actorID.
+func (s *TaxonomyService) MergeTags(ctx context.Context, actorID, sourceID, targetID string) error {
+	sourceID = strings.TrimSpace(sourceID)
+	targetID = strings.TrimSpace(targetID)
+	if sourceID == "" || targetID == "" || sourceID == targetID {
+		return fmt.Errorf("merge needs two different tags: %w", ErrValidation)
+	}
+
+	source, err := s.repo.GetTagByID(ctx, sourceID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrTagNotFound
+		}
+		return fmt.Errorf("load merged tag: %w", err)
+	}
+	target, err := s.repo.GetTagByID(ctx, targetID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrTagNotFound
+		}
+		return fmt.Errorf("load merge target tag: %w", err)
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.MergeTags(ctx, source.ID, target.ID); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditRecord{
+			ActorID:    actorID,
+			Action:     AuditTagMerge,
+			TargetType: AuditTargetTag,
+			TargetID:   source.ID,
+			Before:     map[string]string{"name": source.Name, "slug": source.Slug},
+			After:      map[string]string{"merged_into": target.ID, "slug": target.Slug},
+		})
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrTagNotFound
+		}
+		return fmt.Errorf("merge tags: %w", err)
+	}
+	return nil
+}
+
+func (s *TaxonomyService) ListCategories(ctx context.Context) ([]CategoryNode, error) {
+	categories, err := s.repo.ListCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list categories: %w", err)
+	}
+	return buildCategoryTree(categories), nil
+}
+
+func (s *TaxonomyService) CreateCategory(ctx context.Context, input CreateCategoryInput) (CategoryNode, error) {
+	name := strings.TrimSpace(input.Name)
+	slug := slugify(name)
+	if slug == "" {
+		return CategoryNode{}, fmt.Errorf("category name is required: %w", ErrValidation)
+	}
+
+	category := &models.Category{Name: name, Slug: slug}
+	if parentID := strings.TrimSpace(input.ParentID); parentID != "" {
+		if _, err := s.repo.GetCategoryByID(ctx, parentID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return CategoryNode{}, ErrCategoryNotFound
+			}
+			return CategoryNode{}, fmt.Errorf("load parent category: %w", err)
+		}
+		category.ParentID = &parentID
+	}
+
+	if err := s.repo.CreateCategory(ctx, category); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return CategoryNode{}, fmt.Errorf("category %q already exists: %w", slug, ErrConflict)
+		}
+		return CategoryNode{}, fmt.Errorf("create category: %w", err)
+	}
+
+	return CategoryNode{ID: category.ID, ParentID: category.ParentID, Name: category.Name, Slug: category.Slug, Children: []CategoryNode{}}, nil
+}
+
+func buildCategoryTree(categories []models.Category) []CategoryNode {
+	children := make(map[string][]models.Category)
+	known := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		known[category.ID] = true
+	}
+
+	var roots []models.Category
+	for _, category := range categories {
+		if category.ParentID == nil || !known[*category.ParentID] {
+			roots = append(roots, category)
+			continue
+		}
+		children[*category.ParentID] = append(children[*category.ParentID], category)
+	}
+
+	var build func(list []models.Category) []CategoryNode
+	build = func(list []models.Category) []CategoryNode {
+		nodes := make([]CategoryNode, 0, len(list))
+		for _, category := range list {
+			nodes = append(nodes, CategoryNode{
+				ID:       category.ID,
+				ParentID: category.ParentID,
+				Name:     category.Name,
+				Slug:     category.Slug,
+				Children: build(children[category.ID]),
+			})
+		}
+		return nodes
+	}
+	return build(roots)
+}
+
+// normalizeTags trims and de-duplicates tag names by slug, keeping the first
+// spelling seen.
+func normalizeTags(names []string) ([]models.Tag, error) {
+	seen := make(map[string]bool, len(names))
+	tags := make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		name = strings.Join(strings.Fields(name), " ")
+		if name == "" {
+			continue
+		}
+		if len(name) > maxTagLength {
+			return nil, fmt.Errorf("tag %q exceeds %d characters: %w", name, maxTagLength, ErrValidation)
+		}
+
+		slug := slugify(name)
+		if slug == "" {
+			return nil, fmt.Errorf("tag %q has no letters or digits: %w", name, ErrValidation)
+		}
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		tags = append(tags, models.Tag{Name: name, Slug: slug})
+	}
+
+	if len(tags) > maxTagsPerPost {
+		return nil, fmt.Errorf("at most %d tags per post: %w", maxTagsPerPost, ErrValidation)
+	}
+	return tags, nil
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+type fakeTaxonomyRepo struct {
+	tags       []models.Tag
+	categories []models.Category
+	postTags   map[string][]string
+	renameErr  error
+	merged     [2]string
+}
+
+func (f *fakeTaxonomyRepo) UpsertTags(_ context.Context, tags []models.Tag) ([]models.Tag, error) {
+	stored := make([]models.Tag, 0, len(tags))
+	for _, tag := range tags {
+		tag.ID = "tag-" + tag.Slug
+		stored = append(stored, tag)
+	}
+	return stored, nil
+}
+
+func (f *fakeTaxonomyRepo) ReplacePostTags(_ context.Context, postID string, tagIDs []string) error {
+	if f.postTags == nil {
+		f.postTags = map[string][]string{}
+	}
+	f.postTags[postID] = tagIDs
+	return nil
+}
+
+func (f *fakeTaxonomyRepo) ListTagsWithCounts(context.Context) ([]repository.TagCount, error) {
+	counts := make([]repository.TagCount, 0, len(f.tags))
+	for _, tag := range f.tags {
+		counts = append(counts, repository.TagCount{Tag: tag, PostCount: 1})
+	}
+	return counts, nil
+}
+
+func (f *fakeTaxonomyRepo) GetTagByID(_ context.Context, id string) (*models.Tag, error) {
+	for i := range f.tags {
+		if f.tags[i].ID == id {
+			copy := f.tags[i]
+			return &copy, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeTaxonomyRepo) RenameTag(_ context.Context, id, name, slug string) error {
+	if f.renameErr != nil {
+		return f.renameErr
+	}
+	for i := range f.tags {
+		if f.tags[i].ID == id {
+			f.tags[i].Name, f.tags[i].Slug = name, slug
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (f *fakeTaxonomyRepo) MergeTags(_ context.Context, sourceID, targetID string) error {
+	f.merged = [2]string{sourceID, targetID}
+	return nil
+}
+
+func (f *fakeTaxonomyRepo) CreateCategory(_ context.Context, category *models.Category) error {
+	category.ID = "cat-" + category.Slug
+	f.categories = append(f.categories, *category)
+	return nil
+}
+
+func (f *fakeTaxonomyRepo) GetCategoryByID(_ context.Context, id string) (*models.Category, error) {
+	for i := range f.categories {
+		if f.categories[i].ID == id {
+			copy := f.categories[i]
+			return &copy, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeTaxonomyRepo) GetCategoryBySlug(_ context.Context, slug string) (*models.Category, error) {
+	for i := range f.categories {
+		if f.categories[i].Slug == slug {
+			copy := f.categories[i]
+			return &copy, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeTaxonomyRepo) ListCategories(context.Context) ([]models.Category, error) {
+	return f.categories, nil
+}
+
+func TestNormalizeTagsValidation(t *testing.T) {
+	if _, err := normalizeTags([]string{"???"}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for symbol-only tag, got %v", err)
+	}
+	if _, err := normalizeTags([]string{strings.Repeat("x", maxTagLength+1)}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for long tag, got %v", err)
+	}
+
+	many := make([]string, 0, maxTagsPerPost+1)
+	for i := 0; i <= maxTagsPerPost; i++ {
+		many = append(many, strings.Repeat("t", i+1))
+	}
+	if _, err := normalizeTags(many); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for too many tags, got %v", err)
+	}
+}
+
+func TestTaxonomyServiceRenameTagConflict(t *testing.T) {
+	repo := &fakeTaxonomyRepo{tags: []models.Tag{{ID: "t1", Name: "go", Slug: "go"}}, renameErr: repository.ErrDuplicate}
+	events := &fakeAuditRepo{}
+	svc := NewTaxonomyService(repo, NewAuditService(events, defaultPolicy()), fakeTransactor{})
+
+	_, err := svc.RenameTag(context.Background(), "admin-1", "t1", "Golang")
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if len(events.events) != 0 {
+		t.Fatalf("expected a failed rename not to be audited, got %+v", events.events)
+	}
+}
+
+func TestTaxonomyServiceRenameTagRecordsAudit(t *testing.T) {
+	repo := &fakeTaxonomyRepo{tags: []models.Tag{{ID: "t1", Name: "golang", Slug: "golang"}}}
+	events := &fakeAuditRepo{}
+	svc := NewTaxonomyService(repo, NewAuditService(events, defaultPolicy()), fakeTransactor{})
+
+	if _, err := svc.RenameTag(context.Background(), "admin-1", "missing", "Go"); !errors.Is(err, ErrTagNotFound) {
+		t.Fatalf("expected ErrTagNotFound, got %v", err)
+	}
+	tag, err := svc.RenameTag(context.Background(), "admin-1", "t1", "Go")
+	if err != nil {
+		t.Fatalf("expected rename to succeed: %v", err)
+	}
+	if tag.ID != "t1" || tag.Slug != "go" || repo.tags[0].Slug != "go" {
+		t.Fatalf("unexpected rename result %+v, stored %+v", tag, repo.tags[0])
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("expected only the rename to be audited, got %+v", events.events)
+	}
+	event := events.events[0]
+	if event.Action != AuditTagRename || *event.ActorID != "admin-1" || event.TargetID != "t1" ||
+		!strings.Contains(string(event.Before), `"slug":"golang"`) || !strings.Contains(string(event.After), `"slug":"go"`) {
+		t.Fatalf("unexpected audit event %+v", event)
+	}
+}
+
+func TestTaxonomyServiceMergeTags(t *testing.T) {
+	repo := &fakeTaxonomyRepo{tags: []models.Tag{{ID: "t1", Name: "golang", Slug: "golang"}, {ID: "t2", Name: "Go", Slug: "go"}}}
+	events := &fakeAuditRepo{}
+	svc := NewTaxonomyService(repo, NewAuditService(events, defaultPolicy()), fakeTransactor{})
+
+	if err := svc.MergeTags(context.Background(), "admin-1", "t1", "t1"); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for self-merge, got %v", err)
+	}
+	if err := svc.MergeTags(context.Background(), "admin-1", "t1", "missing"); !errors.Is(err, ErrTagNotFound) {
+		t.Fatalf("expected ErrTagNotFound, got %v", err)
+	}
+	if err := svc.MergeTags(context.Background(), "admin-1", "missing", "t2"); !errors.Is(err, ErrTagNotFound) {
+		t.Fatalf("expected ErrTagNotFound for a missing source, got %v", err)
+	}
+	if err := svc.MergeTags(context.Background(), "admin-1", "t1", "t2"); err != nil {
+		t.Fatalf("expected merge to succeed: %v", err)
+	}
+	if repo.merged != [2]string{"t1", "t2"} {
+		t.Fatalf("unexpected merge call: %v", repo.merged)
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("expected only the merge to be audited, got %+v", events.events)
+	}
+	event := events.events[0]
+	if event.Action != AuditTagMerge || *event.ActorID != "admin-1" || event.TargetID != "t1" || !strings.Contains(string(event.Before), `"golang"`) {
+		t.Fatalf("unexpected audit event %+v", event)
+	}
+}
+
+func TestTaxonomyServiceListCategoriesBuildsTree(t *testing.T) {
+	parent := "c1"
+	repo := &fakeTaxonomyRepo{categories: []models.Category{
+		{ID: "c1", Name: "Engineering", Slug: "engineering"},
+		{ID: "c2", ParentID: &parent, Name: "Backend", Slug: "backend"},
+		{ID: "c3", Name: "Culture", Slug: "culture"},
+	}}
+	svc := NewTaxonomyService(repo, newTestAudit(), fakeTransactor{})
+
+	tree, err := svc.ListCategories(context.Background())
+	if err != nil {
+		t.Fatalf("expected list categories to succeed: %v", err)
+	}
+	if len(tree) != 2 {
+		t.Fatalf("expected two root categories, got %d", len(tree))
+	}
+	if len(tree[0].Children) != 1 || tree[0].Children[0].Slug != "backend" {
+		t.Fatalf("expected backend nested under engineering, got %+v", tree[0])
+	}
+}
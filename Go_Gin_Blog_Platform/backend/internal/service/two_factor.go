@@ -0,0 +1,384 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+// recoveryCodeCount is how many recovery codes are issued when 2FA is enabled.
+const recoveryCodeCount = 10
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication already enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication not enabled")
+	ErrInvalidMFACode          = errors.New("invalid two-factor code")
+	ErrMFARequired             = errors.New("two-factor authentication required")
+)
+
+// MFARequiredError is returned by Login and provider sign-ins when the first
+// factor was accepted but the account needs a second one. Token is exchanged
+// through VerifyMFA. It matches ErrMFARequired with errors.Is.
+type MFARequiredError struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+func (e *MFARequiredError) Error() string {
+	return ErrMFARequired.Error()
+}
+
+func (e *MFARequiredError) Unwrap() error {
+	return ErrMFARequired
+}
+
+type TOTPSetup struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
+
+type EnableTOTPInput struct {
+	UserID string
+	Code   string
+}
+
+type DisableTOTPInput struct {
+	UserID          string
+	CurrentPassword string
+	// Code is a current authenticator code or an unused recovery code.
+	Code string
+}
+
+type VerifyMFAInput struct {
+	MFAToken string
+	// Exactly one of Code (from the authenticator app) and RecoveryCode is set.
+	Code         string
+	RecoveryCode string
+	IPAddress    string
+	UserAgent    string
+}
+
+// TwoFactorStatus reports whether the user has 2FA on and how many recovery
+// codes they have left.
+func (s *AuthService) TwoFactorStatus(ctx context.Context, userID string) (TwoFactorStatus, error) {
+	if _, err := s.loadUser(ctx, userID); err != nil {
+		return TwoFactorStatus{}, err
+	}
+
+	credential, err := s.mfa.GetTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return TwoFactorStatus{}, nil
+		}
+		return TwoFactorStatus{}, fmt.Errorf("get totp credential: %w", err)
+	}
+	if credential.EnabledAt == nil {
+		return TwoFactorStatus{}, nil
+	}
+
+	remaining, err := s.mfa.CountUnusedRecoveryCodes(ctx, userID)
+	if err != nil {
+		return TwoFactorStatus{}, fmt.Errorf("count recovery codes: %w", err)
+	}
+	return TwoFactorStatus{Enabled: true, EnabledAt: credential.EnabledAt, RecoveryCodesRemaining: remaining}, nil
+}
+
+// SetupTOTP starts enrollment with a fresh secret. 2FA stays off until
+// EnableTOTP confirms the user's app produces matching codes; calling it again
+// before then replaces the secret.
+func (s *AuthService) SetupTOTP(ctx context.Context, userID string) (TOTPSetup, error) {
+	user, err := s.loadUser(ctx, userID)
+	if err != nil {
+		return TOTPSetup{}, err
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return TOTPSetup{}, err
+	}
+	if err := s.mfa.SavePendingTOTP(ctx, user.ID, secret); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return TOTPSetup{}, ErrTwoFactorAlreadyEnabled
+		}
+		return TOTPSetup{}, fmt.Errorf("save totp secret: %w", err)
+	}
+
+	return TOTPSetup{Secret: secret, OTPAuthURI: auth.TOTPURI(s.mfaIssuer, user.Email, secret)}, nil
+}
+
+// EnableTOTP turns 2FA on once the user proves their app is set up, and returns
+// recovery codes. They are shown only this once; only their hashes are kept.
+func (s *AuthService) EnableTOTP(ctx context.Context, input EnableTOTPInput) ([]string, error) {
+	if strings.TrimSpace(input.UserID) == "" || strings.TrimSpace(input.Code) == "" {
+		return nil, fmt.Errorf("enable totp input invalid: %w", ErrValidation)
+	}
+
+	user, err := s.loadUser(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := s.mfa.GetTOTP(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("start 2fa setup first: %w", ErrValidation)
+		}
+		return nil, fmt.Errorf("get totp credential: %w", err)
+	}
+	if credential.EnabledAt != nil {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := auth.ValidateTOTP(credential.Secret, input.Code, s.now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.mfa.EnableTOTP(ctx, user.ID, step, s.now()); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrTwoFactorAlreadyEnabled
+			}
+			return fmt.Errorf("enable totp: %w", err)
+		}
+		if err := s.mfa.ReplaceRecoveryCodes(ctx, user.ID, hashes); err != nil {
+			return fmt.Errorf("store recovery codes: %w", err)
+		}
+		return s.audit.Record(ctx, AuditRecord{
+			ActorID:    user.ID,
+			Action:     AuditTwoFactorEnable,
+			TargetType: AuditTargetUser,
+			TargetID:   user.ID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.sendAccountNotice(ctx, user.Email, "Two-factor authentication enabled",
+		"Two-factor authentication is now on for your account. "+
+			"If this wasn't you, reset your password now: "+s.frontendBaseURL+"/forgot-password")
+	return codes, nil
+}
+
+// DisableTOTP turns 2FA off. It needs both the password and a second factor, so
+// a hijacked session alone cannot remove it.
+func (s *AuthService) DisableTOTP(ctx context.Context, input DisableTOTPInput) error {
+	if strings.TrimSpace(input.UserID) == "" || strings.TrimSpace(input.Code) == "" {
+		return fmt.Errorf("disable totp input invalid: %w", ErrValidation)
+	}
+
+	user, err := s.verifyCurrentPassword(ctx, input.UserID, input.CurrentPassword)
+	if err != nil {
+		return err
+	}
+
+	enabled, err := s.twoFactorEnabled(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return ErrTwoFactorNotEnabled
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.checkSecondFactor(ctx, user.ID, input.Code, input.Code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidMFACode
+		}
+
+		if err := s.mfa.DeleteTOTP(ctx, user.ID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("delete totp credential: %w", err)
+		}
+		if err := s.mfa.DeleteRecoveryCodes(ctx, user.ID); err != nil {
+			return fmt.Errorf("delete recovery codes: %w", err)
+		}
+		return s.audit.Record(ctx, AuditRecord{
+			ActorID:    user.ID,
+			Action:     AuditTwoFactorDisable,
+			TargetType: AuditTargetUser,
+			TargetID:   user.ID,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.sendAccountNotice(ctx, user.Email, "Two-factor authentication disabled",
+		"Two-factor authentication was turned off for your account. "+
+			"If this wasn't you, reset your password now: "+s.frontendBaseURL+"/forgot-password")
+	return nil
+}
+
+// VerifyMFA completes a sign-in that Login answered with an MFARequiredError.
+// Wrong codes count towards the same lockout as wrong passwords.
+func (s *AuthService) VerifyMFA(ctx context.Context, input VerifyMFAInput) (RegisteredUser, TokenPair, error) {
+	code := strings.TrimSpace(input.Code)
+	recoveryCode := strings.TrimSpace(input.RecoveryCode)
+	if strings.TrimSpace(input.MFAToken) == "" || (code == "") == (recoveryCode == "") {
+		return RegisteredUser{}, TokenPair{}, fmt.Errorf("mfa verification input invalid: %w", ErrValidation)
+	}
+
+	claims, err := s.tokenManager.ParseMFAChallengeToken(strings.TrimSpace(input.MFAToken))
+	if err != nil {
+		return RegisteredUser{}, TokenPair{}, ErrInvalidToken
+	}
+
+	ip := strings.TrimSpace(input.IPAddress)
+	if ip != "" {
+		if err := s.checkLocked(ctx, ipThrottleKey(ip)); err != nil {
+			return RegisteredUser{}, TokenPair{}, err
+		}
+	}
+
+	user, err := s.users.GetByID(ctx, claims.Subject)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return RegisteredUser{}, TokenPair{}, ErrInvalidToken
+		}
+		return RegisteredUser{}, TokenPair{}, fmt.Errorf("get user for mfa: %w", err)
+	}
+	if err := s.checkLocked(ctx, userThrottleKey(user.ID)); err != nil {
+		return RegisteredUser{}, TokenPair{}, err
+	}
+
+	ok, err := s.checkSecondFactor(ctx, user.ID, code, recoveryCode)
+	if err != nil {
+		return RegisteredUser{}, TokenPair{}, err
+	}
+	if !ok {
+		if err := s.recordLoginFailure(ctx, user, ip); err != nil {
+			return RegisteredUser{}, TokenPair{}, err
+		}
+		return RegisteredUser{}, TokenPair{}, ErrInvalidMFACode
+	}
+
+	if err := s.throttles.Clear(ctx, userThrottleKey(user.ID)); err != nil {
+		return RegisteredUser{}, TokenPair{}, fmt.Errorf("reset login failures: %w", err)
+	}
+
+	pair, err := s.issueTokenPair(ctx, user, sessionInfo{ipAddress: ip, userAgent: input.UserAgent, mfa: true})
+	if err != nil {
+		return RegisteredUser{}, TokenPair{}, err
+	}
+
+	if recoveryCode != "" {
+		s.sendAccountNotice(ctx, user.Email, "A recovery code was used to sign in",
+			"One of your two-factor recovery codes was just used to sign in. "+
+				"If this wasn't you, reset your password now: "+s.frontendBaseURL+"/forgot-password")
+	}
+
+	return registeredUser(user), pair, nil
+}
+
+// checkSecondFactor accepts an authenticator code or, failing that, an unused
+// recovery code, consuming whichever matched so it cannot be used again.
+func (s *AuthService) checkSecondFactor(ctx context.Context, userID, code, recoveryCode string) (bool, error) {
+	if code != "" {
+		credential, err := s.mfa.GetTOTP(ctx, userID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return false, ErrTwoFactorNotEnabled
+			}
+			return false, fmt.Errorf("get totp credential: %w", err)
+		}
+		if credential.EnabledAt == nil {
+			return false, ErrTwoFactorNotEnabled
+		}
+
+		if step, ok := auth.ValidateTOTP(credential.Secret, code, s.now()); ok {
+			err := s.mfa.ConsumeTOTPStep(ctx, userID, step)
+			if err == nil {
+				return true, nil
+			}
+			if !errors.Is(err, repository.ErrNotFound) {
+				return false, fmt.Errorf("consume totp code: %w", err)
+			}
+		}
+	}
+
+	if recoveryCode != "" {
+		err := s.mfa.UseRecoveryCode(ctx, userID, auth.HashToken(auth.NormalizeRecoveryCode(recoveryCode)), s.now())
+		if err == nil {
+			return true, nil
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return false, fmt.Errorf("use recovery code: %w", err)
+		}
+	}
+
+	return false, nil
+}
+
+func (s *AuthService) twoFactorEnabled(ctx context.Context, userID string) (bool, error) {
+	credential, err := s.mfa.GetTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("get totp credential: %w", err)
+	}
+	return credential.EnabledAt != nil, nil
+}
+
+// secondFactorChallenge returns an MFARequiredError when the user has 2FA on,
+// for sign-ins that proved only the first factor.
+func (s *AuthService) secondFactorChallenge(ctx context.Context, userID string) error {
+	enabled, err := s.twoFactorEnabled(ctx, userID)
+	if err != nil || !enabled {
+		return err
+	}
+	challenge, expiresAt, err := s.tokenManager.GenerateMFAChallengeToken(userID)
+	if err != nil {
+		return fmt.Errorf("generate mfa challenge: %w", err)
+	}
+	return &MFARequiredError{Token: challenge, ExpiresAt: expiresAt}
+}
+
+func (s *AuthService) loadUser(ctx context.Context, userID string) (*models.User, error) {
+	if strings.TrimSpace(userID) == "" {
+		return nil, fmt.Errorf("user id is required: %w", ErrValidation)
+	}
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	return user, nil
+}
+
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for len(codes) < recoveryCodeCount {
+		code, err := auth.GenerateRecoveryCode()
+		if err != nil {
+			return nil, nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, auth.HashToken(code))
+	}
+	return codes, hashes, nil
+}
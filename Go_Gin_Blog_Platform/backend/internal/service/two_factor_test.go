@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/repository"
+)
+
+type fakeMFARepo struct {
+	credentials map[string]*models.TOTPCredential
+	codes       []models.RecoveryCode
+}
+
+func newFakeMFARepo() *fakeMFARepo {
+	return &fakeMFARepo{credentials: make(map[string]*models.TOTPCredential)}
+}
+
+func (f *fakeMFARepo) GetTOTP(_ context.Context, userID string) (*models.TOTPCredential, error) {
+	credential, ok := f.credentials[userID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copy := *credential
+	return &copy, nil
+}
+
+func (f *fakeMFARepo) SavePendingTOTP(_ context.Context, userID, secret string) error {
+	if credential, ok := f.credentials[userID]; ok && credential.EnabledAt != nil {
+		return repository.ErrDuplicate
+	}
+	f.credentials[userID] = &models.TOTPCredential{UserID: userID, Secret: secret}
+	return nil
+}
+
+func (f *fakeMFARepo) EnableTOTP(_ context.Context, userID string, step int64, at time.Time) error {
+	credential, ok := f.credentials[userID]
+	if !ok || credential.EnabledAt != nil {
+		return repository.ErrNotFound
+	}
+	credential.EnabledAt = &at
+	credential.LastUsedStep = step
+	return nil
+}
+
+func (f *fakeMFARepo) ConsumeTOTPStep(_ context.Context, userID string, step int64) error {
+	credential, ok := f.credentials[userID]
+	if !ok || credential.EnabledAt == nil || credential.LastUsedStep >= step {
+		return repository.ErrNotFound
+	}
+	credential.LastUsedStep = step
+	return nil
+}
+
+func (f *fakeMFARepo) DeleteTOTP(_ context.Context, userID string) error {
+	if _, ok := f.credentials[userID]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(f.credentials, userID)
+	return nil
+}
+
+func (f *fakeMFARepo) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	_ = f.DeleteRecoveryCodes(ctx, userID)
+	for _, hash := range codeHashes {
+		f.codes = append(f.codes, models.RecoveryCode{UserID: userID, CodeHash: hash})
+	}
+	return nil
+}
+
+func (f *fakeMFARepo) UseRecoveryCode(_ context.Context, userID, codeHash string, at time.Time) error {
+	for i := range f.codes {
+		if f.codes[i].UserID == userID && f.codes[i].CodeHash == codeHash && f.codes[i].UsedAt == nil {
+			f.codes[i].UsedAt = &at
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (f *fakeMFARepo) CountUnusedRecoveryCodes(_ context.Context, userID string) (int64, error) {
+	var count int64
+	for _, code := range f.codes {
+		if code.UserID == userID && code.UsedAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeMFARepo) DeleteRecoveryCodes(_ context.Context, userID string) error {
+	kept := f.codes[:0]
+	for _, code := range f.codes {
+		if code.UserID != userID {
+			kept = append(kept, code)
+		}
+	}
+	f.codes = kept
+	return nil
+}
+
+// enrollTOTP turns 2FA on for alice and returns her secret and recovery codes.
+func enrollTOTP(t *testing.T, f authFixture) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	setup, err := f.svc.SetupTOTP(ctx, "u1")
+	if err != nil {
+		t.Fatalf("setup totp: %v", err)
+	}
+	uri, err := url.Parse(setup.OTPAuthURI)
+	if err != nil || uri.Query().Get("secret") != setup.Secret || uri.Query().Get("issuer") != "Blog" {
+		t.Fatalf("unexpected otpauth uri %q (%v)", setup.OTPAuthURI, err)
+	}
+
+	code, _ := auth.TOTPCode(setup.Secret, auth.TOTPStep(*f.clock))
+	recoveryCodes, err := f.svc.EnableTOTP(ctx, EnableTOTPInput{UserID: "u1", Code: code})
+	if err != nil {
+		t.Fatalf("enable totp: %v", err)
+	}
+	return setup.Secret, recoveryCodes
+}
+
+func (f authFixture) mfaChallenge(t *testing.T) string {
+	t.Helper()
+	err := f.login("correct-horse")
+	var required *MFARequiredError
+	if !errors.As(err, &required) || !errors.Is(err, ErrMFARequired) {
+		t.Fatalf("expected login to ask for a second factor, got %v", err)
+	}
+	return required.Token
+}
+
+func TestAuthServiceEnableTOTP(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+
+	setup, err := f.svc.SetupTOTP(ctx, "u1")
+	if err != nil {
+		t.Fatalf("setup totp: %v", err)
+	}
+	if _, err := f.svc.EnableTOTP(ctx, EnableTOTPInput{UserID: "u1", Code: "000000"}); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("expected a wrong code to be refused, got %v", err)
+	}
+	if err := f.login("correct-horse"); err != nil {
+		t.Fatalf("expected a pending secret not to affect login: %v", err)
+	}
+
+	code, _ := auth.TOTPCode(setup.Secret, auth.TOTPStep(*f.clock))
+	codes, err := f.svc.EnableTOTP(ctx, EnableTOTPInput{UserID: "u1", Code: code})
+	if err != nil {
+		t.Fatalf("enable totp: %v", err)
+	}
+	if len(codes) != recoveryCodeCount {
+		t.Fatalf("expected %d recovery codes, got %d", recoveryCodeCount, len(codes))
+	}
+	for _, stored := range f.mfa.codes {
+		for _, plain := range codes {
+			if stored.CodeHash == plain {
+				t.Fatalf("expected recovery codes to be stored hashed")
+			}
+		}
+	}
+
+	status, err := f.svc.TwoFactorStatus(ctx, "u1")
+	if err != nil || !status.Enabled || status.RecoveryCodesRemaining != recoveryCodeCount {
+		t.Fatalf("unexpected 2fa status %+v (%v)", status, err)
+	}
+	if _, err := f.svc.SetupTOTP(ctx, "u1"); !errors.Is(err, ErrTwoFactorAlreadyEnabled) {
+		t.Fatalf("expected setup to refuse replacing an enabled secret, got %v", err)
+	}
+}
+
+func TestAuthServiceLoginWithTOTP(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	secret, _ := enrollTOTP(t, f)
+
+	challenge := f.mfaChallenge(t)
+	*f.clock = f.clock.Add(30 * time.Second)
+	code, _ := auth.TOTPCode(secret, auth.TOTPStep(*f.clock))
+
+	user, pair, err := f.svc.VerifyMFA(ctx, VerifyMFAInput{MFAToken: challenge, Code: code})
+	if err != nil {
+		t.Fatalf("verify mfa: %v", err)
+	}
+	if user.ID != "u1" {
+		t.Fatalf("unexpected user %+v", user)
+	}
+	claims, err := f.svc.tokenManager.ParseAccessToken(pair.AccessToken)
+	if err != nil || !claims.MFA {
+		t.Fatalf("expected an mfa access token, got %+v (%v)", claims, err)
+	}
+
+	if _, _, err := f.svc.VerifyMFA(ctx, VerifyMFAInput{MFAToken: challenge, Code: code}); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("expected a used code to be refused, got %v", err)
+	}
+
+	refreshed, err := f.svc.Refresh(ctx, RefreshInput{RefreshToken: pair.RefreshToken})
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	claims, err = f.svc.tokenManager.ParseAccessToken(refreshed.AccessToken)
+	if err != nil || !claims.MFA {
+		t.Fatalf("expected refresh to keep the session's mfa flag, got %+v (%v)", claims, err)
+	}
+
+	if _, _, err := f.svc.VerifyMFA(ctx, VerifyMFAInput{MFAToken: pair.AccessToken, Code: code}); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected an access token not to stand in for a challenge, got %v", err)
+	}
+}
+
+func TestAuthServiceRecoveryCodesAreSingleUse(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	_, codes := enrollTOTP(t, f)
+	f.emails.sent = nil
+
+	typed := strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))
+	if _, _, err := f.svc.VerifyMFA(ctx, VerifyMFAInput{MFAToken: f.mfaChallenge(t), RecoveryCode: typed}); err != nil {
+		t.Fatalf("verify with recovery code: %v", err)
+	}
+	if len(f.emails.sent) != 1 || !strings.Contains(f.emails.sent[0].Subject, "recovery code") {
+		t.Fatalf("expected a recovery code notice, got %+v", f.emails.sent)
+	}
+
+	if _, _, err := f.svc.VerifyMFA(ctx, VerifyMFAInput{MFAToken: f.mfaChallenge(t), RecoveryCode: codes[0]}); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("expected a used recovery code to be refused, got %v", err)
+	}
+
+	status, _ := f.svc.TwoFactorStatus(ctx, "u1")
+	if status.RecoveryCodesRemaining != recoveryCodeCount-1 {
+		t.Fatalf("expected one recovery code to be spent, got %+v", status)
+	}
+}
+
+func TestAuthServiceWrongMFACodesLockAccount(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	enrollTOTP(t, f)
+
+	challenge := f.mfaChallenge(t)
+	for i := 0; i < 2; i++ {
+		if _, _, err := f.svc.VerifyMFA(ctx, VerifyMFAInput{MFAToken: challenge, Code: "000000", IPAddress: "198.51.100.7"}); !errors.Is(err, ErrInvalidMFACode) {
+			t.Fatalf("attempt %d: expected ErrInvalidMFACode, got %v", i+1, err)
+		}
+	}
+	if _, _, err := f.svc.VerifyMFA(ctx, VerifyMFAInput{MFAToken: challenge, Code: "000000", IPAddress: "198.51.100.7"}); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("expected the third wrong code to lock the account, got %v", err)
+	}
+}
+
+func TestAuthServiceDisableTOTP(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	secret, _ := enrollTOTP(t, f)
+
+	*f.clock = f.clock.Add(30 * time.Second)
+	code, _ := auth.TOTPCode(secret, auth.TOTPStep(*f.clock))
+	if err := f.svc.DisableTOTP(ctx, DisableTOTPInput{UserID: "u1", CurrentPassword: "wrong", Code: code}); !errors.Is(err, ErrIncorrectPassword) {
+		t.Fatalf("expected the password to be required, got %v", err)
+	}
+	if err := f.svc.DisableTOTP(ctx, DisableTOTPInput{UserID: "u1", CurrentPassword: "correct-horse", Code: "000000"}); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("expected a second factor to be required, got %v", err)
+	}
+	if err := f.svc.DisableTOTP(ctx, DisableTOTPInput{UserID: "u1", CurrentPassword: "correct-horse", Code: code}); err != nil {
+		t.Fatalf("disable totp: %v", err)
+	}
+
+	if err := f.login("correct-horse"); err != nil {
+		t.Fatalf("expected login without a second factor once 2fa is off: %v", err)
+	}
+	if len(f.mfa.codes) != 0 {
+		t.Fatalf("expected recovery codes to be removed, got %d", len(f.mfa.codes))
+	}
+}
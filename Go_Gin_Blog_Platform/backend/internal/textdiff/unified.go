@@ -0,0 +1,264 @@
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type edit struct {
+	kind opKind
+	line string
+}
+
+// Unified returns a line-level unified diff turning a into b, labelled with
+// fromName and toName, with contextLines of unchanged context around each hunk.
+// Identical inputs produce an empty string.
+func Unified(fromName, toName, a, b string, contextLines int) string {
+	if a == b {
+		return ""
+	}
+	if contextLines < 0 {
+		contextLines = 0
+	}
+
+	edits := diffLines(splitLines(a), splitLines(b))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+	for _, h := range hunks(edits, contextLines) {
+		writeHunk(&out, edits, h)
+	}
+	return out.String()
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// maxEditDistance bounds the work diffLines does: the search keeps O(D²) state
+// for an edit distance D, and bodies are author supplied. Inputs further apart
+// than this are shown as every differing line removed and re-added.
+const maxEditDistance = 1000
+
+// diffLines computes a shortest edit script with Myers' O(ND) algorithm, after
+// setting aside the lines both inputs start and end with.
+func diffLines(a, b []string) []edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]edit, 0, len(a)+len(b)-prefix-suffix)
+	for _, line := range a[:prefix] {
+		edits = append(edits, edit{kind: opEqual, line: line})
+	}
+	edits = append(edits, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, edit{kind: opEqual, line: line})
+	}
+	return edits
+}
+
+func myers(a, b []string) []edit {
+	n, m := len(a), len(b)
+	maxD := n + m
+	if maxD > maxEditDistance {
+		maxD = maxEditDistance
+	}
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+	// trace[d] holds the diagonals -d-1..d+1 of v as they were before step d,
+	// which is all backtrack reads, indexed from d+1.
+	var trace [][]int
+
+	for d := 0; d <= maxD; d++ {
+		snapshot := make([]int, 2*d+3)
+		copy(snapshot, v[offset-d-1:offset+d+2])
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b)
+			}
+		}
+	}
+	return replaceAll(a, b)
+}
+
+func backtrack(trace [][]int, a, b []string) []edit {
+	x, y := len(a), len(b)
+	var reversed []edit
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		offset := d + 1
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, edit{kind: opEqual, line: a[x]})
+		}
+		if d > 0 {
+			if x == prevX {
+				y--
+				reversed = append(reversed, edit{kind: opInsert, line: b[y]})
+			} else {
+				x--
+				reversed = append(reversed, edit{kind: opDelete, line: a[x]})
+			}
+		}
+	}
+
+	edits := make([]edit, len(reversed))
+	for i := range reversed {
+		edits[i] = reversed[len(reversed)-1-i]
+	}
+	return edits
+}
+
+// replaceAll is the edit script for inputs too far apart to diff: every line
+// of a removed, then every line of b added.
+func replaceAll(a, b []string) []edit {
+	edits := make([]edit, 0, len(a)+len(b))
+	for _, line := range a {
+		edits = append(edits, edit{kind: opDelete, line: line})
+	}
+	for _, line := range b {
+		edits = append(edits, edit{kind: opInsert, line: line})
+	}
+	return edits
+}
+
+type hunk struct {
+	start, end int
+}
+
+// hunks groups changed edits with their surrounding context, merging groups
+// whose context would overlap.
+func hunks(edits []edit, contextLines int) []hunk {
+	var out []hunk
+	for i := 0; i < len(edits); i++ {
+		if edits[i].kind == opEqual {
+			continue
+		}
+
+		start := i - contextLines
+		if start < 0 {
+			start = 0
+		}
+		end := i + 1
+		for j := i + 1; j < len(edits); j++ {
+			if edits[j].kind != opEqual {
+				end = j + 1
+				continue
+			}
+			if j-end >= 2*contextLines {
+				break
+			}
+		}
+		end += contextLines
+		if end > len(edits) {
+			end = len(edits)
+		}
+
+		if len(out) > 0 && start <= out[len(out)-1].end {
+			out[len(out)-1].end = end
+		} else {
+			out = append(out, hunk{start: start, end: end})
+		}
+		i = end - 1
+	}
+	return out
+}
+
+func writeHunk(out *strings.Builder, edits []edit, h hunk) {
+	oldLine, newLine := 1, 1
+	for _, e := range edits[:h.start] {
+		if e.kind != opInsert {
+			oldLine++
+		}
+		if e.kind != opDelete {
+			newLine++
+		}
+	}
+
+	oldCount, newCount := 0, 0
+	for _, e := range edits[h.start:h.end] {
+		if e.kind != opInsert {
+			oldCount++
+		}
+		if e.kind != opDelete {
+			newCount++
+		}
+	}
+
+	// Unified diff headers point at the line before an empty range.
+	if oldCount == 0 {
+		oldLine--
+	}
+	if newCount == 0 {
+		newLine--
+	}
+
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(oldLine, oldCount), hunkRange(newLine, newCount))
+	for _, e := range edits[h.start:h.end] {
+		switch e.kind {
+		case opEqual:
+			out.WriteString(" ")
+		case opDelete:
+			out.WriteString("-")
+		case opInsert:
+			out.WriteString("+")
+		}
+		out.WriteString(e.line)
+		out.WriteString("\n")
+	}
+}
+
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
@@ -0,0 +1,91 @@
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedIdenticalInputIsEmpty(t *testing.T) {
+	if got := Unified("a", "b", "same\ntext\n", "same\ntext\n", 3); got != "" {
+		t.Fatalf("expected empty diff, got %q", got)
+	}
+}
+
+func TestUnifiedSingleChange(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\n"
+	b := "one\ntwo\nthree\nfour\nFIVE\nsix\nseven\neight\n"
+
+	want := "--- revision 1\n+++ current\n" +
+		"@@ -2,7 +2,7 @@\n" +
+		" two\n three\n four\n-five\n+FIVE\n six\n seven\n eight\n"
+	if got := Unified("revision 1", "current", a, b, 3); got != want {
+		t.Fatalf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnifiedSeparateHunks(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	b := "x\n2\n3\n4\n5\n6\n7\n8\n9\ny\n"
+
+	want := "--- a\n+++ b\n" +
+		"@@ -1,2 +1,2 @@\n-1\n+x\n 2\n" +
+		"@@ -9,2 +9,2 @@\n 9\n-10\n+y\n"
+	if got := Unified("a", "b", a, b, 1); got != want {
+		t.Fatalf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnifiedInsertIntoEmpty(t *testing.T) {
+	want := "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+hello\n+world\n"
+	if got := Unified("a", "b", "", "hello\nworld", 3); got != want {
+		t.Fatalf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDiffLinesReproducesBothInputs(t *testing.T) {
+	a := strings.Split("a b c a b b a x y z q", " ")
+	b := strings.Split("c b a b a c x z q r", " ")
+
+	edits := diffLines(a, b)
+	var gotA, gotB []string
+	changes := 0
+	for _, e := range edits {
+		if e.kind != opInsert {
+			gotA = append(gotA, e.line)
+		}
+		if e.kind != opDelete {
+			gotB = append(gotB, e.line)
+		}
+		if e.kind != opEqual {
+			changes++
+		}
+	}
+	if strings.Join(gotA, " ") != strings.Join(a, " ") || strings.Join(gotB, " ") != strings.Join(b, " ") {
+		t.Fatalf("edit script does not turn a into b: %+v", edits)
+	}
+	if changes != 7 {
+		t.Fatalf("expected a shortest edit script of 7 changes, got %d", changes)
+	}
+}
+
+func TestDiffLinesReplacesInputsTooFarApart(t *testing.T) {
+	var a, b []string
+	for i := 0; i < maxEditDistance; i++ {
+		a = append(a, fmt.Sprintf("old %d", i))
+		b = append(b, fmt.Sprintf("new %d", i))
+	}
+	a = append([]string{"title"}, append(a, "footer")...)
+	b = append([]string{"title"}, append(b, "footer")...)
+
+	edits := diffLines(a, b)
+	if len(edits) != 2*maxEditDistance+2 {
+		t.Fatalf("expected every differing line removed and re-added, got %d edits", len(edits))
+	}
+	if edits[0] != (edit{kind: opEqual, line: "title"}) || edits[len(edits)-1] != (edit{kind: opEqual, line: "footer"}) {
+		t.Fatalf("expected the shared first and last lines to stay as context")
+	}
+	if edits[1].kind != opDelete || edits[maxEditDistance+1].kind != opInsert {
+		t.Fatalf("expected deletions followed by insertions, got %+v and %+v", edits[1], edits[maxEditDistance+1])
+	}
+}
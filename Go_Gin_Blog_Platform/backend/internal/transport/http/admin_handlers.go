@@ -12,7 +12,8 @@ import (
 
 type AdminService interface {
 	ListUsers(ctx context.Context, page, limit int) ([]service.UserSummary, service.Pagination, error)
-	UpdateUserRole(ctx context.Context, userID, role string) (service.UserSummary, error)
+	UpdateUserRole(ctx context.Context, actorID, actorRole, userID, role string) (service.UserSummary, error)
+	UnlockUser(ctx context.Context, actorID, userID string) error
 }
 
 type AdminHandler struct {
@@ -47,7 +48,13 @@ func (h *AdminHandler) UpdateRole(c *gin.Context) {
 		return
 	}
 
-	user, err := h.adminService.UpdateUserRole(c.Request.Context(), c.Param("id"), req.Role)
+	actorID, actorRole, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	user, err := h.adminService.UpdateUserRole(c.Request.Context(), actorID, actorRole, c.Param("id"), req.Role)
 	if err != nil {
 		handleAdminError(c, err)
 		return
@@ -56,10 +63,27 @@ func (h *AdminHandler) UpdateRole(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": user})
 }
 
+func (h *AdminHandler) UnlockUser(c *gin.Context) {
+	actorID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	if err := h.adminService.UnlockUser(c.Request.Context(), actorID, c.Param("id")); err != nil {
+		handleAdminError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 func handleAdminError(c *gin.Context, err error) {
 	switch {
 	case errors.Is(err, service.ErrValidation):
 		writeError(c, http.StatusBadRequest, "validation_error", "Request validation failed", gin.H{"reason": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		writeError(c, http.StatusForbidden, "forbidden", "Insufficient permissions", nil)
 	case errors.Is(err, service.ErrUserNotFound):
 		writeError(c, http.StatusNotFound, "user_not_found", "User was not found", nil)
 	default:
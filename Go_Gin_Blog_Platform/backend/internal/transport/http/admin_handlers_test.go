@@ -19,10 +19,17 @@ func (f fakeAdminService) ListUsers(_ context.Context, _, _ int) ([]service.User
 	return []service.UserSummary{{ID: "u1", Email: "a@example.com", Role: models.RoleAuthor}}, service.Pagination{Page: 1, Limit: 10, Total: 1, TotalPages: 1}, nil
 }
 
-func (f fakeAdminService) UpdateUserRole(_ context.Context, userID, role string) (service.UserSummary, error) {
+func (f fakeAdminService) UpdateUserRole(_ context.Context, _, _, userID, role string) (service.UserSummary, error) {
 	return service.UserSummary{ID: userID, Email: "a@example.com", Role: models.Role(role)}, nil
 }
 
+func (f fakeAdminService) UnlockUser(_ context.Context, _, userID string) error {
+	if userID != "u1" {
+		return service.ErrUserNotFound
+	}
+	return nil
+}
+
 func TestAdminListUsersSuccess(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -61,3 +68,24 @@ func TestAdminUpdateRoleValidation(t *testing.T) {
 		t.Fatalf("expected status 400, got %d", w.Code)
 	}
 }
+
+func TestAdminUnlockUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewAdminHandler(fakeAdminService{})
+	r.Use(func(c *gin.Context) {
+		c.Set(ContextKeyUserID, "admin-1")
+		c.Set(ContextKeyRole, "admin")
+	})
+	r.POST("/admin/users/:id/unlock", h.UnlockUser)
+
+	for path, want := range map[string]int{"/admin/users/u1/unlock": http.StatusNoContent, "/admin/users/missing/unlock": http.StatusNotFound} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		r.ServeHTTP(w, req)
+
+		if w.Code != want {
+			t.Fatalf("%s: expected status %d, got %d", path, want, w.Code)
+		}
+	}
+}
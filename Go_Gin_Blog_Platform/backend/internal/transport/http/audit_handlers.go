@@ -0,0 +1,179 @@
+package http
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	auditExportCSV    = "csv"
+	auditExportNDJSON = "ndjson"
+	// auditExportTimeout replaces the server's write timeout for exports,
+	// which can run far longer than a regular response.
+	auditExportTimeout = 5 * time.Minute
+)
+
+var auditCSVHeader = []string{"id", "created_at", "actor_id", "action", "target_type", "target_id", "ip_address", "request_id", "before", "after"}
+
+type AuditService interface {
+	List(ctx context.Context, query service.AuditQuery) ([]service.AuditEventItem, string, error)
+	Export(ctx context.Context, query service.AuditQuery, write func(service.AuditEventItem) error) error
+}
+
+// AuditHandler serves /admin/audit, the read side of the audit log.
+type AuditHandler struct {
+	auditService AuditService
+}
+
+func NewAuditHandler(auditService AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+func (h *AuditHandler) List(c *gin.Context) {
+	query, ok := auditQuery(c)
+	if !ok {
+		return
+	}
+	query.Cursor = c.Query("cursor")
+	query.Limit, _ = strconv.Atoi(c.Query("limit"))
+
+	events, next, err := h.auditService.List(c.Request.Context(), query)
+	if err != nil {
+		handleAuditError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": events, "meta": gin.H{"next_cursor": next}})
+}
+
+// Export streams every matching event as CSV (the default) or NDJSON. The
+// response starts with the first event, so a refused query still gets a
+// regular error response.
+func (h *AuditHandler) Export(c *gin.Context) {
+	query, ok := auditQuery(c)
+	if !ok {
+		return
+	}
+	format := strings.ToLower(strings.TrimSpace(c.DefaultQuery("format", auditExportCSV)))
+	if format != auditExportCSV && format != auditExportNDJSON {
+		writeError(c, http.StatusBadRequest, "validation_error", "Request validation failed", gin.H{"reason": "format must be csv or ndjson"})
+		return
+	}
+
+	started := false
+	csvWriter := csv.NewWriter(c.Writer)
+	encoder := json.NewEncoder(c.Writer)
+	start := func() error {
+		started = true
+		_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(auditExportTimeout))
+		filename := fmt.Sprintf("audit-%s.%s", time.Now().UTC().Format("20060102T150405Z"), format)
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+		if format == auditExportNDJSON {
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Status(http.StatusOK)
+			return nil
+		}
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Status(http.StatusOK)
+		return csvWriter.Write(auditCSVHeader)
+	}
+
+	err := h.auditService.Export(c.Request.Context(), query, func(event service.AuditEventItem) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if format == auditExportNDJSON {
+			return encoder.Encode(event)
+		}
+		return csvWriter.Write(auditCSVRow(event))
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if format == auditExportCSV {
+		csvWriter.Flush()
+		if err == nil {
+			err = csvWriter.Error()
+		}
+	}
+	if err != nil {
+		if !started {
+			handleAuditError(c, err)
+			return
+		}
+		// The status is already sent; cutting the stream short is all that is
+		// left, and the error ends up in the request log.
+		_ = c.Error(err)
+		c.Abort()
+	}
+}
+
+func auditQuery(c *gin.Context) (service.AuditQuery, bool) {
+	_, viewerRole, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return service.AuditQuery{}, false
+	}
+
+	return service.AuditQuery{
+		ViewerRole: viewerRole,
+		Actor:      c.Query("actor"),
+		Action:     c.Query("action"),
+		From:       c.Query("from"),
+		To:         c.Query("to"),
+	}, true
+}
+
+func auditCSVRow(event service.AuditEventItem) []string {
+	var actorID string
+	if event.ActorID != nil {
+		actorID = *event.ActorID
+	}
+	row := []string{
+		event.ID,
+		event.CreatedAt.UTC().Format(time.RFC3339Nano),
+		actorID,
+		event.Action,
+		event.TargetType,
+		event.TargetID,
+		event.IPAddress,
+		event.RequestID,
+		string(event.Before),
+		string(event.After),
+	}
+	for i := range row {
+		row[i] = csvSafe(row[i])
+	}
+	return row
+}
+
+// csvSafe stops spreadsheets from reading a cell as a formula.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func handleAuditError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		writeError(c, http.StatusBadRequest, "validation_error", "Request validation failed", gin.H{"reason": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		writeError(c, http.StatusForbidden, "forbidden", "Insufficient permissions", nil)
+	default:
+		writeError(c, http.StatusInternalServerError, "internal_error", "Unexpected server error", nil)
+	}
+}
@@ -0,0 +1,137 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type fakeAuditService struct{}
+
+func (fakeAuditService) events() []service.AuditEventItem {
+	actor := "admin-1"
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	return []service.AuditEventItem{
+		{ID: "e2", ActorID: &actor, Action: service.AuditUserRoleUpdate, TargetType: "user", TargetID: "u1", Before: json.RawMessage(`{"role":"author"}`), After: json.RawMessage(`{"role":"editor"}`), CreatedAt: at},
+		{ID: "e1", ActorID: &actor, Action: service.AuditPostPurge, TargetType: "post", TargetID: "=HYPERLINK(\"x\")", CreatedAt: at.Add(-time.Hour)},
+	}
+}
+
+func (f fakeAuditService) List(_ context.Context, query service.AuditQuery) ([]service.AuditEventItem, string, error) {
+	if query.ViewerRole != "admin" {
+		return nil, "", service.ErrForbidden
+	}
+	if query.From == "yesterday" {
+		return nil, "", service.ErrValidation
+	}
+	return f.events()[:1], "next-page", nil
+}
+
+func (f fakeAuditService) Export(_ context.Context, query service.AuditQuery, write func(service.AuditEventItem) error) error {
+	if query.ViewerRole != "admin" {
+		return service.ErrForbidden
+	}
+	for _, event := range f.events() {
+		if err := write(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newAuditRouter(role string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewAuditHandler(fakeAuditService{})
+	r.Use(func(c *gin.Context) {
+		c.Set(ContextKeyUserID, "admin-1")
+		c.Set(ContextKeyRole, role)
+	})
+	r.GET("/admin/audit", h.List)
+	r.GET("/admin/audit/export", h.Export)
+	return r
+}
+
+func TestAuditListRoute(t *testing.T) {
+	r := newAuditRouter("admin")
+
+	cases := map[string]int{
+		"/admin/audit?action=user.role.update": http.StatusOK,
+		"/admin/audit?from=yesterday":          http.StatusBadRequest,
+	}
+	for path, want := range cases {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != want {
+			t.Fatalf("%s: expected %d, got %d %s", path, want, w.Code, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/audit", nil))
+	var payload struct {
+		Data []service.AuditEventItem `json:"data"`
+		Meta struct {
+			NextCursor string `json:"next_cursor"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("expected json response: %v", err)
+	}
+	if len(payload.Data) != 1 || payload.Meta.NextCursor != "next-page" {
+		t.Fatalf("unexpected page %+v", payload)
+	}
+
+	w = httptest.NewRecorder()
+	newAuditRouter("editor").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/audit", nil))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected non-admins to be refused, got %d", w.Code)
+	}
+}
+
+func TestAuditExportRoute(t *testing.T) {
+	r := newAuditRouter("admin")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/audit/export", nil))
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("expected a csv export, got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Disposition"), "attachment;") {
+		t.Fatalf("expected an attachment, got %q", w.Header().Get("Content-Disposition"))
+	}
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "id,created_at,actor_id,action") {
+		t.Fatalf("expected a header and two rows, got %q", w.Body.String())
+	}
+	if !strings.Contains(lines[2], `"'=HYPERLINK(""x"")"`) {
+		t.Fatalf("expected formula cells to be escaped, got %q", lines[2])
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/audit/export?format=ndjson", nil))
+	lines = strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	var first service.AuditEventItem
+	if w.Code != http.StatusOK || len(lines) != 2 || json.Unmarshal([]byte(lines[0]), &first) != nil || first.ID != "e2" {
+		t.Fatalf("expected one json event per line, got %d %q", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/audit/export?format=xml", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected an unknown format to be refused, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	newAuditRouter("editor").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/audit/export", nil))
+	if w.Code != http.StatusForbidden || w.Header().Get("Content-Disposition") != "" {
+		t.Fatalf("expected a refused export to get a plain error, got %d", w.Code)
+	}
+}
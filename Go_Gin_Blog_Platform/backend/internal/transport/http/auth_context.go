@@ -27,3 +27,11 @@ func currentUserFromContext(c *gin.Context) (userID string, role string, ok bool
 
 	return userID, role, true
 }
+
+// currentSessionFromContext returns the session the caller's access token was
+// issued for, or "" when it carries none.
+func currentSessionFromContext(c *gin.Context) string {
+	sessionID, _ := c.Get(ContextKeySessionID)
+	value, _ := sessionID.(string)
+	return value
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
 	"github.com/gin-gonic/gin"
@@ -16,6 +18,16 @@ type AuthService interface {
 	Logout(ctx context.Context, input service.LogoutInput) error
 	RequestPasswordReset(ctx context.Context, input service.RequestResetInput) error
 	ConfirmPasswordReset(ctx context.Context, input service.ConfirmResetInput) error
+	ChangePassword(ctx context.Context, input service.ChangePasswordInput) error
+	RequestEmailChange(ctx context.Context, input service.RequestEmailChangeInput) error
+	ConfirmEmailChange(ctx context.Context, input service.ConfirmEmailChangeInput) error
+	VerifyEmail(ctx context.Context, input service.VerifyEmailInput) error
+	ResendVerification(ctx context.Context, userID string) error
+	VerifyMFA(ctx context.Context, input service.VerifyMFAInput) (service.RegisteredUser, service.TokenPair, error)
+	TwoFactorStatus(ctx context.Context, userID string) (service.TwoFactorStatus, error)
+	SetupTOTP(ctx context.Context, userID string) (service.TOTPSetup, error)
+	EnableTOTP(ctx context.Context, input service.EnableTOTPInput) ([]string, error)
+	DisableTOTP(ctx context.Context, input service.DisableTOTPInput) error
 }
 
 type AuthHandler struct {
@@ -53,6 +65,24 @@ type confirmResetRequest struct {
 	NewPassword string `json:"new_password" binding:"required,min=8"`
 }
 
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8"`
+}
+
+type requestEmailChangeRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewEmail        string `json:"new_email" binding:"required,email"`
+}
+
+type confirmEmailChangeRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type verifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req registerRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -60,7 +90,12 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	user, tokenPair, err := h.authService.Register(c.Request.Context(), service.RegisterInput{Email: req.Email, Password: req.Password})
+	user, tokenPair, err := h.authService.Register(c.Request.Context(), service.RegisterInput{
+		Email:     req.Email,
+		Password:  req.Password,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
 	if err != nil {
 		handleAuthError(c, err)
 		return
@@ -76,8 +111,23 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	user, tokenPair, err := h.authService.Login(c.Request.Context(), service.LoginInput{Email: req.Email, Password: req.Password})
+	user, tokenPair, err := h.authService.Login(c.Request.Context(), service.LoginInput{
+		Email:     req.Email,
+		Password:  req.Password,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
 	if err != nil {
+		if writeMFARequired(c, err) {
+			return
+		}
+		// An account lock looks like a wrong password here, or 423 vs 401
+		// would tell which emails have accounts; the owner learns of it by
+		// email. A locked client IP is refused whatever the email.
+		var locked *service.AccountLockedError
+		if errors.As(err, &locked) && !locked.ClientIP {
+			err = service.ErrInvalidCredentials
+		}
 		handleAuthError(c, err)
 		return
 	}
@@ -92,7 +142,11 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 		return
 	}
 
-	tokenPair, err := h.authService.Refresh(c.Request.Context(), service.RefreshInput{RefreshToken: req.RefreshToken})
+	tokenPair, err := h.authService.Refresh(c.Request.Context(), service.RefreshInput{
+		RefreshToken: req.RefreshToken,
+		IPAddress:    c.ClientIP(),
+		UserAgent:    c.Request.UserAgent(),
+	})
 	if err != nil {
 		handleAuthError(c, err)
 		return
@@ -149,8 +203,127 @@ func (h *AuthHandler) ConfirmPasswordReset(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Password has been reset successfully"})
 }
 
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var req changePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
+	userID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	if err := h.authService.ChangePassword(c.Request.Context(), service.ChangePasswordInput{
+		UserID:          userID,
+		SessionID:       currentSessionFromContext(c),
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+	}); err != nil {
+		handleAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password has been changed"})
+}
+
+func (h *AuthHandler) RequestEmailChange(c *gin.Context) {
+	var req requestEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
+	userID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	if err := h.authService.RequestEmailChange(c.Request.Context(), service.RequestEmailChangeInput{
+		UserID:          userID,
+		CurrentPassword: req.CurrentPassword,
+		NewEmail:        req.NewEmail,
+	}); err != nil {
+		handleAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "A confirmation link has been sent to the new email address"})
+}
+
+func (h *AuthHandler) ConfirmEmailChange(c *gin.Context) {
+	var req confirmEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
+	if err := h.authService.ConfirmEmailChange(c.Request.Context(), service.ConfirmEmailChangeInput{Token: req.Token}); err != nil {
+		handleAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email has been changed"})
+}
+
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req verifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
+	if err := h.authService.VerifyEmail(c.Request.Context(), service.VerifyEmailInput{Token: req.Token}); err != nil {
+		handleAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email has been verified"})
+}
+
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	userID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	if err := h.authService.ResendVerification(c.Request.Context(), userID); err != nil {
+		handleAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "A verification link has been sent"})
+}
+
+// writeMFARequired answers a sign-in that still needs a second factor with the
+// challenge token for POST /auth/login/mfa.
+func writeMFARequired(c *gin.Context, err error) bool {
+	var mfaRequired *service.MFARequiredError
+	if !errors.As(err, &mfaRequired) {
+		return false
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"mfa_required":   true,
+		"mfa_token":      mfaRequired.Token,
+		"mfa_expires_at": mfaRequired.ExpiresAt,
+	})
+	return true
+}
+
 func handleAuthError(c *gin.Context, err error) {
+	var locked *service.AccountLockedError
 	switch {
+	case errors.As(err, &locked):
+		retryAfter := ceilSeconds(time.Until(locked.Until))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		writeError(c, http.StatusLocked, "account_locked", "Too many failed sign-in attempts, try again later", gin.H{"locked_until": locked.Until.UTC()})
 	case errors.Is(err, service.ErrValidation):
 		writeError(c, http.StatusBadRequest, "validation_error", "Request validation failed", gin.H{"reason": err.Error()})
 	case errors.Is(err, service.ErrEmailAlreadyUsed):
@@ -159,6 +332,18 @@ func handleAuthError(c *gin.Context, err error) {
 		writeError(c, http.StatusUnauthorized, "invalid_credentials", "Email or password is incorrect", nil)
 	case errors.Is(err, service.ErrInvalidToken):
 		writeError(c, http.StatusUnauthorized, "invalid_token", "Token is invalid or expired", nil)
+	case errors.Is(err, service.ErrIncorrectPassword):
+		writeError(c, http.StatusForbidden, "incorrect_password", "Current password is incorrect", nil)
+	case errors.Is(err, service.ErrUserNotFound):
+		writeError(c, http.StatusNotFound, "user_not_found", "User was not found", nil)
+	case errors.Is(err, service.ErrEmailAlreadyVerified):
+		writeError(c, http.StatusConflict, "email_already_verified", "Email is already verified", nil)
+	case errors.Is(err, service.ErrInvalidMFACode):
+		writeError(c, http.StatusUnauthorized, "invalid_mfa_code", "Two-factor code is invalid", nil)
+	case errors.Is(err, service.ErrTwoFactorAlreadyEnabled):
+		writeError(c, http.StatusConflict, "two_factor_already_enabled", "Two-factor authentication is already enabled", nil)
+	case errors.Is(err, service.ErrTwoFactorNotEnabled):
+		writeError(c, http.StatusConflict, "two_factor_not_enabled", "Two-factor authentication is not enabled", nil)
 	default:
 		writeError(c, http.StatusInternalServerError, "internal_error", "Unexpected server error", nil)
 	}
@@ -9,9 +9,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 type fakeAuthService struct{}
@@ -20,7 +22,16 @@ func (f fakeAuthService) Register(_ context.Context, _ service.RegisterInput) (s
 	return service.RegisteredUser{ID: "u1", Email: "test@example.com", Role: models.RoleAuthor}, service.TokenPair{AccessToken: "a", RefreshToken: "r", TokenType: "Bearer", AccessExpiresAt: time.Now(), RefreshExpiresAt: time.Now().Add(time.Hour)}, nil
 }
 
-func (f fakeAuthService) Login(_ context.Context, _ service.LoginInput) (service.RegisteredUser, service.TokenPair, error) {
+func (f fakeAuthService) Login(_ context.Context, input service.LoginInput) (service.RegisteredUser, service.TokenPair, error) {
+	if input.Email == "locked@example.com" && input.IPAddress != "" {
+		return service.RegisteredUser{}, service.TokenPair{}, &service.AccountLockedError{Until: time.Now().Add(90 * time.Second)}
+	}
+	if input.Email == "blocked-ip@example.com" {
+		return service.RegisteredUser{}, service.TokenPair{}, &service.AccountLockedError{Until: time.Now().Add(90 * time.Second), ClientIP: true}
+	}
+	if input.Email == "mfa@example.com" {
+		return service.RegisteredUser{}, service.TokenPair{}, &service.MFARequiredError{Token: "challenge", ExpiresAt: time.Now().Add(5 * time.Minute)}
+	}
 	return service.RegisteredUser{}, service.TokenPair{}, service.ErrInvalidCredentials
 }
 
@@ -40,6 +51,76 @@ func (f fakeAuthService) ConfirmPasswordReset(_ context.Context, _ service.Confi
 	return nil
 }
 
+func (f fakeAuthService) ChangePassword(_ context.Context, input service.ChangePasswordInput) error {
+	if input.CurrentPassword == "locked" {
+		return &service.AccountLockedError{Until: time.Now().Add(90 * time.Second)}
+	}
+	if input.CurrentPassword != "old-password" {
+		return service.ErrIncorrectPassword
+	}
+	if input.UserID != "u1" || input.SessionID != "s1" {
+		return service.ErrUserNotFound
+	}
+	return nil
+}
+
+func (f fakeAuthService) RequestEmailChange(_ context.Context, input service.RequestEmailChangeInput) error {
+	if input.NewEmail == "taken@example.com" {
+		return service.ErrEmailAlreadyUsed
+	}
+	return nil
+}
+
+func (f fakeAuthService) VerifyEmail(_ context.Context, input service.VerifyEmailInput) error {
+	if input.Token != "valid" {
+		return service.ErrInvalidToken
+	}
+	return nil
+}
+
+func (f fakeAuthService) ResendVerification(_ context.Context, userID string) error {
+	if userID == "verified" {
+		return service.ErrEmailAlreadyVerified
+	}
+	return nil
+}
+
+func (f fakeAuthService) ConfirmEmailChange(_ context.Context, input service.ConfirmEmailChangeInput) error {
+	if input.Token != "valid" {
+		return service.ErrInvalidToken
+	}
+	return nil
+}
+
+func (f fakeAuthService) VerifyMFA(_ context.Context, input service.VerifyMFAInput) (service.RegisteredUser, service.TokenPair, error) {
+	if input.MFAToken != "challenge" {
+		return service.RegisteredUser{}, service.TokenPair{}, service.ErrInvalidToken
+	}
+	if input.Code != "123456" {
+		return service.RegisteredUser{}, service.TokenPair{}, service.ErrInvalidMFACode
+	}
+	return service.RegisteredUser{ID: "u1"}, service.TokenPair{AccessToken: "a", RefreshToken: "r", TokenType: "Bearer"}, nil
+}
+
+func (f fakeAuthService) TwoFactorStatus(_ context.Context, _ string) (service.TwoFactorStatus, error) {
+	return service.TwoFactorStatus{}, nil
+}
+
+func (f fakeAuthService) SetupTOTP(_ context.Context, _ string) (service.TOTPSetup, error) {
+	return service.TOTPSetup{Secret: "SECRET", OTPAuthURI: "otpauth://totp/Blog:u1?secret=SECRET"}, nil
+}
+
+func (f fakeAuthService) EnableTOTP(_ context.Context, input service.EnableTOTPInput) ([]string, error) {
+	if input.Code != "123456" {
+		return nil, service.ErrInvalidMFACode
+	}
+	return []string{"aaaaa-bbbbb"}, nil
+}
+
+func (f fakeAuthService) DisableTOTP(_ context.Context, _ service.DisableTOTPInput) error {
+	return service.ErrTwoFactorNotEnabled
+}
+
 func TestAuthRegisterSuccess(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -81,3 +162,204 @@ func TestAuthLoginInvalidCredentials(t *testing.T) {
 		t.Fatalf("expected status 401, got %d", w.Code)
 	}
 }
+
+func TestAuthLoginLocks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewAuthHandler(fakeAuthService{})
+	r.POST("/login", h.Login)
+
+	login := func(email string) *httptest.ResponseRecorder {
+		body := `{"email":"` + email + `","password":"whatever"}`
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	locked := login("locked@example.com")
+	wrong := login("test@example.com")
+	if locked.Code != http.StatusUnauthorized || locked.Body.String() != wrong.Body.String() {
+		t.Fatalf("expected a locked account to answer like bad credentials, got %d %s vs %s", locked.Code, locked.Body.String(), wrong.Body.String())
+	}
+	if got := locked.Header().Get("Retry-After"); got != "" {
+		t.Fatalf("expected no Retry-After on login, got %q", got)
+	}
+
+	blocked := login("blocked-ip@example.com")
+	if blocked.Code != http.StatusLocked || blocked.Header().Get("Retry-After") != "90" || !strings.Contains(blocked.Body.String(), `"account_locked"`) {
+		t.Fatalf("expected a locked client IP to get 423 account_locked, got %d %s", blocked.Code, blocked.Body.String())
+	}
+}
+
+func TestAuthPasswordCheckAccountLocked(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewAuthHandler(fakeAuthService{})
+	verifier := fakeVerifier{claims: &auth.AccessClaims{Role: "reader", SessionID: "s1", RegisteredClaims: jwt.RegisteredClaims{Subject: "u1"}}}
+	r.POST("/me/password", AuthRequired(verifier), h.ChangePassword)
+
+	body := `{"current_password":"locked","new_password":"battery-staple"}`
+	req := httptest.NewRequest(http.MethodPost, "/me/password", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer test")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusLocked {
+		t.Fatalf("expected status 423, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "90" {
+		t.Fatalf("expected Retry-After 90, got %q", got)
+	}
+
+	var payload map[string]map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("expected valid json response: %v", err)
+	}
+	if payload["error"]["code"] != "account_locked" {
+		t.Fatalf("expected account_locked code, got %v", payload)
+	}
+}
+
+func TestAuthAccountChangeRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewAuthHandler(fakeAuthService{})
+	verifier := fakeVerifier{claims: &auth.AccessClaims{Role: "reader", SessionID: "s1", RegisteredClaims: jwt.RegisteredClaims{Subject: "u1"}}}
+	r.POST("/me/password", AuthRequired(verifier), h.ChangePassword)
+	r.POST("/me/email", AuthRequired(verifier), h.RequestEmailChange)
+	r.POST("/email-change/confirm", h.ConfirmEmailChange)
+
+	cases := []struct {
+		path string
+		body string
+		want int
+	}{
+		{"/me/password", `{"current_password":"old-password","new_password":"new-password"}`, http.StatusOK},
+		{"/me/password", `{"current_password":"wrong","new_password":"new-password"}`, http.StatusForbidden},
+		{"/me/password", `{"current_password":"old-password","new_password":"short"}`, http.StatusBadRequest},
+		{"/me/email", `{"current_password":"old-password","new_email":"new@example.com"}`, http.StatusAccepted},
+		{"/me/email", `{"current_password":"old-password","new_email":"taken@example.com"}`, http.StatusConflict},
+		{"/email-change/confirm", `{"token":"valid"}`, http.StatusOK},
+		{"/email-change/confirm", `{"token":"stale"}`, http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test")
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.want {
+			t.Fatalf("%s %s: expected status %d, got %d", tc.path, tc.body, tc.want, w.Code)
+		}
+	}
+}
+
+func TestAuthVerifyEmailRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewAuthHandler(fakeAuthService{})
+	r.POST("/verify-email/confirm", h.VerifyEmail)
+	for _, subject := range []string{"u1", "verified"} {
+		verifier := fakeVerifier{claims: &auth.AccessClaims{Role: "author", RegisteredClaims: jwt.RegisteredClaims{Subject: subject}}}
+		r.POST("/"+subject+"/verify-email/resend", AuthRequired(verifier), h.ResendVerification)
+	}
+
+	cases := []struct {
+		path string
+		body string
+		want int
+	}{
+		{"/verify-email/confirm", `{"token":"valid"}`, http.StatusOK},
+		{"/verify-email/confirm", `{"token":"stale"}`, http.StatusUnauthorized},
+		{"/u1/verify-email/resend", ``, http.StatusAccepted},
+		{"/verified/verify-email/resend", ``, http.StatusConflict},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test")
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.want {
+			t.Fatalf("%s %s: expected status %d, got %d", tc.path, tc.body, tc.want, w.Code)
+		}
+	}
+}
+
+func TestAuthLoginAsksForSecondFactor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewAuthHandler(fakeAuthService{})
+	r.POST("/login", h.Login)
+	r.POST("/login/mfa", h.VerifyMFA)
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"email":"mfa@example.com","password":"whatever"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var payload map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("expected valid json response: %v", err)
+	}
+	if w.Code != http.StatusOK || payload["mfa_required"] != true || payload["mfa_token"] != "challenge" || payload["tokens"] != nil {
+		t.Fatalf("expected an mfa challenge instead of tokens, got %d %v", w.Code, payload)
+	}
+
+	cases := []struct {
+		body string
+		want int
+	}{
+		{`{"mfa_token":"challenge","code":"123456"}`, http.StatusOK},
+		{`{"mfa_token":"challenge","code":"000000"}`, http.StatusUnauthorized},
+		{`{"mfa_token":"forged","code":"123456"}`, http.StatusUnauthorized},
+		{`{"code":"123456"}`, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/login/mfa", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.want {
+			t.Fatalf("%s: expected status %d, got %d", tc.body, tc.want, w.Code)
+		}
+	}
+}
+
+func TestAuthTwoFactorRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewAuthHandler(fakeAuthService{})
+	verifier := fakeVerifier{claims: &auth.AccessClaims{Role: "admin", RegisteredClaims: jwt.RegisteredClaims{Subject: "u1"}}}
+	r.POST("/me/2fa/setup", AuthRequired(verifier), h.SetupTOTP)
+	r.POST("/me/2fa/enable", AuthRequired(verifier), h.EnableTOTP)
+	r.POST("/me/2fa/disable", AuthRequired(verifier), h.DisableTOTP)
+
+	cases := []struct {
+		path string
+		body string
+		want int
+	}{
+		{"/me/2fa/setup", ``, http.StatusOK},
+		{"/me/2fa/enable", `{"code":"123456"}`, http.StatusOK},
+		{"/me/2fa/enable", `{"code":"000000"}`, http.StatusUnauthorized},
+		{"/me/2fa/disable", `{"current_password":"pw","code":"123456"}`, http.StatusConflict},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test")
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.want {
+			t.Fatalf("%s %s: expected status %d, got %d", tc.path, tc.body, tc.want, w.Code)
+		}
+	}
+}
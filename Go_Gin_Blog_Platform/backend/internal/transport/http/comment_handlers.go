@@ -0,0 +1,198 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type CommentService interface {
+	Create(ctx context.Context, input service.CreateCommentInput) (service.CommentItem, error)
+	List(ctx context.Context, input service.ListCommentsInput) (service.CommentThread, error)
+	Update(ctx context.Context, input service.UpdateCommentInput) (service.CommentItem, error)
+	Delete(ctx context.Context, input service.DeleteCommentInput) error
+	Moderate(ctx context.Context, input service.ModerateCommentInput) (service.CommentItem, error)
+	ListModerationQueue(ctx context.Context, input service.ListModerationQueueInput) ([]service.CommentItem, service.Pagination, error)
+}
+
+type CommentHandler struct {
+	commentService CommentService
+}
+
+func NewCommentHandler(commentService CommentService) *CommentHandler {
+	return &CommentHandler{commentService: commentService}
+}
+
+type createCommentRequest struct {
+	Body     string  `json:"body" binding:"required"`
+	ParentID *string `json:"parent_id"`
+}
+
+type updateCommentRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+type moderateCommentRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+func (h *CommentHandler) List(c *gin.Context) {
+	viewerID, viewerRole, _ := currentUserFromContext(c)
+
+	thread, err := h.commentService.List(c.Request.Context(), service.ListCommentsInput{
+		PostID:     c.Param("id"),
+		ViewerID:   viewerID,
+		ViewerRole: viewerRole,
+		Status:     c.Query("status"),
+	})
+	if err != nil {
+		handleCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": thread.Comments, "truncated": thread.Truncated})
+}
+
+func (h *CommentHandler) Create(c *gin.Context) {
+	var req createCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
+	actorID, actorRole, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	comment, err := h.commentService.Create(c.Request.Context(), service.CreateCommentInput{
+		PostID:    c.Param("id"),
+		ParentID:  req.ParentID,
+		ActorID:   actorID,
+		ActorRole: actorRole,
+		Body:      req.Body,
+	})
+	if err != nil {
+		handleCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": comment})
+}
+
+func (h *CommentHandler) Update(c *gin.Context) {
+	var req updateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
+	actorID, actorRole, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	comment, err := h.commentService.Update(c.Request.Context(), service.UpdateCommentInput{
+		CommentID: c.Param("id"),
+		ActorID:   actorID,
+		ActorRole: actorRole,
+		Body:      req.Body,
+	})
+	if err != nil {
+		handleCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": comment})
+}
+
+func (h *CommentHandler) Delete(c *gin.Context) {
+	actorID, actorRole, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	if err := h.commentService.Delete(c.Request.Context(), service.DeleteCommentInput{
+		CommentID: c.Param("id"),
+		ActorID:   actorID,
+		ActorRole: actorRole,
+	}); err != nil {
+		handleCommentError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *CommentHandler) Moderate(c *gin.Context) {
+	var req moderateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
+	actorID, actorRole, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	comment, err := h.commentService.Moderate(c.Request.Context(), service.ModerateCommentInput{
+		CommentID: c.Param("id"),
+		ActorID:   actorID,
+		ActorRole: actorRole,
+		Status:    req.Status,
+	})
+	if err != nil {
+		handleCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": comment})
+}
+
+func (h *CommentHandler) ListModerationQueue(c *gin.Context) {
+	_, actorRole, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	comments, pagination, err := h.commentService.ListModerationQueue(c.Request.Context(), service.ListModerationQueueInput{
+		ActorRole: actorRole,
+		Status:    c.Query("status"),
+		Page:      page,
+		Limit:     limit,
+	})
+	if err != nil {
+		handleCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": comments, "meta": pagination})
+}
+
+func handleCommentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		writeError(c, http.StatusBadRequest, "validation_error", "Request validation failed", gin.H{"reason": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		writeError(c, http.StatusForbidden, "forbidden", "Insufficient permissions", nil)
+	case errors.Is(err, service.ErrPostNotFound):
+		writeError(c, http.StatusNotFound, "post_not_found", "Post was not found", nil)
+	case errors.Is(err, service.ErrCommentNotFound):
+		writeError(c, http.StatusNotFound, "comment_not_found", "Comment was not found", nil)
+	default:
+		writeError(c, http.StatusInternalServerError, "internal_error", "Unexpected server error", nil)
+	}
+}
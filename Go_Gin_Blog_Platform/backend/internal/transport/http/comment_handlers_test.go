@@ -0,0 +1,182 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type fakeCommentService struct {
+	lastList  *service.ListCommentsInput
+	lastQueue *service.ListModerationQueueInput
+}
+
+func (f fakeCommentService) Create(_ context.Context, input service.CreateCommentInput) (service.CommentItem, error) {
+	return service.CommentItem{ID: "c1", PostID: input.PostID, ParentID: input.ParentID, AuthorID: input.ActorID, Body: input.Body, Status: models.CommentStatusPending}, nil
+}
+
+func (f fakeCommentService) List(_ context.Context, input service.ListCommentsInput) (service.CommentThread, error) {
+	if f.lastList != nil {
+		*f.lastList = input
+	}
+	if input.PostID == "missing" {
+		return service.CommentThread{}, service.ErrPostNotFound
+	}
+	reply := service.CommentNode{CommentItem: service.CommentItem{ID: "c2", Body: "reply"}, Replies: []service.CommentNode{}}
+	return service.CommentThread{Comments: []service.CommentNode{{CommentItem: service.CommentItem{ID: "c1", Body: "root"}, Replies: []service.CommentNode{reply}}}, Truncated: true}, nil
+}
+
+func (f fakeCommentService) Update(_ context.Context, input service.UpdateCommentInput) (service.CommentItem, error) {
+	if input.ActorID != "u1" {
+		return service.CommentItem{}, service.ErrForbidden
+	}
+	return service.CommentItem{ID: input.CommentID, AuthorID: input.ActorID, Body: input.Body, Status: models.CommentStatusPending}, nil
+}
+
+func (f fakeCommentService) Delete(_ context.Context, input service.DeleteCommentInput) error {
+	if input.CommentID == "missing" {
+		return service.ErrCommentNotFound
+	}
+	return nil
+}
+
+func (f fakeCommentService) Moderate(_ context.Context, input service.ModerateCommentInput) (service.CommentItem, error) {
+	if input.Status != "approved" && input.Status != "spam" {
+		return service.CommentItem{}, service.ErrValidation
+	}
+	return service.CommentItem{ID: input.CommentID, Status: models.CommentStatus(input.Status)}, nil
+}
+
+func (f fakeCommentService) ListModerationQueue(_ context.Context, input service.ListModerationQueueInput) ([]service.CommentItem, service.Pagination, error) {
+	if f.lastQueue != nil {
+		*f.lastQueue = input
+	}
+	return []service.CommentItem{{ID: "c1", Status: models.CommentStatusPending}}, service.Pagination{Page: 1, Limit: 10, Total: 1, TotalPages: 1}, nil
+}
+
+func TestCommentsListReturnsNestedThread(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	var captured service.ListCommentsInput
+	h := NewCommentHandler(fakeCommentService{lastList: &captured})
+	r.GET("/posts/:id/comments", OptionalAuth(fakeVerifier{}), h.List)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/posts/p1/comments", nil)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var payload struct {
+		Data []struct {
+			ID      string `json:"id"`
+			Replies []struct {
+				ID string `json:"id"`
+			} `json:"replies"`
+		} `json:"data"`
+		Truncated bool `json:"truncated"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("expected json response: %v", err)
+	}
+	if len(payload.Data) != 1 || len(payload.Data[0].Replies) != 1 || payload.Data[0].Replies[0].ID != "c2" || !payload.Truncated {
+		t.Fatalf("unexpected thread: %s", w.Body.String())
+	}
+	if captured.PostID != "p1" || captured.ViewerID != "" {
+		t.Fatalf("unexpected list input: %+v", captured)
+	}
+}
+
+func TestCommentsCreateRequiresAuthAndBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewCommentHandler(fakeCommentService{})
+	verifier := fakeVerifier{claims: &auth.AccessClaims{Role: "reader", RegisteredClaims: jwt.RegisteredClaims{Subject: "u1"}}}
+	r.POST("/posts/:id/comments", AuthRequired(verifier), h.Create)
+	r.POST("/anonymous/:id/comments", h.Create)
+
+	cases := []struct {
+		path string
+		auth bool
+		body string
+		want int
+	}{
+		{path: "/posts/p1/comments", auth: true, body: `{"body":"Nice","parent_id":"c0"}`, want: http.StatusCreated},
+		{path: "/posts/p1/comments", auth: true, body: `{}`, want: http.StatusBadRequest},
+		{path: "/anonymous/p1/comments", body: `{"body":"Nice"}`, want: http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		if tc.auth {
+			req.Header.Set("Authorization", "Bearer test")
+		}
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.want {
+			t.Fatalf("%s %s: expected status %d, got %d", tc.path, tc.body, tc.want, w.Code)
+		}
+	}
+}
+
+func TestCommentsDeleteMapsNotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewCommentHandler(fakeCommentService{})
+	verifier := fakeVerifier{claims: &auth.AccessClaims{Role: "reader", RegisteredClaims: jwt.RegisteredClaims{Subject: "u1"}}}
+	r.DELETE("/comments/:id", AuthRequired(verifier), h.Delete)
+
+	for path, want := range map[string]int{"/comments/c1": http.StatusNoContent, "/comments/missing": http.StatusNotFound} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, path, nil)
+		req.Header.Set("Authorization", "Bearer test")
+		r.ServeHTTP(w, req)
+
+		if w.Code != want {
+			t.Fatalf("%s: expected status %d, got %d", path, want, w.Code)
+		}
+	}
+}
+
+func TestCommentsModerationQueuePassesStatusAndPage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	var captured service.ListModerationQueueInput
+	h := NewCommentHandler(fakeCommentService{lastQueue: &captured})
+	verifier := fakeVerifier{claims: &auth.AccessClaims{Role: "admin", RegisteredClaims: jwt.RegisteredClaims{Subject: "a1"}}}
+	r.GET("/admin/comments", AuthRequired(verifier), h.ListModerationQueue)
+	r.PATCH("/admin/comments/:id", AuthRequired(verifier), h.Moderate)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/admin/comments?status=spam&page=2", nil)
+	req.Header.Set("Authorization", "Bearer test")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if captured.Status != "spam" || captured.Page != 2 || captured.ActorRole != "admin" {
+		t.Fatalf("unexpected queue input: %+v", captured)
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPatch, "/admin/comments/c1", strings.NewReader(`{"status":"bogus"}`))
+	req.Header.Set("Authorization", "Bearer test")
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for unknown status, got %d", w.Code)
+	}
+}
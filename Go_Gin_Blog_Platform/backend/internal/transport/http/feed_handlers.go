@@ -0,0 +1,178 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/feed"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const feedSize = 20
+
+// Author feeds are addressed by user ID, a UUID; anything else names no author.
+var feedAuthorPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+type FeedPostService interface {
+	List(ctx context.Context, input service.ListPostsInput) ([]service.PostItem, service.Pagination, error)
+}
+
+// FeedHandler links posts under siteURL, the frontend, and gives each feed
+// its own address under apiURL, where the API is publicly reachable.
+type FeedHandler struct {
+	postService FeedPostService
+	siteURL     string
+	apiURL      string
+	title       string
+}
+
+func NewFeedHandler(postService FeedPostService, siteURL, apiURL, title string) *FeedHandler {
+	return &FeedHandler{
+		postService: postService,
+		siteURL:     strings.TrimRight(siteURL, "/"),
+		apiURL:      strings.TrimRight(apiURL, "/"),
+		title:       title,
+	}
+}
+
+func (h *FeedHandler) RSS(c *gin.Context) {
+	h.serve(c, feed.RSS, feed.RSSContentType)
+}
+
+func (h *FeedHandler) Atom(c *gin.Context) {
+	h.serve(c, feed.Atom, feed.AtomContentType)
+}
+
+func (h *FeedHandler) JSON(c *gin.Context) {
+	h.serve(c, feed.JSON, feed.JSONContentType)
+}
+
+// serve builds the feed for the site, or for one author (:id) or tag (:slug)
+// when the route carries those parameters.
+func (h *FeedHandler) serve(c *gin.Context, render func(feed.Feed) ([]byte, error), contentType string) {
+	if author := c.Param("id"); author != "" && !feedAuthorPattern.MatchString(author) {
+		writeError(c, http.StatusNotFound, "user_not_found", "User was not found", nil)
+		return
+	}
+
+	input := service.ListPostsInput{
+		Page:   1,
+		Limit:  feedSize,
+		Author: c.Param("id"),
+		Tag:    c.Param("slug"),
+		Format: service.ContentFormatHTML,
+	}
+	posts, _, err := h.postService.List(c.Request.Context(), input)
+	if err != nil {
+		handlePostError(c, err)
+		return
+	}
+
+	f := feed.Feed{
+		Title:       h.feedTitle(input),
+		Description: "Latest posts from " + h.title,
+		SiteURL:     h.siteURL,
+		FeedURL:     h.apiURL + c.Request.URL.Path,
+		Updated:     time.Unix(0, 0).UTC(),
+	}
+	for _, post := range posts {
+		published := post.CreatedAt
+		if post.PublishAt != nil {
+			published = *post.PublishAt
+		}
+		item := feed.Item{
+			ID:          post.ID,
+			Title:       post.Title,
+			URL:         h.siteURL + "/posts/" + post.Slug,
+			ContentHTML: post.Content,
+			Published:   published,
+			Updated:     post.UpdatedAt,
+		}
+		for _, tag := range post.Tags {
+			item.Tags = append(item.Tags, tag.Name)
+		}
+		if post.UpdatedAt.After(f.Updated) {
+			f.Updated = post.UpdatedAt
+		}
+		f.Items = append(f.Items, item)
+	}
+
+	var lastModified time.Time
+	if len(f.Items) > 0 {
+		lastModified = f.Updated
+	}
+	if notModified(c, feedETag(contentType, f), lastModified) {
+		return
+	}
+
+	body, err := render(f)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, "internal_error", "Unexpected server error", nil)
+		return
+	}
+	c.Data(http.StatusOK, contentType, body)
+}
+
+func (h *FeedHandler) feedTitle(input service.ListPostsInput) string {
+	switch {
+	case input.Tag != "":
+		return fmt.Sprintf("%s: posts tagged %s", h.title, input.Tag)
+	case input.Author != "":
+		return fmt.Sprintf("%s: posts by author %s", h.title, input.Author)
+	default:
+		return h.title
+	}
+}
+
+// feedETag identifies a feed by the posts in it and when each last changed.
+// The newest update time alone would miss a post leaving the feed.
+func feedETag(contentType string, f feed.Feed) string {
+	sum := sha256.New()
+	fmt.Fprintf(sum, "%s\n%s\n%s\n", contentType, f.FeedURL, f.Title)
+	for _, item := range f.Items {
+		fmt.Fprintf(sum, "%s %d\n", item.ID, item.Updated.UnixNano())
+	}
+	return `"` + hex.EncodeToString(sum.Sum(nil)[:16]) + `"`
+}
+
+// notModified sets the validators and answers conditional requests with 304,
+// so pollers only download a feed when it changed and the server only renders
+// it then.
+func notModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=300")
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		if etagMatches(match, etag) {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	} else if since := c.GetHeader("If-Modified-Since"); since != "" && !lastModified.IsZero() {
+		// If-None-Match takes precedence; dates only count when it is absent.
+		if t, err := http.ParseTime(since); err == nil && !lastModified.Truncate(time.Second).After(t) {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
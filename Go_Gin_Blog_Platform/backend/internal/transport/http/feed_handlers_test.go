@@ -0,0 +1,181 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const feedAuthorID = "7f9c2e4a-1b3d-4c5e-8f60-a1b2c3d4e5f6"
+
+type fakeFeedPosts struct {
+	last  *service.ListPostsInput
+	posts *[]service.PostItem
+}
+
+func (f fakeFeedPosts) List(_ context.Context, input service.ListPostsInput) ([]service.PostItem, service.Pagination, error) {
+	if f.last != nil {
+		*f.last = input
+	}
+	if f.posts != nil {
+		return *f.posts, service.Pagination{Page: 1, Limit: 20, Total: int64(len(*f.posts)), TotalPages: 1}, nil
+	}
+	updated := time.Date(2026, 2, 3, 11, 30, 0, 0, time.UTC)
+	return []service.PostItem{{
+		ID:        "p1",
+		Title:     "Hello",
+		Slug:      "hello",
+		Content:   "<p>World</p>",
+		Format:    service.ContentFormatHTML,
+		Status:    models.PostStatusPublished,
+		Tags:      []service.TagRef{{Name: "Go", Slug: "go"}},
+		CreatedAt: updated.Add(-time.Hour),
+		UpdatedAt: updated,
+	}}, service.Pagination{Page: 1, Limit: 20, Total: 1, TotalPages: 1}, nil
+}
+
+func newFeedRouter(posts FeedPostService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewFeedHandler(posts, "https://blog.example.com/", "https://api.example.com/", "Example Blog")
+	r.GET("/api/v1/feeds/rss.xml", h.RSS)
+	r.GET("/api/v1/feeds/tags/:slug/atom.xml", h.Atom)
+	r.GET("/api/v1/feeds/authors/:id/feed.json", h.JSON)
+	return r
+}
+
+func TestFeedRSSUsesAbsoluteLinksAndValidators(t *testing.T) {
+	r := newFeedRouter(fakeFeedPosts{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/feeds/rss.xml", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/rss+xml") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	if w.Header().Get("ETag") == "" {
+		t.Fatalf("expected ETag header")
+	}
+	if lm := w.Header().Get("Last-Modified"); lm != "Tue, 03 Feb 2026 11:30:00 GMT" {
+		t.Fatalf("unexpected Last-Modified %q", lm)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		"<link>https://blog.example.com/posts/hello</link>",
+		`<atom:link href="https://api.example.com/api/v1/feeds/rss.xml" rel="self"`,
+		"<category>Go</category>",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected feed to contain %q\n%s", want, body)
+		}
+	}
+}
+
+func TestFeedConditionalRequestsReturnNotModified(t *testing.T) {
+	r := newFeedRouter(fakeFeedPosts{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/feeds/rss.xml", nil))
+	etag := w.Header().Get("ETag")
+
+	cases := []struct {
+		name   string
+		header string
+		value  string
+		want   int
+	}{
+		{name: "matching etag", header: "If-None-Match", value: etag, want: http.StatusNotModified},
+		{name: "weak matching etag in list", header: "If-None-Match", value: `"other", W/` + etag, want: http.StatusNotModified},
+		{name: "stale etag", header: "If-None-Match", value: `"stale"`, want: http.StatusOK},
+		{name: "not modified since", header: "If-Modified-Since", value: "Tue, 03 Feb 2026 11:30:00 GMT", want: http.StatusNotModified},
+		{name: "modified since", header: "If-Modified-Since", value: "Tue, 03 Feb 2026 11:29:59 GMT", want: http.StatusOK},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/feeds/rss.xml", nil)
+		req.Header.Set(tc.header, tc.value)
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.want {
+			t.Fatalf("%s: expected status %d, got %d", tc.name, tc.want, w.Code)
+		}
+		if tc.want == http.StatusNotModified && w.Body.Len() != 0 {
+			t.Fatalf("%s: expected empty body on 304", tc.name)
+		}
+	}
+}
+
+func TestFeedVariantsFilterByTagAndAuthor(t *testing.T) {
+	var captured service.ListPostsInput
+	r := newFeedRouter(fakeFeedPosts{last: &captured})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/feeds/tags/go/atom.xml", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if captured.Tag != "go" || captured.Author != "" || captured.Format != service.ContentFormatHTML {
+		t.Fatalf("unexpected tag feed input: %+v", captured)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/feeds/authors/"+feedAuthorID+"/feed.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if captured.Author != feedAuthorID || captured.Tag != "" {
+		t.Fatalf("unexpected author feed input: %+v", captured)
+	}
+	if !strings.Contains(w.Body.String(), `"feed_url": "https://api.example.com/api/v1/feeds/authors/`+feedAuthorID+`/feed.json"`) {
+		t.Fatalf("expected absolute feed_url, got %s", w.Body.String())
+	}
+
+	captured = service.ListPostsInput{}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/feeds/authors/u1/feed.json", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected a non-UUID author to be 404, got %d", w.Code)
+	}
+	if captured.Author != "" {
+		t.Fatalf("expected no listing for a non-UUID author, got %+v", captured)
+	}
+}
+
+func TestFeedETagChangesWhenAPostLeaves(t *testing.T) {
+	updated := time.Date(2026, 2, 3, 11, 30, 0, 0, time.UTC)
+	posts := []service.PostItem{
+		{ID: "p2", Title: "Newer", Slug: "newer", Status: models.PostStatusPublished, CreatedAt: updated, UpdatedAt: updated},
+		{ID: "p1", Title: "Older", Slug: "older", Status: models.PostStatusPublished, CreatedAt: updated.Add(-time.Hour), UpdatedAt: updated.Add(-time.Hour)},
+	}
+	r := newFeedRouter(fakeFeedPosts{posts: &posts})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/feeds/rss.xml", nil))
+	etag, lastModified := w.Header().Get("ETag"), w.Header().Get("Last-Modified")
+
+	posts = posts[:1]
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/feeds/rss.xml", nil)
+	req.Header.Set("If-None-Match", etag)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected the feed without p1 to be sent again, got %d", w.Code)
+	}
+	if w.Header().Get("Last-Modified") != lastModified || w.Header().Get("ETag") == etag {
+		t.Fatalf("expected the same Last-Modified and a new ETag, got %q and %q", w.Header().Get("Last-Modified"), w.Header().Get("ETag"))
+	}
+	if strings.Contains(w.Body.String(), "/posts/older") {
+		t.Fatalf("expected p1 to be gone from the feed:\n%s", w.Body.String())
+	}
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSProvider interface {
+	JWKS() auth.JWKSet
+}
+
+type JWKSHandler struct {
+	keys JWKSProvider
+}
+
+// NewJWKSHandler serves the public signing keys. keys is nil in HS256 mode,
+// where there is nothing to publish and the set is empty.
+func NewJWKSHandler(keys JWKSProvider) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+func (h *JWKSHandler) Get(c *gin.Context) {
+	set := auth.JWKSet{Keys: []auth.JWK{}}
+	if h.keys != nil {
+		set = h.keys.JWKS()
+	}
+
+	// Short enough that verifiers see a new key well before the previous one
+	// is retired.
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, set)
+}
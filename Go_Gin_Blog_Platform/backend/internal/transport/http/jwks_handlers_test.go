@@ -0,0 +1,61 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
+)
+
+func TestJWKSPublishesRingKeys(t *testing.T) {
+	key, err := auth.GenerateSigningKey(auth.AlgEdDSA)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	key.Active = true
+	ring := auth.NewKeyRing(func(context.Context) ([]auth.SigningKey, error) {
+		return []auth.SigningKey{*key}, nil
+	})
+	if err := ring.Reload(context.Background()); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+
+	for name, tc := range map[string]struct {
+		provider JWKSProvider
+		kids     []string
+	}{
+		"key ring": {provider: ring, kids: []string{key.ID}},
+		"hs256":    {provider: nil, kids: []string{}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := NewRouter(slog.Default(), RouterDependencies{JWKSHandler: NewJWKSHandler(tc.provider)})
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", w.Code)
+			}
+			if w.Header().Get("Cache-Control") == "" {
+				t.Fatalf("expected the key set to be cacheable")
+			}
+			var set struct {
+				Keys []map[string]string `json:"keys"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &set); err != nil || set.Keys == nil {
+				t.Fatalf("expected a keys array, got %s (%v)", w.Body.String(), err)
+			}
+			if len(set.Keys) != len(tc.kids) {
+				t.Fatalf("expected %d keys, got %s", len(tc.kids), w.Body.String())
+			}
+			for i, kid := range tc.kids {
+				if set.Keys[i]["kid"] != kid || set.Keys[i]["kty"] != "OKP" {
+					t.Fatalf("unexpected key %v", set.Keys[i])
+				}
+			}
+		})
+	}
+}
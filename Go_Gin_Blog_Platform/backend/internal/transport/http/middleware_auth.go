@@ -1,24 +1,45 @@
 package http
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/policy"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
 const (
-	ContextKeyUserID = "auth_user_id"
-	ContextKeyRole   = "auth_role"
+	ContextKeyUserID        = "auth_user_id"
+	ContextKeyRole          = "auth_role"
+	ContextKeySessionID     = "auth_session_id"
+	ContextKeyEmailVerified = "auth_email_verified"
+	ContextKeyMFA           = "auth_mfa"
+	// ContextKeyScopes is only set for requests made with a personal access
+	// token.
+	ContextKeyScopes = "auth_scopes"
 )
 
 type AccessTokenVerifier interface {
 	ParseAccessToken(token string) (*auth.AccessClaims, error)
 }
 
+type PersonalAccessTokenAuthenticator interface {
+	Authenticate(ctx context.Context, raw string) (service.TokenPrincipal, error)
+}
+
+// AuthRequired accepts access tokens only.
 func AuthRequired(verifier AccessTokenVerifier) gin.HandlerFunc {
+	return AuthRequiredWithTokens(verifier, nil)
+}
+
+// AuthRequiredWithTokens also accepts personal access tokens holding every one
+// of scopes. With no scopes listed, personal access tokens are refused, so a
+// route has to opt in to automation explicitly.
+func AuthRequiredWithTokens(verifier AccessTokenVerifier, tokens PersonalAccessTokenAuthenticator, scopes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if verifier == nil {
 			writeError(c, http.StatusServiceUnavailable, "auth_unavailable", "Authentication is not configured", nil)
@@ -26,17 +47,15 @@ func AuthRequired(verifier AccessTokenVerifier) gin.HandlerFunc {
 			return
 		}
 
-		header := c.GetHeader("Authorization")
-		if !strings.HasPrefix(strings.ToLower(header), "bearer ") {
+		rawToken, ok := bearerToken(c)
+		if !ok {
 			writeError(c, http.StatusUnauthorized, "missing_token", "Authorization token is required", nil)
 			c.Abort()
 			return
 		}
 
-		rawToken := strings.TrimSpace(header[len("Bearer "):])
-		if rawToken == "" {
-			writeError(c, http.StatusUnauthorized, "missing_token", "Authorization token is required", nil)
-			c.Abort()
+		if tokens != nil && strings.HasPrefix(rawToken, service.PersonalAccessTokenPrefix) {
+			authenticatePersonalAccessToken(c, tokens, rawToken, scopes)
 			return
 		}
 
@@ -53,37 +72,159 @@ func AuthRequired(verifier AccessTokenVerifier) gin.HandlerFunc {
 
 		c.Set(ContextKeyUserID, claims.Subject)
 		c.Set(ContextKeyRole, claims.Role)
+		c.Set(ContextKeySessionID, claims.SessionID)
+		c.Set(ContextKeyEmailVerified, claims.EmailVerified)
+		c.Set(ContextKeyMFA, claims.MFA)
 		c.Next()
 	}
 }
 
-func RequireRoles(roles ...string) gin.HandlerFunc {
-	allowed := make(map[string]struct{}, len(roles))
-	for _, role := range roles {
-		allowed[strings.ToLower(strings.TrimSpace(role))] = struct{}{}
+func authenticatePersonalAccessToken(c *gin.Context, tokens PersonalAccessTokenAuthenticator, rawToken string, scopes []string) {
+	principal, err := tokens.Authenticate(c.Request.Context(), rawToken)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			writeError(c, http.StatusUnauthorized, "invalid_token", "Personal access token is invalid, revoked or expired", nil)
+		} else {
+			writeError(c, http.StatusInternalServerError, "internal_error", "Unexpected server error", nil)
+		}
+		c.Abort()
+		return
 	}
 
-	return func(c *gin.Context) {
-		value, exists := c.Get(ContextKeyRole)
-		if !exists {
-			writeError(c, http.StatusForbidden, "forbidden", "Insufficient permissions", nil)
+	if len(scopes) == 0 {
+		writeError(c, http.StatusForbidden, "insufficient_scope", "Personal access tokens cannot be used for this endpoint", nil)
+		c.Abort()
+		return
+	}
+	for _, scope := range scopes {
+		if !service.ScopeGranted(principal.Scopes, scope) {
+			writeError(c, http.StatusForbidden, "insufficient_scope", "Personal access token is missing a required scope", gin.H{"required_scopes": scopes})
 			c.Abort()
 			return
 		}
+	}
 
-		role, ok := value.(string)
-		if !ok {
+	c.Set(ContextKeyUserID, principal.UserID)
+	c.Set(ContextKeyRole, string(principal.Role))
+	c.Set(ContextKeyEmailVerified, principal.EmailVerified)
+	c.Set(ContextKeyMFA, principal.MFA)
+	c.Set(ContextKeyScopes, principal.Scopes)
+	c.Next()
+}
+
+// OptionalAuth attaches the caller identity when a valid bearer token is sent and
+// otherwise lets the request through anonymously, for routes whose response
+// depends on who is asking.
+func OptionalAuth(verifier AccessTokenVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawToken, ok := bearerToken(c)
+		if !ok || verifier == nil {
+			c.Next()
+			return
+		}
+
+		claims, err := verifier.ParseAccessToken(rawToken)
+		if err == nil {
+			c.Set(ContextKeyUserID, claims.Subject)
+			c.Set(ContextKeyRole, claims.Role)
+		}
+		c.Next()
+	}
+}
+
+func bearerToken(c *gin.Context) (string, bool) {
+	header := c.GetHeader("Authorization")
+	if !strings.HasPrefix(strings.ToLower(header), "bearer ") {
+		return "", false
+	}
+
+	rawToken := strings.TrimSpace(header[len("Bearer "):])
+	if rawToken == "" {
+		return "", false
+	}
+	return rawToken, true
+}
+
+// RolePolicy adds requirements on top of the permission check in
+// RequirePermission.
+type RolePolicy struct {
+	// VerifiedEmailRoles lists roles that are refused until the caller's email
+	// address is verified.
+	VerifiedEmailRoles []string
+	// MFARoles lists roles that are refused unless the caller's session was
+	// signed in with a second factor.
+	MFARoles []string
+	// MFAPermissions extends MFARoles to every role that holds any of these
+	// permissions, as the policy currently grants them.
+	MFAPermissions []policy.Permission
+}
+
+type Authorizer interface {
+	Authorize(actor policy.Actor, action policy.Permission, resource *policy.Resource) bool
+}
+
+// RequirePermission lets the caller through if their role holds any of
+// permissions and meets rolePolicy.
+func RequirePermission(authorizer Authorizer, rolePolicy RolePolicy, permissions ...policy.Permission) gin.HandlerFunc {
+	needsVerifiedEmail := roleSet(rolePolicy.VerifiedEmailRoles)
+	needsMFA := roleSet(rolePolicy.MFARoles)
+
+	return func(c *gin.Context) {
+		role := c.GetString(ContextKeyRole)
+		if role == "" || authorizer == nil {
 			writeError(c, http.StatusForbidden, "forbidden", "Insufficient permissions", nil)
 			c.Abort()
 			return
 		}
 
-		if _, ok := allowed[strings.ToLower(role)]; !ok {
+		if !holdsAny(authorizer, role, permissions) {
 			writeError(c, http.StatusForbidden, "forbidden", "Insufficient permissions", nil)
 			c.Abort()
 			return
 		}
 
+		_, mfa := needsMFA[strings.ToLower(role)]
+		if !mfa {
+			mfa = holdsAny(authorizer, role, rolePolicy.MFAPermissions)
+		}
+		if !enforceRolePolicy(c, role, needsVerifiedEmail, mfa) {
+			return
+		}
 		c.Next()
 	}
 }
+
+func holdsAny(authorizer Authorizer, role string, permissions []policy.Permission) bool {
+	for _, permission := range permissions {
+		if authorizer.Authorize(policy.Actor{Role: role}, permission, nil) {
+			return true
+		}
+	}
+	return false
+}
+
+// enforceRolePolicy refuses callers whose role needs a verified email, or who
+// need a second factor they do not have, and reports whether the request may
+// go on.
+func enforceRolePolicy(c *gin.Context, role string, needsVerifiedEmail map[string]struct{}, needsMFA bool) bool {
+	if _, ok := needsVerifiedEmail[strings.ToLower(role)]; ok && !c.GetBool(ContextKeyEmailVerified) {
+		writeError(c, http.StatusForbidden, "email_not_verified", "Verify your email address to continue", nil)
+		c.Abort()
+		return false
+	}
+
+	if needsMFA && !c.GetBool(ContextKeyMFA) {
+		writeError(c, http.StatusForbidden, "mfa_required", "Sign in with two-factor authentication to continue", nil)
+		c.Abort()
+		return false
+	}
+	return true
+}
+
+func roleSet(roles []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		set[strings.ToLower(strings.TrimSpace(role))] = struct{}{}
+	}
+	return set
+}
@@ -1,11 +1,15 @@
 package http
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/policy"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
@@ -18,6 +22,59 @@ func (f fakeVerifier) ParseAccessToken(_ string) (*auth.AccessClaims, error) {
 	return f.claims, f.err
 }
 
+type fakeTokenAuthenticator map[string]service.TokenPrincipal
+
+func (f fakeTokenAuthenticator) Authenticate(_ context.Context, raw string) (service.TokenPrincipal, error) {
+	principal, ok := f[raw]
+	if !ok {
+		return service.TokenPrincipal{}, service.ErrInvalidToken
+	}
+	return principal, nil
+}
+
+func TestAuthRequiredWithTokensChecksScopesAndRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tokens := fakeTokenAuthenticator{
+		"bpat_writer":  {UserID: "u1", Role: "author", Scopes: []string{service.ScopePostsWrite}},
+		"bpat_reader":  {UserID: "u1", Role: "author", Scopes: []string{service.ScopePostsRead}},
+		"bpat_demoted": {UserID: "u2", Role: "reader", Scopes: []string{service.ScopePostsWrite}},
+	}
+	r := gin.New()
+	r.GET("/write", AuthRequiredWithTokens(fakeVerifier{claims: &auth.AccessClaims{Role: "author"}}, tokens, service.ScopePostsWrite), RequirePermission(policy.New(policy.DefaultGrants()), RolePolicy{}, policy.PostCreate), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.GET("/read", AuthRequiredWithTokens(fakeVerifier{}, tokens, service.ScopePostsRead), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.GET("/session-only", AuthRequiredWithTokens(fakeVerifier{}, tokens), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	cases := []struct {
+		path, token string
+		want        int
+		wantCode    string
+	}{
+		{"/write", "bpat_writer", http.StatusOK, ""},
+		{"/write", "access-token", http.StatusOK, ""},
+		{"/read", "bpat_writer", http.StatusOK, ""},
+		{"/write", "bpat_reader", http.StatusForbidden, "insufficient_scope"},
+		{"/write", "bpat_demoted", http.StatusForbidden, "forbidden"},
+		{"/write", "bpat_revoked", http.StatusUnauthorized, "invalid_token"},
+		{"/session-only", "bpat_writer", http.StatusForbidden, "insufficient_scope"},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		req.Header.Set("Authorization", "Bearer "+tc.token)
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.want || !strings.Contains(w.Body.String(), tc.wantCode) {
+			t.Fatalf("%s with %s: expected %d %s, got %d %s", tc.path, tc.token, tc.want, tc.wantCode, w.Code, w.Body.String())
+		}
+	}
+}
+
 func TestAuthRequiredRejectsMissingHeader(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -34,20 +91,126 @@ func TestAuthRequiredRejectsMissingHeader(t *testing.T) {
 	}
 }
 
-func TestRequireRolesAcceptsAllowedRole(t *testing.T) {
+func TestRequirePermissionBlocksUnverifiedEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authz := policy.New(policy.DefaultGrants())
+	rolePolicy := RolePolicy{VerifiedEmailRoles: []string{"author"}}
+
+	cases := []struct {
+		claims *auth.AccessClaims
+		want   int
+	}{
+		{&auth.AccessClaims{Role: "author"}, http.StatusForbidden},
+		{&auth.AccessClaims{Role: "author", EmailVerified: true}, http.StatusOK},
+		{&auth.AccessClaims{Role: "editor"}, http.StatusOK},
+	}
+	for _, tc := range cases {
+		r := gin.New()
+		r.GET("/protected", AuthRequired(fakeVerifier{claims: tc.claims}), RequirePermission(authz, rolePolicy, policy.PostCreate), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer test")
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.want {
+			t.Fatalf("%+v: expected status %d, got %d", tc.claims, tc.want, w.Code)
+		}
+		if tc.want == http.StatusForbidden && !strings.Contains(w.Body.String(), "email_not_verified") {
+			t.Fatalf("expected email_not_verified error, got %s", w.Body.String())
+		}
+	}
+}
+
+func TestRequirePermissionChecksThePolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authz := policy.New(policy.DefaultGrants())
+	rolePolicy := RolePolicy{MFARoles: []string{"admin"}}
+
+	cases := []struct {
+		claims *auth.AccessClaims
+		want   int
+	}{
+		{&auth.AccessClaims{Role: "editor"}, http.StatusOK},
+		{&auth.AccessClaims{Role: "author"}, http.StatusForbidden},
+		{&auth.AccessClaims{Role: "admin"}, http.StatusForbidden},
+		{&auth.AccessClaims{Role: "admin", MFA: true}, http.StatusOK},
+	}
+	for _, tc := range cases {
+		r := gin.New()
+		r.GET("/protected", AuthRequired(fakeVerifier{claims: tc.claims}), RequirePermission(authz, rolePolicy, policy.CommentModerate, policy.TaxonomyManage), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer test")
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.want {
+			t.Fatalf("%+v: expected status %d, got %d", tc.claims, tc.want, w.Code)
+		}
+	}
+}
+
+func TestRequirePermissionNeedsMFAForAdministrativeRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	grants := policy.DefaultGrants()
+	grants["auditor"] = []policy.Permission{policy.CommentModerate, policy.AuditRead}
+	authz := policy.New(grants)
+	rolePolicy := RolePolicy{MFAPermissions: policy.AdminPermissions()}
+
+	cases := []struct {
+		claims *auth.AccessClaims
+		want   int
+	}{
+		{&auth.AccessClaims{Role: "editor"}, http.StatusOK},
+		{&auth.AccessClaims{Role: "auditor"}, http.StatusForbidden},
+		{&auth.AccessClaims{Role: "auditor", MFA: true}, http.StatusOK},
+		{&auth.AccessClaims{Role: "admin"}, http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		r := gin.New()
+		r.GET("/protected", AuthRequired(fakeVerifier{claims: tc.claims}), RequirePermission(authz, rolePolicy, policy.CommentModerate), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer test")
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.want {
+			t.Fatalf("%+v: expected status %d, got %d", tc.claims, tc.want, w.Code)
+		}
+		if tc.want == http.StatusForbidden && !strings.Contains(w.Body.String(), "mfa_required") {
+			t.Fatalf("expected mfa_required error, got %s", w.Body.String())
+		}
+	}
+}
+
+func TestOptionalAuthAllowsAnonymous(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
-	claims := &auth.AccessClaims{Role: "author"}
-	r.GET("/protected", AuthRequired(fakeVerifier{claims: claims}), RequireRoles("author"), func(c *gin.Context) {
+	r.GET("/public", OptionalAuth(fakeVerifier{err: auth.ErrInvalidToken}), func(c *gin.Context) {
+		if _, _, ok := currentUserFromContext(c); ok {
+			t.Errorf("expected no identity for invalid token")
+		}
 		c.Status(http.StatusOK)
 	})
 
-	w := httptest.NewRecorder()
-	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
-	req.Header.Set("Authorization", "Bearer test")
-	r.ServeHTTP(w, req)
+	for _, header := range []string{"", "Bearer bad-token"} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/public", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		r.ServeHTTP(w, req)
 
-	if w.Code != http.StatusOK {
-		t.Fatalf("expected status 200, got %d", w.Code)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200 for header %q, got %d", header, w.Code)
+		}
 	}
 }
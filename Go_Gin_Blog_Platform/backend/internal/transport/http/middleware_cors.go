@@ -0,0 +1,150 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const corsPreflightMaxAge = 10 * time.Minute
+
+var (
+	corsAllowedMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions}
+	corsAllowedHeaders = []string{"Authorization", "Content-Type", "If-None-Match", "If-Modified-Since", RequestIDHeader}
+	corsExposedHeaders = []string{
+		"ETag", "Last-Modified", "Location", "Link", "X-Total-Count",
+		"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After",
+		RequestIDHeader,
+	}
+)
+
+type CORSConfig struct {
+	// AllowedOrigins holds exact origins such as https://blog.example.com,
+	// wildcard subdomain patterns such as https://*.example.com, or "*".
+	AllowedOrigins   []string
+	AllowCredentials bool
+}
+
+// CORS answers cross-origin requests from the configured origins. Preflight
+// requests are answered directly, so they work for every route without
+// registering OPTIONS handlers; disallowed origins get no CORS headers.
+func CORS(cfg CORSConfig) gin.HandlerFunc {
+	matcher := newOriginMatcher(cfg.AllowedOrigins)
+	allowMethods := strings.Join(corsAllowedMethods, ", ")
+	allowHeaders := strings.Join(corsAllowedHeaders, ", ")
+	exposeHeaders := strings.Join(corsExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(corsPreflightMaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Add("Vary", "Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		if !matcher.allows(origin) {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if matcher.any && !cfg.AllowCredentials {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			header.Set("Access-Control-Expose-Headers", exposeHeaders)
+			c.Next()
+			return
+		}
+
+		header.Add("Vary", "Access-Control-Request-Method")
+		header.Add("Vary", "Access-Control-Request-Headers")
+		header.Set("Access-Control-Allow-Methods", allowMethods)
+		if requested := c.GetHeader("Access-Control-Request-Headers"); requested != "" {
+			header.Set("Access-Control-Allow-Headers", requested)
+		} else {
+			header.Set("Access-Control-Allow-Headers", allowHeaders)
+		}
+		header.Set("Access-Control-Max-Age", maxAge)
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+type originMatcher struct {
+	any      bool
+	exact    map[string]bool
+	wildcard []originPattern
+}
+
+// originPattern matches https://*.example.com style entries: same scheme and
+// port, and a host that is a strict subdomain of the suffix.
+type originPattern struct {
+	scheme string
+	suffix string
+	port   string
+}
+
+func newOriginMatcher(origins []string) originMatcher {
+	m := originMatcher{exact: make(map[string]bool, len(origins))}
+	for _, raw := range origins {
+		origin := strings.ToLower(strings.TrimRight(strings.TrimSpace(raw), "/"))
+		switch {
+		case origin == "":
+		case origin == "*":
+			m.any = true
+		case strings.Contains(origin, "://*."):
+			scheme, host, _ := strings.Cut(origin, "://*.")
+			hostname, port, _ := strings.Cut(host, ":")
+			m.wildcard = append(m.wildcard, originPattern{scheme: scheme, suffix: "." + hostname, port: port})
+		default:
+			m.exact[origin] = true
+		}
+	}
+	return m
+}
+
+func (m originMatcher) allows(origin string) bool {
+	if m.any {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	if m.exact[origin] {
+		return true
+	}
+	if len(m.wildcard) == 0 {
+		return false
+	}
+
+	parsed, err := url.Parse(origin)
+	if err != nil || parsed.Host == "" || parsed.Path != "" {
+		return false
+	}
+	for _, pattern := range m.wildcard {
+		if parsed.Scheme != pattern.scheme || parsed.Port() != pattern.port {
+			continue
+		}
+		hostname := parsed.Hostname()
+		if strings.HasSuffix(hostname, pattern.suffix) && len(hostname) > len(pattern.suffix) {
+			return true
+		}
+	}
+	return false
+}
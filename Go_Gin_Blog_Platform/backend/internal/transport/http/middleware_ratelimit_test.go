@@ -0,0 +1,132 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Take(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store down")
+}
+
+func newRateLimitedRouter(store ratelimit.Store) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	limit := RateLimit(store,
+		RateLimitRule{Name: "login", Limit: ratelimit.Limit{Requests: 3, Window: time.Minute}, Key: ClientIPKey},
+		RateLimitRule{Name: "login", Limit: ratelimit.Limit{Requests: 2, Window: time.Minute}, Key: AccountEmailKey},
+	)
+	r.POST("/login", limit, func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+	return r
+}
+
+func postLogin(r *gin.Engine, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitPerAccountReturns429WithRetryAfter(t *testing.T) {
+	r := newRateLimitedRouter(ratelimit.NewMemoryStore())
+	body := `{"email":"Alice@Example.com","password":"x"}`
+
+	for i := 0; i < 2; i++ {
+		w := postLogin(r, body)
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d: expected status 200, got %d", i+1, w.Code)
+		}
+		if w.Body.String() != body {
+			t.Fatalf("expected handler to receive the original body, got %q", w.Body.String())
+		}
+		if got := w.Header().Get("X-RateLimit-Limit"); got != "2" {
+			t.Fatalf("expected headers from the tighter account bucket, got limit %q", got)
+		}
+	}
+
+	w := postLogin(r, `{"email":"alice@example.com","password":"y"}`)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" || w.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Fatalf("expected Retry-After and X-RateLimit-Remaining headers, got %v", w.Header())
+	}
+
+	var payload map[string]map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("expected json response: %v", err)
+	}
+	if payload["error"]["code"] != "rate_limited" {
+		t.Fatalf("expected rate_limited error code, got %v", payload)
+	}
+}
+
+func TestRateLimitPerIPAppliesAcrossAccounts(t *testing.T) {
+	r := newRateLimitedRouter(ratelimit.NewMemoryStore())
+
+	for i, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		if w := postLogin(r, `{"email":"`+email+`"}`); w.Code != http.StatusOK {
+			t.Fatalf("request %d: expected status 200, got %d", i+1, w.Code)
+		}
+	}
+	if w := postLogin(r, `{"email":"d@example.com"}`); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected the IP bucket to run out, got %d", w.Code)
+	}
+}
+
+func TestRateLimitPerUserKeysOnAuthenticatedUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	limit := RateLimit(ratelimit.NewMemoryStore(),
+		RateLimitRule{Name: "verify_email", Limit: ratelimit.Limit{Requests: 1, Window: time.Hour}, Key: UserIDKey},
+	)
+	for _, subject := range []string{"u1", "u2"} {
+		verifier := fakeVerifier{claims: &auth.AccessClaims{Role: "reader", RegisteredClaims: jwt.RegisteredClaims{Subject: subject}}}
+		r.POST("/"+subject+"/resend", AuthRequired(verifier), limit, func(c *gin.Context) {
+			c.Status(http.StatusAccepted)
+		})
+	}
+
+	resend := func(subject string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/"+subject+"/resend", nil)
+		req.Header.Set("Authorization", "Bearer test")
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := resend("u1"); code != http.StatusAccepted {
+		t.Fatalf("expected the first resend to pass, got %d", code)
+	}
+	if code := resend("u1"); code != http.StatusTooManyRequests {
+		t.Fatalf("expected a second resend for the same user to be limited, got %d", code)
+	}
+	if code := resend("u2"); code != http.StatusAccepted {
+		t.Fatalf("expected other users to keep their own bucket, got %d", code)
+	}
+}
+
+func TestRateLimitFailsOpenOnStoreError(t *testing.T) {
+	r := newRateLimitedRouter(failingRateLimitStore{})
+	if w := postLogin(r, `{"email":"a@example.com"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected request to pass when the store fails, got %d", w.Code)
+	}
+}
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"regexp"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RequestIDHeader     = "X-Request-ID"
+	ContextKeyRequestID = "request_id"
+)
+
+// Incoming IDs are only reused when they are short and plain, so a client
+// cannot put arbitrary text into logs and the audit trail.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestID tags every request with an ID, reusing the caller's X-Request-ID
+// when it is well formed, and echoes it in the response. The ID and the client
+// IP travel on the request context so services can attribute what they record.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			// Failing to generate one only leaves the request without an ID.
+			id, _ = auth.GenerateRandomToken(16)
+		}
+
+		c.Header(RequestIDHeader, id)
+		c.Set(ContextKeyRequestID, id)
+		c.Request = c.Request.WithContext(service.WithRequestInfo(c.Request.Context(), service.RequestInfo{
+			ID:        id,
+			IPAddress: c.ClientIP(),
+		}))
+		c.Next()
+	}
+}
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestID())
+	var seen service.RequestInfo
+	r.GET("/", func(c *gin.Context) {
+		seen = service.RequestInfoFrom(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	cases := map[string]bool{
+		"":                        false,
+		"abc-123.DEF_456":         true,
+		"has spaces":              false,
+		"line\r\nX-Injected: yes": false,
+	}
+	for incoming, kept := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "198.51.100.7:4242"
+		if incoming != "" {
+			req.Header.Set(RequestIDHeader, incoming)
+		}
+		r.ServeHTTP(w, req)
+
+		id := w.Header().Get(RequestIDHeader)
+		if id == "" || (id == incoming) != kept {
+			t.Fatalf("%q: unexpected request id %q", incoming, id)
+		}
+		if seen.ID != id || seen.IPAddress != "198.51.100.7" {
+			t.Fatalf("%q: expected the request info on the context, got %+v", incoming, seen)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type OIDCService interface {
+	Providers() []string
+	StartLogin(ctx context.Context, provider string) (service.OIDCAuthorization, error)
+	CompleteLogin(ctx context.Context, input service.OIDCCallbackInput) (service.RegisteredUser, service.TokenPair, error)
+}
+
+// oidcStateCookie ties a sign-in to the browser that started it, so a callback
+// carrying someone else's code and state is refused.
+const oidcStateCookie = "oidc_state"
+
+type OIDCHandler struct {
+	oidcService OIDCService
+	// secureCookie marks the state cookie Secure; set it when the API is
+	// served over HTTPS.
+	secureCookie bool
+}
+
+func NewOIDCHandler(oidcService OIDCService, secureCookie bool) *OIDCHandler {
+	return &OIDCHandler{oidcService: oidcService, secureCookie: secureCookie}
+}
+
+type oidcCallbackRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+func (h *OIDCHandler) ListProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"providers": h.oidcService.Providers()})
+}
+
+func (h *OIDCHandler) Start(c *gin.Context) {
+	authorization, err := h.oidcService.StartLogin(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		handleOIDCError(c, err)
+		return
+	}
+
+	// The cookie is scoped to this provider's routes, which the callback
+	// shares with start.
+	maxAge := int(time.Until(authorization.ExpiresAt).Seconds())
+	h.setStateCookie(c, authorization.State, path.Dir(c.Request.URL.Path), maxAge)
+	c.JSON(http.StatusOK, authorization)
+}
+
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	var req oidcCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
+	// The state is single use whatever the outcome.
+	cookieState, _ := c.Cookie(oidcStateCookie)
+	h.setStateCookie(c, "", path.Dir(c.Request.URL.Path), -1)
+	if cookieState == "" || subtle.ConstantTimeCompare([]byte(cookieState), []byte(req.State)) != 1 {
+		handleOIDCError(c, service.ErrInvalidOIDCState)
+		return
+	}
+
+	user, tokenPair, err := h.oidcService.CompleteLogin(c.Request.Context(), service.OIDCCallbackInput{
+		Provider:  c.Param("provider"),
+		Code:      req.Code,
+		State:     req.State,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	if err != nil {
+		if writeMFARequired(c, err) {
+			return
+		}
+		handleOIDCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": user, "tokens": tokenPair})
+}
+
+func (h *OIDCHandler) setStateCookie(c *gin.Context, state, cookiePath string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, state, maxAge, cookiePath, "", h.secureCookie, true)
+}
+
+func handleOIDCError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrOIDCProviderNotFound):
+		writeError(c, http.StatusNotFound, "oidc_provider_not_found", "Sign-in provider was not found", nil)
+	case errors.Is(err, service.ErrInvalidOIDCState):
+		writeError(c, http.StatusBadRequest, "invalid_oidc_state", "Sign-in request is invalid or expired, start again", nil)
+	case errors.Is(err, service.ErrOIDCAuthenticationFailed):
+		writeError(c, http.StatusUnauthorized, "oidc_authentication_failed", "Sign-in with the provider failed", nil)
+	case errors.Is(err, service.ErrOIDCProviderUnavailable):
+		writeError(c, http.StatusBadGateway, "oidc_provider_unavailable", "Sign-in provider is unavailable", nil)
+	case errors.Is(err, service.ErrOIDCEmailRequired):
+		writeError(c, http.StatusUnprocessableEntity, "oidc_email_required", "Sign-in provider did not share an email address", nil)
+	case errors.Is(err, service.ErrOIDCAccountConflict):
+		writeError(c, http.StatusConflict, "oidc_account_conflict", "An account with this email exists; sign in with your password and verify your email first", nil)
+	default:
+		handleAuthError(c, err)
+	}
+}
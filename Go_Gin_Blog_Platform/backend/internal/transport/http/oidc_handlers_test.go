@@ -0,0 +1,116 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type fakeOIDCService struct{}
+
+func (fakeOIDCService) Providers() []string {
+	return []string{"google"}
+}
+
+func (fakeOIDCService) StartLogin(_ context.Context, provider string) (service.OIDCAuthorization, error) {
+	if provider != "google" {
+		return service.OIDCAuthorization{}, service.ErrOIDCProviderNotFound
+	}
+	return service.OIDCAuthorization{AuthorizationURL: "https://accounts.example.com/authorize?state=s1", State: "s1", ExpiresAt: time.Now().Add(10 * time.Minute)}, nil
+}
+
+func (fakeOIDCService) CompleteLogin(_ context.Context, input service.OIDCCallbackInput) (service.RegisteredUser, service.TokenPair, error) {
+	switch input.State {
+	case "s1":
+		return service.RegisteredUser{ID: "u1", Email: "bob@example.com"}, service.TokenPair{AccessToken: "access"}, nil
+	case "mfa":
+		return service.RegisteredUser{}, service.TokenPair{}, &service.MFARequiredError{Token: "challenge"}
+	case "taken":
+		return service.RegisteredUser{}, service.TokenPair{}, service.ErrOIDCAccountConflict
+	default:
+		return service.RegisteredUser{}, service.TokenPair{}, service.ErrInvalidOIDCState
+	}
+}
+
+func TestOIDCRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewOIDCHandler(fakeOIDCService{}, true)
+	r.GET("/auth/oidc/providers", h.ListProviders)
+	r.POST("/auth/oidc/:provider/start", h.Start)
+	r.POST("/auth/oidc/:provider/callback", h.Callback)
+
+	cases := []struct {
+		method, path, body string
+		cookie             string
+		want               int
+		wantKey            string
+	}{
+		{http.MethodGet, "/auth/oidc/providers", "", "", http.StatusOK, "providers"},
+		{http.MethodPost, "/auth/oidc/google/start", "", "", http.StatusOK, "authorization_url"},
+		{http.MethodPost, "/auth/oidc/github/start", "", "", http.StatusNotFound, "error"},
+		{http.MethodPost, "/auth/oidc/google/callback", `{"code":"c","state":"s1"}`, "s1", http.StatusOK, "tokens"},
+		{http.MethodPost, "/auth/oidc/google/callback", `{"code":"c","state":"mfa"}`, "mfa", http.StatusOK, "mfa_token"},
+		{http.MethodPost, "/auth/oidc/google/callback", `{"code":"c","state":"taken"}`, "taken", http.StatusConflict, "error"},
+		{http.MethodPost, "/auth/oidc/google/callback", `{"code":"c","state":"replayed"}`, "replayed", http.StatusBadRequest, "error"},
+		{http.MethodPost, "/auth/oidc/google/callback", `{"code":"c"}`, "", http.StatusBadRequest, "error"},
+		{http.MethodPost, "/auth/oidc/google/callback", `{"code":"c","state":"s1"}`, "", http.StatusBadRequest, "error"},
+		{http.MethodPost, "/auth/oidc/google/callback", `{"code":"c","state":"s1"}`, "attacker", http.StatusBadRequest, "error"},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		if tc.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: oidcStateCookie, Value: tc.cookie})
+		}
+		r.ServeHTTP(w, req)
+
+		var payload map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("%s %s: expected valid json response: %v", tc.method, tc.path, err)
+		}
+		if w.Code != tc.want || payload[tc.wantKey] == nil {
+			t.Fatalf("%s %s %s: expected %d with %q, got %d %v", tc.method, tc.path, tc.body, tc.want, tc.wantKey, w.Code, payload)
+		}
+	}
+}
+
+func TestOIDCStartBindsStateToBrowser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewOIDCHandler(fakeOIDCService{}, true)
+	r.POST("/api/v1/auth/oidc/:provider/start", h.Start)
+	r.POST("/api/v1/auth/oidc/:provider/callback", h.Callback)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/auth/oidc/google/start", nil))
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("expected a state cookie, got %v", cookies)
+	}
+	cookie := cookies[0]
+	if cookie.Name != oidcStateCookie || cookie.Value != "s1" || cookie.MaxAge <= 0 || !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteLaxMode || cookie.Path != "/api/v1/auth/oidc/google" {
+		t.Fatalf("unexpected state cookie %+v", cookie)
+	}
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/oidc/google/callback", strings.NewReader(`{"code":"c","state":"s1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(cookie)
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected the callback to succeed, got %d %s", w.Code, w.Body.String())
+	}
+	cleared := w.Result().Cookies()
+	if len(cleared) != 1 || cleared[0].Name != oidcStateCookie || cleared[0].MaxAge >= 0 {
+		t.Fatalf("expected the callback to clear the state cookie, got %v", cleared)
+	}
+}
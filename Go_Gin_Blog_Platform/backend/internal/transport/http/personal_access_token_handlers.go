@@ -0,0 +1,105 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PersonalAccessTokenService interface {
+	Create(ctx context.Context, input service.CreatePersonalAccessTokenInput) (service.CreatedPersonalAccessToken, error)
+	List(ctx context.Context, userID string) ([]service.PersonalAccessTokenItem, error)
+	Revoke(ctx context.Context, userID, tokenID string) error
+}
+
+type createPersonalAccessTokenRequest struct {
+	Name          string   `json:"name" binding:"required"`
+	Scopes        []string `json:"scopes" binding:"required"`
+	ExpiresInDays int      `json:"expires_in_days"`
+}
+
+// PersonalAccessTokenHandler serves the caller's own tokens under /me/tokens.
+type PersonalAccessTokenHandler struct {
+	tokenService PersonalAccessTokenService
+}
+
+func NewPersonalAccessTokenHandler(tokenService PersonalAccessTokenService) *PersonalAccessTokenHandler {
+	return &PersonalAccessTokenHandler{tokenService: tokenService}
+}
+
+func (h *PersonalAccessTokenHandler) Create(c *gin.Context) {
+	userID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	var req createPersonalAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
+	token, err := h.tokenService.Create(c.Request.Context(), service.CreatePersonalAccessTokenInput{
+		UserID:        userID,
+		Name:          req.Name,
+		Scopes:        req.Scopes,
+		ExpiresInDays: req.ExpiresInDays,
+		MFA:           c.GetBool(ContextKeyMFA),
+	})
+	if err != nil {
+		handlePersonalAccessTokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+func (h *PersonalAccessTokenHandler) List(c *gin.Context) {
+	userID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	tokens, err := h.tokenService.List(c.Request.Context(), userID)
+	if err != nil {
+		handlePersonalAccessTokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tokens})
+}
+
+func (h *PersonalAccessTokenHandler) Revoke(c *gin.Context) {
+	userID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	if err := h.tokenService.Revoke(c.Request.Context(), userID, c.Param("id")); err != nil {
+		handlePersonalAccessTokenError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func handlePersonalAccessTokenError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		writeError(c, http.StatusBadRequest, "validation_error", "Request validation failed", gin.H{"reason": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		writeError(c, http.StatusForbidden, "forbidden", "Your role cannot grant the requested scopes", nil)
+	case errors.Is(err, service.ErrUserNotFound):
+		writeError(c, http.StatusNotFound, "user_not_found", "User was not found", nil)
+	case errors.Is(err, service.ErrPersonalAccessTokenNotFound):
+		writeError(c, http.StatusNotFound, "token_not_found", "Personal access token was not found", nil)
+	default:
+		writeError(c, http.StatusInternalServerError, "internal_error", "Unexpected server error", nil)
+	}
+}
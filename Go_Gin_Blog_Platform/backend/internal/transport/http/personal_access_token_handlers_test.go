@@ -0,0 +1,86 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type fakePersonalAccessTokenService struct {
+	created *service.CreatePersonalAccessTokenInput
+}
+
+func (f fakePersonalAccessTokenService) Create(_ context.Context, input service.CreatePersonalAccessTokenInput) (service.CreatedPersonalAccessToken, error) {
+	if len(input.Scopes) == 1 && input.Scopes[0] == "admin:users" {
+		return service.CreatedPersonalAccessToken{}, service.ErrForbidden
+	}
+	*f.created = input
+	return service.CreatedPersonalAccessToken{
+		PersonalAccessTokenItem: service.PersonalAccessTokenItem{ID: "t1", Name: input.Name, Scopes: input.Scopes},
+		Token:                   "bpat_secret",
+	}, nil
+}
+
+func (f fakePersonalAccessTokenService) List(_ context.Context, _ string) ([]service.PersonalAccessTokenItem, error) {
+	return []service.PersonalAccessTokenItem{{ID: "t1", Name: "ci"}}, nil
+}
+
+func (f fakePersonalAccessTokenService) Revoke(_ context.Context, _, tokenID string) error {
+	if tokenID != "t1" {
+		return service.ErrPersonalAccessTokenNotFound
+	}
+	return nil
+}
+
+func TestPersonalAccessTokenRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	created := &service.CreatePersonalAccessTokenInput{}
+	h := NewPersonalAccessTokenHandler(fakePersonalAccessTokenService{created: created})
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(ContextKeyUserID, "u1")
+		c.Set(ContextKeyRole, "author")
+		c.Set(ContextKeyMFA, true)
+	})
+	r.POST("/me/tokens", h.Create)
+	r.GET("/me/tokens", h.List)
+	r.DELETE("/me/tokens/:id", h.Revoke)
+
+	cases := []struct {
+		method, path, body string
+		want               int
+	}{
+		{http.MethodPost, "/me/tokens", `{"name":"ci","scopes":["posts:write"],"expires_in_days":30}`, http.StatusCreated},
+		{http.MethodPost, "/me/tokens", `{"name":"ops","scopes":["admin:users"]}`, http.StatusForbidden},
+		{http.MethodPost, "/me/tokens", `{"name":"ci"}`, http.StatusBadRequest},
+		{http.MethodGet, "/me/tokens", "", http.StatusOK},
+		{http.MethodDelete, "/me/tokens/t1", "", http.StatusNoContent},
+		{http.MethodDelete, "/me/tokens/t2", "", http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.want {
+			t.Fatalf("%s %s %s: expected %d, got %d %s", tc.method, tc.path, tc.body, tc.want, w.Code, w.Body.String())
+		}
+		if tc.want == http.StatusCreated {
+			var payload map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &payload); err != nil || payload["token"] != "bpat_secret" || payload["id"] != "t1" {
+				t.Fatalf("expected the token to be returned once with its details, got %s", w.Body.String())
+			}
+		}
+	}
+
+	if created.UserID != "u1" || created.ExpiresInDays != 30 || !created.MFA {
+		t.Fatalf("expected the caller and session to be passed on, got %+v", created)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
 	"github.com/gin-gonic/gin"
@@ -12,10 +14,22 @@ import (
 
 type PostService interface {
 	Create(ctx context.Context, input service.CreatePostInput) (service.PostItem, error)
-	GetByID(ctx context.Context, postID string) (service.PostItem, error)
+	GetByID(ctx context.Context, input service.GetPostInput) (service.PostItem, error)
+	GetBySlug(ctx context.Context, input service.GetPostBySlugInput) (service.PostItem, error)
 	List(ctx context.Context, input service.ListPostsInput) ([]service.PostItem, service.Pagination, error)
+	Search(ctx context.Context, input service.SearchPostsInput) ([]service.PostSearchItem, service.Pagination, error)
+	ListMine(ctx context.Context, input service.ListMyPostsInput) ([]service.PostItem, service.Pagination, error)
 	Update(ctx context.Context, input service.UpdatePostInput) (service.PostItem, error)
 	Delete(ctx context.Context, input service.DeletePostInput) error
+	ListTrash(ctx context.Context, input service.ListTrashInput) ([]service.PostItem, service.Pagination, error)
+	Restore(ctx context.Context, input service.RestorePostInput) (service.PostItem, error)
+	Purge(ctx context.Context, input service.PurgePostInput) error
+	ListRevisions(ctx context.Context, input service.PostRevisionInput) ([]service.PostRevisionItem, error)
+	DiffRevision(ctx context.Context, input service.PostRevisionInput) (service.PostRevisionDiff, error)
+	RestoreRevision(ctx context.Context, input service.PostRevisionInput) (service.PostItem, error)
+	Review(ctx context.Context, input service.ReviewPostInput) (service.PostItem, error)
+	ListReviewEvents(ctx context.Context, input service.ListPostReviewsInput) ([]service.PostReviewEventItem, error)
+	ReviewQueue(ctx context.Context, input service.ReviewQueueInput) ([]service.PostItem, service.Pagination, error)
 }
 
 type PostHandler struct {
@@ -27,22 +41,86 @@ func NewPostHandler(postService PostService) *PostHandler {
 }
 
 type createPostRequest struct {
-	Title   string `json:"title" binding:"required"`
-	Content string `json:"content" binding:"required"`
-	Status  string `json:"status"`
+	Title     string     `json:"title" binding:"required"`
+	Content   string     `json:"content" binding:"required"`
+	Status    string     `json:"status"`
+	PublishAt *time.Time `json:"publish_at"`
+	Tags      []string   `json:"tags"`
+	Category  string     `json:"category"`
 }
 
 type updatePostRequest struct {
-	Title   *string `json:"title"`
-	Content *string `json:"content"`
-	Status  *string `json:"status"`
+	Title     *string    `json:"title"`
+	Content   *string    `json:"content"`
+	Status    *string    `json:"status"`
+	PublishAt *time.Time `json:"publish_at"`
+	Tags      *[]string  `json:"tags"`
+	Category  *string    `json:"category"`
+}
+
+type reviewPostRequest struct {
+	Action     string `json:"action" binding:"required"`
+	Comment    string `json:"comment"`
+	ReviewerID string `json:"reviewer_id"`
 }
 
 func (h *PostHandler) List(c *gin.Context) {
 	page, _ := strconv.Atoi(c.Query("page"))
 	limit, _ := strconv.Atoi(c.Query("limit"))
 
-	posts, pagination, err := h.postService.List(c.Request.Context(), service.ListPostsInput{Page: page, Limit: limit})
+	viewerID, viewerRole, _ := currentUserFromContext(c)
+
+	posts, pagination, err := h.postService.List(c.Request.Context(), service.ListPostsInput{
+		Page:       page,
+		Limit:      limit,
+		Status:     c.Query("status"),
+		Tag:        c.Query("tag"),
+		Category:   c.Query("category"),
+		Format:     c.Query("format"),
+		ViewerID:   viewerID,
+		ViewerRole: viewerRole,
+	})
+	if err != nil {
+		handlePostError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": posts, "meta": pagination})
+}
+
+func (h *PostHandler) Search(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	results, pagination, err := h.postService.Search(c.Request.Context(), service.SearchPostsInput{
+		Query: c.Query("q"),
+		Page:  page,
+		Limit: limit,
+	})
+	if err != nil {
+		handlePostError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": results, "meta": pagination})
+}
+
+func (h *PostHandler) ListMine(c *gin.Context) {
+	actorID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	posts, pagination, err := h.postService.ListMine(c.Request.Context(), service.ListMyPostsInput{
+		ActorID: actorID,
+		Status:  c.Query("status"),
+		Page:    page,
+		Limit:   limit,
+	})
 	if err != nil {
 		handlePostError(c, err)
 		return
@@ -52,7 +130,14 @@ func (h *PostHandler) List(c *gin.Context) {
 }
 
 func (h *PostHandler) GetByID(c *gin.Context) {
-	post, err := h.postService.GetByID(c.Request.Context(), c.Param("id"))
+	viewerID, viewerRole, _ := currentUserFromContext(c)
+
+	post, err := h.postService.GetByID(c.Request.Context(), service.GetPostInput{
+		PostID:     c.Param("id"),
+		ViewerID:   viewerID,
+		ViewerRole: viewerRole,
+		Format:     c.Query("format"),
+	})
 	if err != nil {
 		handlePostError(c, err)
 		return
@@ -61,6 +146,30 @@ func (h *PostHandler) GetByID(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": post})
 }
 
+func (h *PostHandler) GetBySlug(c *gin.Context) {
+	viewerID, viewerRole, _ := currentUserFromContext(c)
+	requested := c.Param("slug")
+
+	post, err := h.postService.GetBySlug(c.Request.Context(), service.GetPostBySlugInput{
+		Slug:       requested,
+		ViewerID:   viewerID,
+		ViewerRole: viewerRole,
+	})
+	if err != nil {
+		handlePostError(c, err)
+		return
+	}
+
+	if post.Slug != requested {
+		location := strings.TrimSuffix(c.Request.URL.Path, requested) + post.Slug
+		c.Header("Location", location)
+		c.JSON(http.StatusMovedPermanently, gin.H{"data": gin.H{"slug": post.Slug, "location": location}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": post})
+}
+
 func (h *PostHandler) Create(c *gin.Context) {
 	var req createPostRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -80,6 +189,9 @@ func (h *PostHandler) Create(c *gin.Context) {
 		Title:     req.Title,
 		Content:   req.Content,
 		Status:    req.Status,
+		PublishAt: req.PublishAt,
+		Tags:      req.Tags,
+		Category:  req.Category,
 	})
 	if err != nil {
 		handlePostError(c, err)
@@ -109,6 +221,9 @@ func (h *PostHandler) Update(c *gin.Context) {
 		Title:     req.Title,
 		Content:   req.Content,
 		Status:    req.Status,
+		PublishAt: req.PublishAt,
+		Tags:      req.Tags,
+		Category:  req.Category,
 	})
 	if err != nil {
 		handlePostError(c, err)
@@ -137,6 +252,210 @@ func (h *PostHandler) Delete(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+func (h *PostHandler) ListTrash(c *gin.Context) {
+	actorID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	posts, pagination, err := h.postService.ListTrash(c.Request.Context(), service.ListTrashInput{
+		ActorID: actorID,
+		Page:    page,
+		Limit:   limit,
+	})
+	if err != nil {
+		handlePostError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": posts, "meta": pagination})
+}
+
+func (h *PostHandler) Restore(c *gin.Context) {
+	actorID, actorRole, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	post, err := h.postService.Restore(c.Request.Context(), service.RestorePostInput{
+		PostID:    c.Param("id"),
+		ActorID:   actorID,
+		ActorRole: actorRole,
+	})
+	if err != nil {
+		handlePostError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": post})
+}
+
+func (h *PostHandler) Purge(c *gin.Context) {
+	actorID, actorRole, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	if err := h.postService.Purge(c.Request.Context(), service.PurgePostInput{
+		PostID:    c.Param("id"),
+		ActorID:   actorID,
+		ActorRole: actorRole,
+	}); err != nil {
+		handlePostError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *PostHandler) ListRevisions(c *gin.Context) {
+	input, ok := revisionInput(c, false)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.postService.ListRevisions(c.Request.Context(), input)
+	if err != nil {
+		handlePostError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": revisions})
+}
+
+func (h *PostHandler) DiffRevision(c *gin.Context) {
+	input, ok := revisionInput(c, true)
+	if !ok {
+		return
+	}
+
+	diff, err := h.postService.DiffRevision(c.Request.Context(), input)
+	if err != nil {
+		handlePostError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": diff})
+}
+
+func (h *PostHandler) RestoreRevision(c *gin.Context) {
+	input, ok := revisionInput(c, true)
+	if !ok {
+		return
+	}
+
+	post, err := h.postService.RestoreRevision(c.Request.Context(), input)
+	if err != nil {
+		handlePostError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": post})
+}
+
+func (h *PostHandler) Review(c *gin.Context) {
+	var req reviewPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
+	actorID, actorRole, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	post, err := h.postService.Review(c.Request.Context(), service.ReviewPostInput{
+		PostID:     c.Param("id"),
+		ActorID:    actorID,
+		ActorRole:  actorRole,
+		Action:     req.Action,
+		Comment:    req.Comment,
+		ReviewerID: req.ReviewerID,
+	})
+	if err != nil {
+		handlePostError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": post})
+}
+
+func (h *PostHandler) ListReviewEvents(c *gin.Context) {
+	actorID, actorRole, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	events, err := h.postService.ListReviewEvents(c.Request.Context(), service.ListPostReviewsInput{
+		PostID:    c.Param("id"),
+		ActorID:   actorID,
+		ActorRole: actorRole,
+	})
+	if err != nil {
+		handlePostError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": events})
+}
+
+func (h *PostHandler) ReviewQueue(c *gin.Context) {
+	actorID, actorRole, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	posts, pagination, err := h.postService.ReviewQueue(c.Request.Context(), service.ReviewQueueInput{
+		ActorID:   actorID,
+		ActorRole: actorRole,
+		Assigned:  c.Query("assigned"),
+		Page:      page,
+		Limit:     limit,
+	})
+	if err != nil {
+		handlePostError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": posts, "meta": pagination})
+}
+
+func revisionInput(c *gin.Context, withRevision bool) (service.PostRevisionInput, bool) {
+	actorID, actorRole, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return service.PostRevisionInput{}, false
+	}
+
+	input := service.PostRevisionInput{
+		PostID:    c.Param("id"),
+		ActorID:   actorID,
+		ActorRole: actorRole,
+	}
+	if withRevision {
+		revision, err := strconv.Atoi(c.Param("rev"))
+		if err != nil || revision <= 0 {
+			writeError(c, http.StatusBadRequest, "validation_error", "Request validation failed", gin.H{"reason": "revision must be a positive integer"})
+			return service.PostRevisionInput{}, false
+		}
+		input.Revision = revision
+	}
+	return input, true
+}
+
 func handlePostError(c *gin.Context, err error) {
 	switch {
 	case errors.Is(err, service.ErrValidation):
@@ -145,6 +464,10 @@ func handlePostError(c *gin.Context, err error) {
 		writeError(c, http.StatusForbidden, "forbidden", "Insufficient permissions", nil)
 	case errors.Is(err, service.ErrPostNotFound):
 		writeError(c, http.StatusNotFound, "post_not_found", "Post was not found", nil)
+	case errors.Is(err, service.ErrRevisionNotFound):
+		writeError(c, http.StatusNotFound, "revision_not_found", "Revision was not found", nil)
+	case errors.Is(err, service.ErrConflict):
+		writeError(c, http.StatusConflict, "conflict", "Request conflicts with a concurrent change", gin.H{"reason": err.Error()})
 	default:
 		writeError(c, http.StatusInternalServerError, "internal_error", "Unexpected server error", nil)
 	}
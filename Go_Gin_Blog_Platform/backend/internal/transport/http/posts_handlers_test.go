@@ -9,25 +9,64 @@ import (
 	"testing"
 	"time"
 
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/models"
 	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 )
 
-type fakePostService struct{}
+type fakePostService struct {
+	lastList     *service.ListPostsInput
+	lastListMine *service.ListMyPostsInput
+}
 
 func (f fakePostService) Create(_ context.Context, input service.CreatePostInput) (service.PostItem, error) {
 	return service.PostItem{ID: "p1", AuthorID: input.ActorID, Title: input.Title, Content: input.Content, Status: models.PostStatusPublished}, nil
 }
 
-func (f fakePostService) GetByID(_ context.Context, postID string) (service.PostItem, error) {
-	return service.PostItem{ID: postID, AuthorID: "u1", Title: "Hello", Content: "World", Status: models.PostStatusPublished}, nil
+func (f fakePostService) GetByID(_ context.Context, input service.GetPostInput) (service.PostItem, error) {
+	if input.PostID == "draft" && input.ViewerID != "u1" {
+		return service.PostItem{}, service.ErrPostNotFound
+	}
+	if input.Format == "html" {
+		return service.PostItem{ID: input.PostID, AuthorID: "u1", Title: "Hello", Content: "<p>World</p>", Format: "html", Status: models.PostStatusPublished}, nil
+	}
+	if input.Format != "" && input.Format != "markdown" {
+		return service.PostItem{}, service.ErrValidation
+	}
+	return service.PostItem{ID: input.PostID, AuthorID: "u1", Title: "Hello", Content: "World", Format: "markdown", Status: models.PostStatusPublished}, nil
+}
+
+func (f fakePostService) GetBySlug(_ context.Context, input service.GetPostBySlugInput) (service.PostItem, error) {
+	if input.Slug != "hello-world" && input.Slug != "old-hello" {
+		return service.PostItem{}, service.ErrPostNotFound
+	}
+	return service.PostItem{ID: "p1", AuthorID: "u1", Title: "Hello World", Slug: "hello-world", Content: "World", Status: models.PostStatusPublished}, nil
 }
 
-func (f fakePostService) List(_ context.Context, _ service.ListPostsInput) ([]service.PostItem, service.Pagination, error) {
+func (f fakePostService) List(_ context.Context, input service.ListPostsInput) ([]service.PostItem, service.Pagination, error) {
+	if f.lastList != nil {
+		*f.lastList = input
+	}
 	return []service.PostItem{{ID: "p1", Title: "A", Content: "B", Status: models.PostStatusPublished}}, service.Pagination{Page: 1, Limit: 10, Total: 1, TotalPages: 1}, nil
 }
 
+func (f fakePostService) Search(_ context.Context, input service.SearchPostsInput) ([]service.PostSearchItem, service.Pagination, error) {
+	if input.Query == "" {
+		return nil, service.Pagination{}, service.ErrValidation
+	}
+	item := service.PostSearchItem{PostItem: service.PostItem{ID: "p1", Title: "A"}, Rank: 0.5, Snippet: "<mark>A</mark>"}
+	return []service.PostSearchItem{item}, service.Pagination{Page: 1, Limit: 10, Total: 1, TotalPages: 1}, nil
+}
+
+func (f fakePostService) ListMine(_ context.Context, input service.ListMyPostsInput) ([]service.PostItem, service.Pagination, error) {
+	if f.lastListMine != nil {
+		*f.lastListMine = input
+	}
+	return []service.PostItem{{ID: "p2", AuthorID: input.ActorID, Title: "Draft", Content: "B", Status: models.PostStatusDraft}}, service.Pagination{Page: 1, Limit: 10, Total: 1, TotalPages: 1}, nil
+}
+
 func (f fakePostService) Update(_ context.Context, input service.UpdatePostInput) (service.PostItem, error) {
 	return service.PostItem{ID: input.PostID, AuthorID: input.ActorID, Title: "Updated", Content: "Updated", Status: models.PostStatusPublished, UpdatedAt: time.Now()}, nil
 }
@@ -36,6 +75,60 @@ func (f fakePostService) Delete(_ context.Context, _ service.DeletePostInput) er
 	return nil
 }
 
+func (f fakePostService) ListTrash(_ context.Context, input service.ListTrashInput) ([]service.PostItem, service.Pagination, error) {
+	return []service.PostItem{{ID: "p3", AuthorID: input.ActorID, Title: "Trashed", Content: "B", Status: models.PostStatusDraft}}, service.Pagination{Page: 1, Limit: 10, Total: 1, TotalPages: 1}, nil
+}
+
+func (f fakePostService) Restore(_ context.Context, input service.RestorePostInput) (service.PostItem, error) {
+	if input.PostID != "p3" {
+		return service.PostItem{}, service.ErrPostNotFound
+	}
+	return service.PostItem{ID: input.PostID, AuthorID: input.ActorID, Title: "Trashed", Content: "B", Status: models.PostStatusDraft}, nil
+}
+
+func (f fakePostService) Purge(_ context.Context, input service.PurgePostInput) error {
+	if input.ActorRole != "admin" {
+		return service.ErrForbidden
+	}
+	return nil
+}
+
+func (f fakePostService) ListRevisions(_ context.Context, input service.PostRevisionInput) ([]service.PostRevisionItem, error) {
+	return []service.PostRevisionItem{{Revision: 1, EditorID: &input.ActorID, Title: "Old", Content: "Old", Status: models.PostStatusDraft}}, nil
+}
+
+func (f fakePostService) DiffRevision(_ context.Context, input service.PostRevisionInput) (service.PostRevisionDiff, error) {
+	if input.Revision != 1 {
+		return service.PostRevisionDiff{}, service.ErrRevisionNotFound
+	}
+	return service.PostRevisionDiff{Revision: 1, Diff: "--- revision 1\n+++ current\n"}, nil
+}
+
+func (f fakePostService) RestoreRevision(_ context.Context, input service.PostRevisionInput) (service.PostItem, error) {
+	return service.PostItem{ID: input.PostID, AuthorID: input.ActorID, Title: "Old", Content: "Old", Status: models.PostStatusPublished}, nil
+}
+
+func (f fakePostService) Review(_ context.Context, input service.ReviewPostInput) (service.PostItem, error) {
+	if input.Action == "approve" && input.ActorRole != "editor" {
+		return service.PostItem{}, service.ErrForbidden
+	}
+	if input.Action != "submit" && input.Action != "approve" {
+		return service.PostItem{}, service.ErrValidation
+	}
+	return service.PostItem{ID: input.PostID, AuthorID: "u1", Title: "Draft", Content: "B", Status: models.PostStatusInReview}, nil
+}
+
+func (f fakePostService) ListReviewEvents(_ context.Context, input service.ListPostReviewsInput) ([]service.PostReviewEventItem, error) {
+	return []service.PostReviewEventItem{{ID: "e1", ActorID: &input.ActorID, Action: "submit", FromStatus: models.PostStatusDraft, ToStatus: models.PostStatusInReview}}, nil
+}
+
+func (f fakePostService) ReviewQueue(_ context.Context, input service.ReviewQueueInput) ([]service.PostItem, service.Pagination, error) {
+	if input.Assigned != "" && input.Assigned != "me" && input.Assigned != "none" {
+		return nil, service.Pagination{}, service.ErrValidation
+	}
+	return []service.PostItem{{ID: "p4", AuthorID: "u2", Title: "Waiting", Content: "B", Status: models.PostStatusInReview}}, service.Pagination{Page: 1, Limit: 10, Total: 1, TotalPages: 1}, nil
+}
+
 func TestPostsListSuccess(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -75,3 +168,272 @@ func TestPostsCreateUnauthorizedWhenContextMissing(t *testing.T) {
 		t.Fatalf("expected status 401, got %d", w.Code)
 	}
 }
+
+func TestPostsListPassesViewerFromOptionalAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	var captured service.ListPostsInput
+	h := NewPostHandler(fakePostService{lastList: &captured})
+	verifier := fakeVerifier{claims: &auth.AccessClaims{Role: "admin", RegisteredClaims: jwt.RegisteredClaims{Subject: "a1"}}}
+	r.GET("/posts", OptionalAuth(verifier), h.List)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/posts?status=draft", nil)
+	req.Header.Set("Authorization", "Bearer test")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if captured.ViewerID != "a1" || captured.ViewerRole != "admin" || captured.Status != "draft" {
+		t.Fatalf("unexpected list input: %+v", captured)
+	}
+}
+
+func TestPostsGetDraftHiddenFromAnonymous(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewPostHandler(fakePostService{})
+	r.GET("/posts/:id", OptionalAuth(fakeVerifier{}), h.GetByID)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/posts/draft", nil)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", w.Code)
+	}
+}
+
+func TestPostsListMineUsesActorAndStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	var captured service.ListMyPostsInput
+	h := NewPostHandler(fakePostService{lastListMine: &captured})
+	verifier := fakeVerifier{claims: &auth.AccessClaims{Role: "author", RegisteredClaims: jwt.RegisteredClaims{Subject: "u1"}}}
+	r.GET("/me/posts", AuthRequired(verifier), h.ListMine)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/me/posts?status=draft", nil)
+	req.Header.Set("Authorization", "Bearer test")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if captured.ActorID != "u1" || captured.Status != "draft" {
+		t.Fatalf("unexpected list mine input: %+v", captured)
+	}
+}
+
+func TestPostsListMineUnauthorizedWhenContextMissing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewPostHandler(fakePostService{})
+	r.GET("/me/posts", h.ListMine)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/me/posts", nil)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401, got %d", w.Code)
+	}
+}
+
+func TestPostsGetBySlugCanonical(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewPostHandler(fakePostService{})
+	r.GET("/posts/by-slug/:slug", h.GetBySlug)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/posts/by-slug/hello-world", nil)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+}
+
+func TestPostsGetBySlugRedirectsHistoricalSlug(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewPostHandler(fakePostService{})
+	r.GET("/api/v1/posts/by-slug/:slug", h.GetBySlug)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/posts/by-slug/old-hello", nil)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusMovedPermanently {
+		t.Fatalf("expected status 301, got %d", w.Code)
+	}
+	if location := w.Header().Get("Location"); location != "/api/v1/posts/by-slug/hello-world" {
+		t.Fatalf("unexpected Location header %q", location)
+	}
+}
+
+func TestPostsSearchReturnsMeta(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewPostHandler(fakePostService{})
+	r.GET("/posts/search", h.Search)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts/search?q=go", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("expected json response: %v", err)
+	}
+	if _, ok := payload["meta"]; !ok {
+		t.Fatalf("expected meta object in response")
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts/search", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 without query, got %d", w.Code)
+	}
+}
+
+func TestPostsRevisionDiffParsesRevisionNumber(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewPostHandler(fakePostService{})
+	verifier := fakeVerifier{claims: &auth.AccessClaims{Role: "author", RegisteredClaims: jwt.RegisteredClaims{Subject: "u1"}}}
+	r.GET("/posts/:id/revisions/:rev/diff", AuthRequired(verifier), h.DiffRevision)
+
+	cases := map[string]int{
+		"/posts/p1/revisions/1/diff":   http.StatusOK,
+		"/posts/p1/revisions/7/diff":   http.StatusNotFound,
+		"/posts/p1/revisions/abc/diff": http.StatusBadRequest,
+		"/posts/p1/revisions/0/diff":   http.StatusBadRequest,
+	}
+	for path, want := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer test")
+		r.ServeHTTP(w, req)
+
+		if w.Code != want {
+			t.Fatalf("%s: expected status %d, got %d", path, want, w.Code)
+		}
+	}
+}
+
+func TestPostsRestoreRevisionUnauthorizedWhenContextMissing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewPostHandler(fakePostService{})
+	r.POST("/posts/:id/revisions/:rev/restore", h.RestoreRevision)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/posts/p1/revisions/1/restore", nil))
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401, got %d", w.Code)
+	}
+}
+
+func TestPostsRestoreFromTrash(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewPostHandler(fakePostService{})
+	verifier := fakeVerifier{claims: &auth.AccessClaims{Role: "author", RegisteredClaims: jwt.RegisteredClaims{Subject: "u1"}}}
+	r.POST("/posts/:id/restore", AuthRequired(verifier), h.Restore)
+
+	for path, want := range map[string]int{"/posts/p3/restore": http.StatusOK, "/posts/missing/restore": http.StatusNotFound} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("Authorization", "Bearer test")
+		r.ServeHTTP(w, req)
+
+		if w.Code != want {
+			t.Fatalf("%s: expected status %d, got %d", path, want, w.Code)
+		}
+	}
+}
+
+func TestPostsPurgeReturnsNoContent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewPostHandler(fakePostService{})
+	verifier := fakeVerifier{claims: &auth.AccessClaims{Role: "admin", RegisteredClaims: jwt.RegisteredClaims{Subject: "a1"}}}
+	r.DELETE("/admin/posts/:id", AuthRequired(verifier), h.Purge)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, "/admin/posts/p3", nil)
+	req.Header.Set("Authorization", "Bearer test")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", w.Code)
+	}
+}
+
+func TestPostsGetByIDFormatQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewPostHandler(fakePostService{})
+	r.GET("/posts/:id", OptionalAuth(fakeVerifier{}), h.GetByID)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts/p1?format=html", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var payload struct {
+		Data service.PostItem `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("expected json response: %v", err)
+	}
+	if payload.Data.Format != "html" || payload.Data.Content != "<p>World</p>" {
+		t.Fatalf("expected html content, got %+v", payload.Data)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts/p1?format=pdf", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for unknown format, got %d", w.Code)
+	}
+}
+
+func TestPostsReviewRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewPostHandler(fakePostService{})
+	verifier := fakeVerifier{claims: &auth.AccessClaims{Role: "author", RegisteredClaims: jwt.RegisteredClaims{Subject: "u1"}}}
+	r.POST("/posts/:id/review", AuthRequired(verifier), h.Review)
+	r.GET("/posts/:id/review", AuthRequired(verifier), h.ListReviewEvents)
+	r.GET("/review-queue", AuthRequired(verifier), h.ReviewQueue)
+
+	cases := []struct {
+		method, path, body string
+		want               int
+	}{
+		{http.MethodPost, "/posts/p2/review", `{"action":"submit"}`, http.StatusOK},
+		{http.MethodPost, "/posts/p2/review", `{"action":"approve"}`, http.StatusForbidden},
+		{http.MethodPost, "/posts/p2/review", `{"action":"merge"}`, http.StatusBadRequest},
+		{http.MethodPost, "/posts/p2/review", `{}`, http.StatusBadRequest},
+		{http.MethodGet, "/posts/p2/review", "", http.StatusOK},
+		{http.MethodGet, "/review-queue?assigned=me", "", http.StatusOK},
+		{http.MethodGet, "/review-queue?assigned=them", "", http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Authorization", "Bearer test")
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.want {
+			t.Fatalf("%s %s %s: expected status %d, got %d", tc.method, tc.path, tc.body, tc.want, w.Code)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/policy"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type RoleService interface {
+	List(ctx context.Context) ([]service.RoleItem, error)
+	Create(ctx context.Context, actorID, actorRole, name string, permissions []string) (service.RoleItem, error)
+	SetPermissions(ctx context.Context, actorID, actorRole, name string, permissions []string) (service.RoleItem, error)
+	Delete(ctx context.Context, actorID, name string) error
+}
+
+type createRoleRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Permissions []string `json:"permissions"`
+}
+
+type setRolePermissionsRequest struct {
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+// RoleHandler serves /admin/roles, where admins decide what each role may do.
+type RoleHandler struct {
+	roleService RoleService
+}
+
+func NewRoleHandler(roleService RoleService) *RoleHandler {
+	return &RoleHandler{roleService: roleService}
+}
+
+func (h *RoleHandler) List(c *gin.Context) {
+	roles, err := h.roleService.List(c.Request.Context())
+	if err != nil {
+		handleRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": roles, "permissions": policy.All()})
+}
+
+func (h *RoleHandler) Create(c *gin.Context) {
+	var req createRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
+	actorID, actorRole, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	role, err := h.roleService.Create(c.Request.Context(), actorID, actorRole, req.Name, req.Permissions)
+	if err != nil {
+		handleRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": role})
+}
+
+func (h *RoleHandler) SetPermissions(c *gin.Context) {
+	var req setRolePermissionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
+	actorID, actorRole, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	role, err := h.roleService.SetPermissions(c.Request.Context(), actorID, actorRole, c.Param("name"), req.Permissions)
+	if err != nil {
+		handleRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": role})
+}
+
+func (h *RoleHandler) Delete(c *gin.Context) {
+	actorID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	if err := h.roleService.Delete(c.Request.Context(), actorID, c.Param("name")); err != nil {
+		handleRoleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func handleRoleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		writeError(c, http.StatusBadRequest, "validation_error", "Request validation failed", gin.H{"reason": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		writeError(c, http.StatusForbidden, "forbidden", "Insufficient permissions", nil)
+	case errors.Is(err, service.ErrRoleNotFound):
+		writeError(c, http.StatusNotFound, "role_not_found", "Role was not found", nil)
+	case errors.Is(err, service.ErrRoleExists):
+		writeError(c, http.StatusConflict, "role_exists", "A role with this name already exists", nil)
+	case errors.Is(err, service.ErrRoleInUse):
+		writeError(c, http.StatusConflict, "role_in_use", "Role is still assigned to users", nil)
+	case errors.Is(err, service.ErrRoleImmutable):
+		writeError(c, http.StatusConflict, "role_immutable", "Built-in roles cannot be removed and the admin role cannot be edited", nil)
+	default:
+		writeError(c, http.StatusInternalServerError, "internal_error", "Unexpected server error", nil)
+	}
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/policy"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type fakeRoleService struct{}
+
+func (fakeRoleService) List(_ context.Context) ([]service.RoleItem, error) {
+	return []service.RoleItem{{Name: "reader", BuiltIn: true, Permissions: []policy.Permission{}}}, nil
+}
+
+func (fakeRoleService) Create(_ context.Context, _, _, name string, _ []string) (service.RoleItem, error) {
+	if name == "author" {
+		return service.RoleItem{}, service.ErrRoleExists
+	}
+	return service.RoleItem{Name: name}, nil
+}
+
+func (fakeRoleService) SetPermissions(_ context.Context, _, _, name string, permissions []string) (service.RoleItem, error) {
+	switch {
+	case name == "admin":
+		return service.RoleItem{}, service.ErrRoleImmutable
+	case name == "missing":
+		return service.RoleItem{}, service.ErrRoleNotFound
+	case len(permissions) == 1 && permissions[0] == "post.fly":
+		return service.RoleItem{}, service.ErrValidation
+	}
+	return service.RoleItem{Name: name}, nil
+}
+
+func (fakeRoleService) Delete(_ context.Context, _, name string) error {
+	if name == "busy" {
+		return service.ErrRoleInUse
+	}
+	return nil
+}
+
+func TestRoleRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewRoleHandler(fakeRoleService{})
+	r.Use(func(c *gin.Context) {
+		c.Set(ContextKeyUserID, "admin-1")
+		c.Set(ContextKeyRole, "admin")
+	})
+	r.GET("/admin/roles", h.List)
+	r.POST("/admin/roles", h.Create)
+	r.PUT("/admin/roles/:name/permissions", h.SetPermissions)
+	r.DELETE("/admin/roles/:name", h.Delete)
+
+	cases := []struct {
+		method, path, body string
+		want               int
+	}{
+		{http.MethodPost, "/admin/roles", `{"name":"moderator","permissions":["comment.moderate"]}`, http.StatusCreated},
+		{http.MethodPost, "/admin/roles", `{"name":"author"}`, http.StatusConflict},
+		{http.MethodPost, "/admin/roles", `{}`, http.StatusBadRequest},
+		{http.MethodPut, "/admin/roles/editor/permissions", `{"permissions":["post.create"]}`, http.StatusOK},
+		{http.MethodPut, "/admin/roles/editor/permissions", `{"permissions":["post.fly"]}`, http.StatusBadRequest},
+		{http.MethodPut, "/admin/roles/admin/permissions", `{"permissions":[]}`, http.StatusConflict},
+		{http.MethodPut, "/admin/roles/missing/permissions", `{"permissions":[]}`, http.StatusNotFound},
+		{http.MethodDelete, "/admin/roles/moderator", "", http.StatusNoContent},
+		{http.MethodDelete, "/admin/roles/busy", "", http.StatusConflict},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.want {
+			t.Fatalf("%s %s %s: expected %d, got %d %s", tc.method, tc.path, tc.body, tc.want, w.Code, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/roles", nil))
+	var payload struct {
+		Data        []service.RoleItem  `json:"data"`
+		Permissions []policy.Permission `json:"permissions"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &payload); err != nil || len(payload.Data) != 1 || len(payload.Permissions) != len(policy.All()) {
+		t.Fatalf("expected roles with the permission catalogue, got %d %s", w.Code, w.Body.String())
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/policy"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/ratelimit"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,14 +22,48 @@ type RouterDependencies struct {
 	AuthHandler         *AuthHandler
 	PostHandler         *PostHandler
 	AdminHandler        *AdminHandler
+	TaxonomyHandler     *TaxonomyHandler
+	FeedHandler         *FeedHandler
+	CommentHandler      *CommentHandler
+	SessionHandler      *SessionHandler
+	JWKSHandler         *JWKSHandler
+	OIDCHandler         *OIDCHandler
+	TokenHandler        *PersonalAccessTokenHandler
+	RoleHandler         *RoleHandler
+	AuditHandler        *AuditHandler
+	RolePolicy          RolePolicy
+	Authorizer          Authorizer
 	AccessTokenVerifier AccessTokenVerifier
+	TokenAuthenticator  PersonalAccessTokenAuthenticator
+	CORS                CORSConfig
+	TrustedProxies      []string
+	RateLimitStore      ratelimit.Store
+	AuthRateLimits      AuthRateLimits
 }
 
 func NewRouter(logger *slog.Logger, deps RouterDependencies) *gin.Engine {
 	router := gin.New()
-	router.Use(gin.Logger(), gin.Recovery())
+	// Gin trusts every proxy by default, which would let any caller pick its
+	// own client IP through X-Forwarded-For; an empty list trusts none.
+	if err := router.SetTrustedProxies(deps.TrustedProxies); err != nil {
+		logger.Error("invalid trusted proxies, client IPs will be read from the socket", "error", err)
+		_ = router.SetTrustedProxies(nil)
+	}
+	router.Use(RequestID(), gin.Logger(), gin.Recovery(), CORS(deps.CORS))
+
+	if deps.JWKSHandler != nil {
+		router.GET("/.well-known/jwks.json", deps.JWKSHandler.Get)
+	} else {
+		router.GET("/.well-known/jwks.json", notImplemented(canonicalRoute("GET /.well-known/jwks.json")))
+	}
 
 	api := router.Group("/api/v1")
+	tokenAuth := func(scopes ...string) gin.HandlerFunc {
+		return AuthRequiredWithTokens(deps.AccessTokenVerifier, deps.TokenAuthenticator, scopes...)
+	}
+	can := func(permissions ...policy.Permission) gin.HandlerFunc {
+		return RequirePermission(deps.Authorizer, deps.RolePolicy, permissions...)
+	}
 	{
 		api.GET("/healthz", func(c *gin.Context) {
 			if deps.HealthChecker != nil {
@@ -50,54 +87,292 @@ func NewRouter(logger *slog.Logger, deps RouterDependencies) *gin.Engine {
 
 		auth := api.Group("/auth")
 		{
+			limits := deps.AuthRateLimits
+			registerLimit := RateLimit(deps.RateLimitStore,
+				RateLimitRule{Name: "register", Limit: limits.RegisterIP, Key: ClientIPKey},
+			)
+			loginLimit := RateLimit(deps.RateLimitStore,
+				RateLimitRule{Name: "login", Limit: limits.LoginIP, Key: ClientIPKey},
+				RateLimitRule{Name: "login", Limit: limits.LoginAccount, Key: AccountEmailKey},
+			)
+			loginIPLimit := RateLimit(deps.RateLimitStore,
+				RateLimitRule{Name: "login", Limit: limits.LoginIP, Key: ClientIPKey},
+			)
+			resetLimit := RateLimit(deps.RateLimitStore,
+				RateLimitRule{Name: "password_reset", Limit: limits.PasswordResetIP, Key: ClientIPKey},
+				RateLimitRule{Name: "password_reset", Limit: limits.PasswordResetAccount, Key: AccountEmailKey},
+			)
+			verifyResendLimit := RateLimit(deps.RateLimitStore,
+				RateLimitRule{Name: "verify_email", Limit: limits.VerifyEmailAccount, Key: UserIDKey},
+			)
+
 			if deps.AuthHandler != nil {
-				auth.POST("/register", deps.AuthHandler.Register)
-				auth.POST("/login", deps.AuthHandler.Login)
+				auth.POST("/register", registerLimit, deps.AuthHandler.Register)
+				auth.POST("/login", loginLimit, deps.AuthHandler.Login)
+				auth.POST("/login/mfa", loginIPLimit, deps.AuthHandler.VerifyMFA)
 				auth.POST("/refresh", deps.AuthHandler.Refresh)
 				auth.POST("/logout", deps.AuthHandler.Logout)
-				auth.POST("/password-reset/request", deps.AuthHandler.RequestPasswordReset)
+				auth.POST("/password-reset/request", resetLimit, deps.AuthHandler.RequestPasswordReset)
 				auth.POST("/password-reset/confirm", deps.AuthHandler.ConfirmPasswordReset)
+				auth.POST("/email-change/confirm", deps.AuthHandler.ConfirmEmailChange)
+				auth.POST("/verify-email/confirm", deps.AuthHandler.VerifyEmail)
+				auth.POST("/verify-email/resend", AuthRequired(deps.AccessTokenVerifier), verifyResendLimit, deps.AuthHandler.ResendVerification)
 			} else {
 				auth.POST("/register", notImplemented(canonicalRoute("POST /auth/register")))
 				auth.POST("/login", notImplemented(canonicalRoute("POST /auth/login")))
+				auth.POST("/login/mfa", notImplemented(canonicalRoute("POST /auth/login/mfa")))
 				auth.POST("/refresh", notImplemented(canonicalRoute("POST /auth/refresh")))
 				auth.POST("/logout", notImplemented(canonicalRoute("POST /auth/logout")))
 				auth.POST("/password-reset/request", notImplemented(canonicalRoute("POST /auth/password-reset/request")))
 				auth.POST("/password-reset/confirm", notImplemented(canonicalRoute("POST /auth/password-reset/confirm")))
+				auth.POST("/email-change/confirm", notImplemented(canonicalRoute("POST /auth/email-change/confirm")))
+				auth.POST("/verify-email/confirm", notImplemented(canonicalRoute("POST /auth/verify-email/confirm")))
+				auth.POST("/verify-email/resend", notImplemented(canonicalRoute("POST /auth/verify-email/resend")))
+			}
+
+			if deps.OIDCHandler != nil {
+				auth.GET("/oidc/providers", deps.OIDCHandler.ListProviders)
+				auth.POST("/oidc/:provider/start", loginIPLimit, deps.OIDCHandler.Start)
+				auth.POST("/oidc/:provider/callback", loginIPLimit, deps.OIDCHandler.Callback)
+			} else {
+				auth.GET("/oidc/providers", notImplemented(canonicalRoute("GET /auth/oidc/providers")))
+				auth.POST("/oidc/:provider/start", notImplemented(canonicalRoute("POST /auth/oidc/:provider/start")))
+				auth.POST("/oidc/:provider/callback", notImplemented(canonicalRoute("POST /auth/oidc/:provider/callback")))
 			}
 		}
 
 		if deps.PostHandler != nil {
-			api.GET("/posts", deps.PostHandler.List)
-			api.GET("/posts/:id", deps.PostHandler.GetByID)
+			api.GET("/posts", OptionalAuth(deps.AccessTokenVerifier), deps.PostHandler.List)
+			api.GET("/posts/search", deps.PostHandler.Search)
+			api.GET("/posts/:id", OptionalAuth(deps.AccessTokenVerifier), deps.PostHandler.GetByID)
+			api.GET("/posts/by-slug/:slug", OptionalAuth(deps.AccessTokenVerifier), deps.PostHandler.GetBySlug)
 		} else {
 			api.GET("/posts", notImplemented(canonicalRoute("GET /posts")))
+			api.GET("/posts/search", notImplemented(canonicalRoute("GET /posts/search")))
 			api.GET("/posts/:id", notImplemented(canonicalRoute("GET /posts/:id")))
+			api.GET("/posts/by-slug/:slug", notImplemented(canonicalRoute("GET /posts/by-slug/:slug")))
 		}
 
 		postsWrite := api.Group("/posts")
-		postsWrite.Use(AuthRequired(deps.AccessTokenVerifier), RequireRoles("author", "admin"))
+		postsWrite.Use(tokenAuth(service.ScopePostsWrite), can(policy.PostCreate, policy.PostEditAny))
 		{
 			if deps.PostHandler != nil {
 				postsWrite.POST("", deps.PostHandler.Create)
 				postsWrite.PATCH("/:id", deps.PostHandler.Update)
 				postsWrite.DELETE("/:id", deps.PostHandler.Delete)
+				postsWrite.POST("/:id/restore", deps.PostHandler.Restore)
+				postsWrite.GET("/:id/revisions", deps.PostHandler.ListRevisions)
+				postsWrite.GET("/:id/revisions/:rev/diff", deps.PostHandler.DiffRevision)
+				postsWrite.POST("/:id/revisions/:rev/restore", deps.PostHandler.RestoreRevision)
 			} else {
 				postsWrite.POST("", notImplemented(canonicalRoute("POST /posts")))
 				postsWrite.PATCH("/:id", notImplemented(canonicalRoute("PATCH /posts/:id")))
 				postsWrite.DELETE("/:id", notImplemented(canonicalRoute("DELETE /posts/:id")))
+				postsWrite.POST("/:id/restore", notImplemented(canonicalRoute("POST /posts/:id/restore")))
+				postsWrite.GET("/:id/revisions", notImplemented(canonicalRoute("GET /posts/:id/revisions")))
+				postsWrite.GET("/:id/revisions/:rev/diff", notImplemented(canonicalRoute("GET /posts/:id/revisions/:rev/diff")))
+				postsWrite.POST("/:id/revisions/:rev/restore", notImplemented(canonicalRoute("POST /posts/:id/revisions/:rev/restore")))
 			}
 		}
 
-		admin := api.Group("/admin")
-		admin.Use(AuthRequired(deps.AccessTokenVerifier), RequireRoles("admin"))
+		postsReview := api.Group("/posts")
+		postsReview.Use(tokenAuth(service.ScopePostsWrite), can(policy.PostCreate, policy.PostEditAny, policy.PostReview))
+		{
+			if deps.PostHandler != nil {
+				postsReview.POST("/:id/review", deps.PostHandler.Review)
+				postsReview.GET("/:id/review", deps.PostHandler.ListReviewEvents)
+			} else {
+				postsReview.POST("/:id/review", notImplemented(canonicalRoute("POST /posts/:id/review")))
+				postsReview.GET("/:id/review", notImplemented(canonicalRoute("GET /posts/:id/review")))
+			}
+		}
+
+		reviewQueue := api.Group("/review-queue")
+		reviewQueue.Use(tokenAuth(service.ScopePostsRead), can(policy.PostReview))
+		{
+			if deps.PostHandler != nil {
+				reviewQueue.GET("", deps.PostHandler.ReviewQueue)
+			} else {
+				reviewQueue.GET("", notImplemented(canonicalRoute("GET /review-queue")))
+			}
+		}
+
+		if deps.CommentHandler != nil {
+			api.GET("/posts/:id/comments", OptionalAuth(deps.AccessTokenVerifier), deps.CommentHandler.List)
+			api.POST("/posts/:id/comments", AuthRequired(deps.AccessTokenVerifier), deps.CommentHandler.Create)
+		} else {
+			api.GET("/posts/:id/comments", notImplemented(canonicalRoute("GET /posts/:id/comments")))
+			api.POST("/posts/:id/comments", notImplemented(canonicalRoute("POST /posts/:id/comments")))
+		}
+
+		comments := api.Group("/comments")
+		comments.Use(AuthRequired(deps.AccessTokenVerifier))
+		{
+			if deps.CommentHandler != nil {
+				comments.PATCH("/:id", deps.CommentHandler.Update)
+				comments.DELETE("/:id", deps.CommentHandler.Delete)
+				comments.PATCH("/:id/status", deps.CommentHandler.Moderate)
+			} else {
+				comments.PATCH("/:id", notImplemented(canonicalRoute("PATCH /comments/:id")))
+				comments.DELETE("/:id", notImplemented(canonicalRoute("DELETE /comments/:id")))
+				comments.PATCH("/:id/status", notImplemented(canonicalRoute("PATCH /comments/:id/status")))
+			}
+		}
+
+		if deps.TaxonomyHandler != nil {
+			api.GET("/tags", deps.TaxonomyHandler.ListTags)
+			api.GET("/categories", deps.TaxonomyHandler.ListCategories)
+		} else {
+			api.GET("/tags", notImplemented(canonicalRoute("GET /tags")))
+			api.GET("/categories", notImplemented(canonicalRoute("GET /categories")))
+		}
+
+		feeds := api.Group("/feeds")
+		{
+			if deps.FeedHandler != nil {
+				for _, prefix := range []string{"", "/authors/:id", "/tags/:slug"} {
+					feeds.GET(prefix+"/rss.xml", deps.FeedHandler.RSS)
+					feeds.GET(prefix+"/atom.xml", deps.FeedHandler.Atom)
+					feeds.GET(prefix+"/feed.json", deps.FeedHandler.JSON)
+				}
+			} else {
+				feeds.GET("/rss.xml", notImplemented(canonicalRoute("GET /feeds/rss.xml")))
+				feeds.GET("/atom.xml", notImplemented(canonicalRoute("GET /feeds/atom.xml")))
+				feeds.GET("/feed.json", notImplemented(canonicalRoute("GET /feeds/feed.json")))
+			}
+		}
+
+		myPosts := api.Group("/me")
+		myPosts.Use(tokenAuth(service.ScopePostsRead))
+		{
+			if deps.PostHandler != nil {
+				myPosts.GET("/posts", deps.PostHandler.ListMine)
+				myPosts.GET("/trash", deps.PostHandler.ListTrash)
+			} else {
+				myPosts.GET("/posts", notImplemented(canonicalRoute("GET /me/posts")))
+				myPosts.GET("/trash", notImplemented(canonicalRoute("GET /me/trash")))
+			}
+		}
+
+		me := api.Group("/me")
+		me.Use(AuthRequired(deps.AccessTokenVerifier))
+		{
+			emailChangeLimit := RateLimit(deps.RateLimitStore,
+				RateLimitRule{Name: "email_change", Limit: deps.AuthRateLimits.EmailChangeAccount, Key: UserIDKey},
+			)
+
+			if deps.AuthHandler != nil {
+				me.POST("/password", deps.AuthHandler.ChangePassword)
+				me.POST("/email", emailChangeLimit, deps.AuthHandler.RequestEmailChange)
+				me.GET("/2fa", deps.AuthHandler.TwoFactorStatus)
+				me.POST("/2fa/setup", deps.AuthHandler.SetupTOTP)
+				me.POST("/2fa/enable", deps.AuthHandler.EnableTOTP)
+				me.POST("/2fa/disable", deps.AuthHandler.DisableTOTP)
+			} else {
+				me.POST("/password", notImplemented(canonicalRoute("POST /me/password")))
+				me.POST("/email", notImplemented(canonicalRoute("POST /me/email")))
+				me.GET("/2fa", notImplemented(canonicalRoute("GET /me/2fa")))
+				me.POST("/2fa/setup", notImplemented(canonicalRoute("POST /me/2fa/setup")))
+				me.POST("/2fa/enable", notImplemented(canonicalRoute("POST /me/2fa/enable")))
+				me.POST("/2fa/disable", notImplemented(canonicalRoute("POST /me/2fa/disable")))
+			}
+
+			if deps.SessionHandler != nil {
+				me.GET("/sessions", deps.SessionHandler.ListMine)
+				me.DELETE("/sessions/:id", deps.SessionHandler.RevokeMine)
+				me.POST("/sessions/revoke-all", deps.SessionHandler.RevokeAllMine)
+			} else {
+				me.GET("/sessions", notImplemented(canonicalRoute("GET /me/sessions")))
+				me.DELETE("/sessions/:id", notImplemented(canonicalRoute("DELETE /me/sessions/:id")))
+				me.POST("/sessions/revoke-all", notImplemented(canonicalRoute("POST /me/sessions/revoke-all")))
+			}
+
+			if deps.TokenHandler != nil {
+				me.POST("/tokens", deps.TokenHandler.Create)
+				me.GET("/tokens", deps.TokenHandler.List)
+				me.DELETE("/tokens/:id", deps.TokenHandler.Revoke)
+			} else {
+				me.POST("/tokens", notImplemented(canonicalRoute("POST /me/tokens")))
+				me.GET("/tokens", notImplemented(canonicalRoute("GET /me/tokens")))
+				me.DELETE("/tokens/:id", notImplemented(canonicalRoute("DELETE /me/tokens/:id")))
+			}
+		}
+
+		adminUsers := api.Group("/admin/users")
+		adminUsers.Use(tokenAuth(service.ScopeAdminUsers))
 		{
+			manageUsers := can(policy.UserManage)
 			if deps.AdminHandler != nil {
-				admin.GET("/users", deps.AdminHandler.ListUsers)
-				admin.PATCH("/users/:id/role", deps.AdminHandler.UpdateRole)
+				adminUsers.GET("", manageUsers, deps.AdminHandler.ListUsers)
+				adminUsers.PATCH("/:id/role", can(policy.UserRoleAssign), deps.AdminHandler.UpdateRole)
+				adminUsers.POST("/:id/unlock", manageUsers, deps.AdminHandler.UnlockUser)
+			} else {
+				adminUsers.GET("", manageUsers, notImplemented(canonicalRoute("GET /admin/users")))
+				adminUsers.PATCH("/:id/role", can(policy.UserRoleAssign), notImplemented(canonicalRoute("PATCH /admin/users/:id/role")))
+				adminUsers.POST("/:id/unlock", manageUsers, notImplemented(canonicalRoute("POST /admin/users/:id/unlock")))
+			}
+
+			if deps.SessionHandler != nil {
+				adminUsers.GET("/:id/sessions", manageUsers, deps.SessionHandler.ListForUser)
+				adminUsers.DELETE("/:id/sessions/:session_id", manageUsers, deps.SessionHandler.RevokeForUser)
+				adminUsers.POST("/:id/sessions/revoke-all", manageUsers, deps.SessionHandler.RevokeAllForUser)
+			} else {
+				adminUsers.GET("/:id/sessions", manageUsers, notImplemented(canonicalRoute("GET /admin/users/:id/sessions")))
+				adminUsers.DELETE("/:id/sessions/:session_id", manageUsers, notImplemented(canonicalRoute("DELETE /admin/users/:id/sessions/:session_id")))
+				adminUsers.POST("/:id/sessions/revoke-all", manageUsers, notImplemented(canonicalRoute("POST /admin/users/:id/sessions/revoke-all")))
+			}
+		}
+
+		admin := api.Group("/admin")
+		admin.Use(AuthRequired(deps.AccessTokenVerifier))
+		{
+			purge := can(policy.PostPurge)
+			if deps.PostHandler != nil {
+				admin.DELETE("/posts/:id", purge, deps.PostHandler.Purge)
+			} else {
+				admin.DELETE("/posts/:id", purge, notImplemented(canonicalRoute("DELETE /admin/posts/:id")))
+			}
+
+			moderate := can(policy.CommentModerate)
+			if deps.CommentHandler != nil {
+				admin.GET("/comments", moderate, deps.CommentHandler.ListModerationQueue)
+				admin.PATCH("/comments/:id", moderate, deps.CommentHandler.Moderate)
+			} else {
+				admin.GET("/comments", moderate, notImplemented(canonicalRoute("GET /admin/comments")))
+				admin.PATCH("/comments/:id", moderate, notImplemented(canonicalRoute("PATCH /admin/comments/:id")))
+			}
+
+			taxonomy := can(policy.TaxonomyManage)
+			if deps.TaxonomyHandler != nil {
+				admin.PATCH("/tags/:id", taxonomy, deps.TaxonomyHandler.RenameTag)
+				admin.POST("/tags/:id/merge", taxonomy, deps.TaxonomyHandler.MergeTags)
+				admin.POST("/categories", taxonomy, deps.TaxonomyHandler.CreateCategory)
+			} else {
+				admin.PATCH("/tags/:id", taxonomy, notImplemented(canonicalRoute("PATCH /admin/tags/:id")))
+				admin.POST("/tags/:id/merge", taxonomy, notImplemented(canonicalRoute("POST /admin/tags/:id/merge")))
+				admin.POST("/categories", taxonomy, notImplemented(canonicalRoute("POST /admin/categories")))
+			}
+
+			manageRoles := can(policy.RoleManage)
+			if deps.RoleHandler != nil {
+				admin.GET("/roles", manageRoles, deps.RoleHandler.List)
+				admin.POST("/roles", manageRoles, deps.RoleHandler.Create)
+				admin.PUT("/roles/:name/permissions", manageRoles, deps.RoleHandler.SetPermissions)
+				admin.DELETE("/roles/:name", manageRoles, deps.RoleHandler.Delete)
+			} else {
+				admin.GET("/roles", manageRoles, notImplemented(canonicalRoute("GET /admin/roles")))
+				admin.POST("/roles", manageRoles, notImplemented(canonicalRoute("POST /admin/roles")))
+				admin.PUT("/roles/:name/permissions", manageRoles, notImplemented(canonicalRoute("PUT /admin/roles/:name/permissions")))
+				admin.DELETE("/roles/:name", manageRoles, notImplemented(canonicalRoute("DELETE /admin/roles/:name")))
+			}
+
+			readAudit := can(policy.AuditRead)
+			if deps.AuditHandler != nil {
+				admin.GET("/audit", readAudit, deps.AuditHandler.List)
+				admin.GET("/audit/export", readAudit, deps.AuditHandler.Export)
 			} else {
-				admin.GET("/users", notImplemented(canonicalRoute("GET /admin/users")))
-				admin.PATCH("/users/:id/role", notImplemented(canonicalRoute("PATCH /admin/users/:id/role")))
+				admin.GET("/audit", readAudit, notImplemented(canonicalRoute("GET /admin/audit")))
+				admin.GET("/audit/export", readAudit, notImplemented(canonicalRoute("GET /admin/audit/export")))
 			}
 		}
 	}
@@ -8,8 +8,12 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+	"github.com/gin-gonic/gin"
 )
 
 type fakeHealthChecker struct {
@@ -96,3 +100,191 @@ func TestNotImplementedShape(t *testing.T) {
 		t.Fatalf("expected code not_implemented, got %v", errObj["code"])
 	}
 }
+
+func TestClientIPIgnoresForwardedForWithoutTrustedProxies(t *testing.T) {
+	cases := map[string]struct {
+		proxies []string
+		want    string
+	}{
+		"no proxies configured": {want: "198.51.100.7"},
+		"socket is a trusted proxy": {
+			proxies: []string{"198.51.100.0/24"},
+			want:    "203.0.113.9",
+		},
+	}
+	for name, tc := range cases {
+		r := NewRouter(slog.Default(), RouterDependencies{
+			HealthCheckTimeout: time.Second,
+			TrustedProxies:     tc.proxies,
+		})
+		var seen service.RequestInfo
+		r.GET("/ip", func(c *gin.Context) {
+			seen = service.RequestInfoFrom(c.Request.Context())
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		req.RemoteAddr = "198.51.100.7:4242"
+		req.Header.Set("X-Forwarded-For", "203.0.113.9")
+		r.ServeHTTP(w, req)
+
+		if seen.IPAddress != tc.want {
+			t.Fatalf("%s: expected client IP %q, got %q", name, tc.want, seen.IPAddress)
+		}
+	}
+}
+
+func TestPostSlugRouteCoexistsWithIDRoute(t *testing.T) {
+	r := NewRouter(slog.Default(), RouterDependencies{
+		HealthCheckTimeout: time.Second,
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/posts/by-slug/hello", nil)
+	r.ServeHTTP(w, req)
+
+	var payload map[string]map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("failed to parse response JSON: %v", err)
+	}
+	details, _ := payload["error"]["details"].(map[string]any)
+	if details["route"] != "GET /posts/by-slug/:slug" {
+		t.Fatalf("expected by-slug route to be matched, got %v", payload)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	cases := []struct {
+		name            string
+		cfg             CORSConfig
+		method          string
+		origin          string
+		requestMethod   string
+		wantStatus      int
+		wantAllowOrigin string
+		wantCredentials bool
+	}{
+		{
+			name:            "exact origin on simple request",
+			cfg:             CORSConfig{AllowedOrigins: []string{"https://blog.example.com"}},
+			method:          http.MethodGet,
+			origin:          "https://blog.example.com",
+			wantStatus:      http.StatusOK,
+			wantAllowOrigin: "https://blog.example.com",
+		},
+		{
+			name:       "unknown origin gets no headers",
+			cfg:        CORSConfig{AllowedOrigins: []string{"https://blog.example.com"}},
+			method:     http.MethodGet,
+			origin:     "https://evil.example.net",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:            "wildcard subdomain preflight",
+			cfg:             CORSConfig{AllowedOrigins: []string{"https://*.example.com"}},
+			method:          http.MethodOptions,
+			origin:          "https://blog-b.eb.example.com",
+			requestMethod:   http.MethodPatch,
+			wantStatus:      http.StatusNoContent,
+			wantAllowOrigin: "https://blog-b.eb.example.com",
+		},
+		{
+			name:          "wildcard does not match apex or other scheme",
+			cfg:           CORSConfig{AllowedOrigins: []string{"https://*.example.com"}},
+			method:        http.MethodOptions,
+			origin:        "http://blog.example.com",
+			requestMethod: http.MethodGet,
+			wantStatus:    http.StatusForbidden,
+		},
+		{
+			name:          "wildcard does not match lookalike suffix",
+			cfg:           CORSConfig{AllowedOrigins: []string{"https://*.example.com"}},
+			method:        http.MethodOptions,
+			origin:        "https://blogexample.com",
+			requestMethod: http.MethodGet,
+			wantStatus:    http.StatusForbidden,
+		},
+		{
+			name:            "any origin without credentials",
+			cfg:             CORSConfig{AllowedOrigins: []string{"*"}},
+			method:          http.MethodGet,
+			origin:          "https://anywhere.test",
+			wantStatus:      http.StatusOK,
+			wantAllowOrigin: "*",
+		},
+		{
+			name:            "credentials reflect the origin",
+			cfg:             CORSConfig{AllowedOrigins: []string{"https://blog.example.com"}, AllowCredentials: true},
+			method:          http.MethodOptions,
+			origin:          "https://blog.example.com",
+			requestMethod:   http.MethodPost,
+			wantStatus:      http.StatusNoContent,
+			wantAllowOrigin: "https://blog.example.com",
+			wantCredentials: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewRouter(slog.Default(), RouterDependencies{
+				HealthChecker:      fakeHealthChecker{},
+				HealthCheckTimeout: time.Second,
+				CORS:               tc.cfg,
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, "/api/v1/healthz", nil)
+			req.Header.Set("Origin", tc.origin)
+			if tc.requestMethod != "" {
+				req.Header.Set("Access-Control-Request-Method", tc.requestMethod)
+			}
+			r.ServeHTTP(w, req)
+
+			if w.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d", tc.wantStatus, w.Code)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tc.wantAllowOrigin {
+				t.Fatalf("expected Access-Control-Allow-Origin %q, got %q", tc.wantAllowOrigin, got)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Credentials") == "true"; got != tc.wantCredentials {
+				t.Fatalf("expected credentials %v, got %v", tc.wantCredentials, got)
+			}
+			if tc.wantAllowOrigin == "" {
+				return
+			}
+			if tc.requestMethod != "" {
+				if !strings.Contains(w.Header().Get("Access-Control-Allow-Methods"), tc.requestMethod) {
+					t.Fatalf("expected %s in allowed methods, got %q", tc.requestMethod, w.Header().Get("Access-Control-Allow-Methods"))
+				}
+			} else {
+				for _, exposed := range []string{"ETag", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After"} {
+					if !strings.Contains(w.Header().Get("Access-Control-Expose-Headers"), exposed) {
+						t.Fatalf("expected %s to be exposed, got %q", exposed, w.Header().Get("Access-Control-Expose-Headers"))
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCORSPreflightReachesUnregisteredMethods(t *testing.T) {
+	r := NewRouter(slog.Default(), RouterDependencies{
+		HealthCheckTimeout: time.Second,
+		CORS:               CORSConfig{AllowedOrigins: []string{"http://localhost:5173"}},
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/posts/p1", nil)
+	req.Header.Set("Origin", "http://localhost:5173")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPatch)
+	req.Header.Set("Access-Control-Request-Headers", "authorization, content-type")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Headers"); got != "authorization, content-type" {
+		t.Fatalf("expected requested headers to be allowed, got %q", got)
+	}
+}
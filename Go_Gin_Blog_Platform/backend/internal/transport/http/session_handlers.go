@@ -0,0 +1,125 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type SessionService interface {
+	List(ctx context.Context, userID, currentSessionID string) ([]service.SessionItem, error)
+	Revoke(ctx context.Context, actorID, userID, sessionID string) error
+	RevokeAll(ctx context.Context, actorID, userID string) error
+}
+
+// SessionHandler serves the caller's own sessions under /me/sessions and, for
+// admins, any user's sessions under /admin/users/:id/sessions.
+type SessionHandler struct {
+	sessionService SessionService
+}
+
+func NewSessionHandler(sessionService SessionService) *SessionHandler {
+	return &SessionHandler{sessionService: sessionService}
+}
+
+func (h *SessionHandler) ListMine(c *gin.Context) {
+	userID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	sessions, err := h.sessionService.List(c.Request.Context(), userID, currentSessionFromContext(c))
+	if err != nil {
+		handleSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": sessions})
+}
+
+func (h *SessionHandler) RevokeMine(c *gin.Context) {
+	userID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	if err := h.sessionService.Revoke(c.Request.Context(), userID, userID, c.Param("id")); err != nil {
+		handleSessionError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *SessionHandler) RevokeAllMine(c *gin.Context) {
+	userID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	if err := h.sessionService.RevokeAll(c.Request.Context(), userID, userID); err != nil {
+		handleSessionError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *SessionHandler) ListForUser(c *gin.Context) {
+	sessions, err := h.sessionService.List(c.Request.Context(), c.Param("id"), "")
+	if err != nil {
+		handleSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": sessions})
+}
+
+func (h *SessionHandler) RevokeForUser(c *gin.Context) {
+	actorID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	if err := h.sessionService.Revoke(c.Request.Context(), actorID, c.Param("id"), c.Param("session_id")); err != nil {
+		handleSessionError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *SessionHandler) RevokeAllForUser(c *gin.Context) {
+	actorID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	if err := h.sessionService.RevokeAll(c.Request.Context(), actorID, c.Param("id")); err != nil {
+		handleSessionError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func handleSessionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		writeError(c, http.StatusBadRequest, "validation_error", "Request validation failed", gin.H{"reason": err.Error()})
+	case errors.Is(err, service.ErrUserNotFound):
+		writeError(c, http.StatusNotFound, "user_not_found", "User was not found", nil)
+	case errors.Is(err, service.ErrSessionNotFound):
+		writeError(c, http.StatusNotFound, "session_not_found", "Session was not found", nil)
+	default:
+		writeError(c, http.StatusInternalServerError, "internal_error", "Unexpected server error", nil)
+	}
+}
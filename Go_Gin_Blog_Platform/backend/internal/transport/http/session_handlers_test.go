@@ -0,0 +1,126 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type fakeSessionService struct {
+	revoked *[]string
+}
+
+func (f fakeSessionService) List(_ context.Context, userID, currentSessionID string) ([]service.SessionItem, error) {
+	if userID == "missing" {
+		return nil, service.ErrUserNotFound
+	}
+	return []service.SessionItem{
+		{ID: "s1", UserAgent: "Firefox", Current: currentSessionID == "s1"},
+		{ID: "s2", UserAgent: "Safari", Current: currentSessionID == "s2"},
+	}, nil
+}
+
+func (f fakeSessionService) Revoke(_ context.Context, actorID, userID, sessionID string) error {
+	if sessionID != "s1" && sessionID != "s2" {
+		return service.ErrSessionNotFound
+	}
+	*f.revoked = append(*f.revoked, actorID+">"+userID+"/"+sessionID)
+	return nil
+}
+
+func (f fakeSessionService) RevokeAll(_ context.Context, actorID, userID string) error {
+	*f.revoked = append(*f.revoked, actorID+">"+userID+"/*")
+	return nil
+}
+
+func TestSessionsListMineMarksCurrentSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewSessionHandler(fakeSessionService{})
+	verifier := fakeVerifier{claims: &auth.AccessClaims{Role: "reader", SessionID: "s2", RegisteredClaims: jwt.RegisteredClaims{Subject: "u1"}}}
+	r.GET("/me/sessions", AuthRequired(verifier), h.ListMine)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/me/sessions", nil)
+	req.Header.Set("Authorization", "Bearer test")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var payload struct {
+		Data []service.SessionItem `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("expected json response: %v", err)
+	}
+	if len(payload.Data) != 2 || payload.Data[0].Current || !payload.Data[1].Current {
+		t.Fatalf("expected s2 to be current: %s", w.Body.String())
+	}
+}
+
+func TestSessionsRevokeRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	var revoked []string
+	h := NewSessionHandler(fakeSessionService{revoked: &revoked})
+	verifier := fakeVerifier{claims: &auth.AccessClaims{Role: "admin", RegisteredClaims: jwt.RegisteredClaims{Subject: "a1"}}}
+	r.DELETE("/me/sessions/:id", AuthRequired(verifier), h.RevokeMine)
+	r.POST("/me/sessions/revoke-all", AuthRequired(verifier), h.RevokeAllMine)
+	r.DELETE("/admin/users/:id/sessions/:session_id", AuthRequired(verifier), h.RevokeForUser)
+	r.POST("/admin/users/:id/sessions/revoke-all", AuthRequired(verifier), h.RevokeAllForUser)
+
+	cases := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{http.MethodDelete, "/me/sessions/s1", http.StatusNoContent},
+		{http.MethodDelete, "/me/sessions/unknown", http.StatusNotFound},
+		{http.MethodPost, "/me/sessions/revoke-all", http.StatusNoContent},
+		{http.MethodDelete, "/admin/users/u2/sessions/s2", http.StatusNoContent},
+		{http.MethodPost, "/admin/users/u2/sessions/revoke-all", http.StatusNoContent},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		req.Header.Set("Authorization", "Bearer test")
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.want {
+			t.Fatalf("%s %s: expected status %d, got %d", tc.method, tc.path, tc.want, w.Code)
+		}
+	}
+
+	want := []string{"a1>a1/s1", "a1>a1/*", "a1>u2/s2", "a1>u2/*"}
+	if len(revoked) != len(want) {
+		t.Fatalf("expected revocations %v, got %v", want, revoked)
+	}
+	for i := range want {
+		if revoked[i] != want[i] {
+			t.Fatalf("expected revocations %v, got %v", want, revoked)
+		}
+	}
+}
+
+func TestSessionsAdminListUnknownUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewSessionHandler(fakeSessionService{})
+	r.GET("/admin/users/:id/sessions", h.ListForUser)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/admin/users/missing/sessions", nil)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", w.Code)
+	}
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type TaxonomyService interface {
+	ListTags(ctx context.Context) ([]service.TagSummary, error)
+	RenameTag(ctx context.Context, actorID, tagID, name string) (service.TagSummary, error)
+	MergeTags(ctx context.Context, actorID, sourceID, targetID string) error
+	ListCategories(ctx context.Context) ([]service.CategoryNode, error)
+	CreateCategory(ctx context.Context, input service.CreateCategoryInput) (service.CategoryNode, error)
+}
+
+type TaxonomyHandler struct {
+	taxonomyService TaxonomyService
+}
+
+func NewTaxonomyHandler(taxonomyService TaxonomyService) *TaxonomyHandler {
+	return &TaxonomyHandler{taxonomyService: taxonomyService}
+}
+
+type renameTagRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type mergeTagRequest struct {
+	TargetID string `json:"target_id" binding:"required"`
+}
+
+type createCategoryRequest struct {
+	Name     string `json:"name" binding:"required"`
+	ParentID string `json:"parent_id"`
+}
+
+func (h *TaxonomyHandler) ListTags(c *gin.Context) {
+	tags, err := h.taxonomyService.ListTags(c.Request.Context())
+	if err != nil {
+		handleTaxonomyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tags})
+}
+
+func (h *TaxonomyHandler) ListCategories(c *gin.Context) {
+	categories, err := h.taxonomyService.ListCategories(c.Request.Context())
+	if err != nil {
+		handleTaxonomyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": categories})
+}
+
+func (h *TaxonomyHandler) RenameTag(c *gin.Context) {
+	var req renameTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
+	actorID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	tag, err := h.taxonomyService.RenameTag(c.Request.Context(), actorID, c.Param("id"), req.Name)
+	if err != nil {
+		handleTaxonomyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tag})
+}
+
+func (h *TaxonomyHandler) MergeTags(c *gin.Context) {
+	var req mergeTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
+	actorID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	if err := h.taxonomyService.MergeTags(c.Request.Context(), actorID, c.Param("id"), req.TargetID); err != nil {
+		handleTaxonomyError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *TaxonomyHandler) CreateCategory(c *gin.Context) {
+	var req createCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
+	category, err := h.taxonomyService.CreateCategory(c.Request.Context(), service.CreateCategoryInput{
+		Name:     req.Name,
+		ParentID: req.ParentID,
+	})
+	if err != nil {
+		handleTaxonomyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": category})
+}
+
+func handleTaxonomyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		writeError(c, http.StatusBadRequest, "validation_error", "Request validation failed", gin.H{"reason": err.Error()})
+	case errors.Is(err, service.ErrTagNotFound):
+		writeError(c, http.StatusNotFound, "tag_not_found", "Tag was not found", nil)
+	case errors.Is(err, service.ErrCategoryNotFound):
+		writeError(c, http.StatusNotFound, "category_not_found", "Category was not found", nil)
+	case errors.Is(err, service.ErrConflict):
+		writeError(c, http.StatusConflict, "conflict", "Request conflicts with existing data", gin.H{"reason": err.Error()})
+	default:
+		writeError(c, http.StatusInternalServerError, "internal_error", "Unexpected server error", nil)
+	}
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/auth"
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type fakeTaxonomyService struct{}
+
+func (f fakeTaxonomyService) ListTags(context.Context) ([]service.TagSummary, error) {
+	return []service.TagSummary{{ID: "t1", Name: "Go", Slug: "go", PostCount: 3}}, nil
+}
+
+func (f fakeTaxonomyService) RenameTag(_ context.Context, _, tagID, name string) (service.TagSummary, error) {
+	if name == "taken" {
+		return service.TagSummary{}, service.ErrConflict
+	}
+	return service.TagSummary{ID: tagID, Name: name, Slug: name}, nil
+}
+
+func (f fakeTaxonomyService) MergeTags(_ context.Context, _, _, targetID string) error {
+	if targetID == "missing" {
+		return service.ErrTagNotFound
+	}
+	return nil
+}
+
+func (f fakeTaxonomyService) ListCategories(context.Context) ([]service.CategoryNode, error) {
+	return []service.CategoryNode{}, nil
+}
+
+func (f fakeTaxonomyService) CreateCategory(_ context.Context, input service.CreateCategoryInput) (service.CategoryNode, error) {
+	return service.CategoryNode{ID: "c1", Name: input.Name, Slug: "c", Children: []service.CategoryNode{}}, nil
+}
+
+func TestTaxonomyListTagsIncludesCounts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewTaxonomyHandler(fakeTaxonomyService{})
+	r.GET("/tags", h.ListTags)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tags", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var payload struct {
+		Data []map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("expected json response: %v", err)
+	}
+	if len(payload.Data) != 1 || payload.Data[0]["post_count"] != float64(3) {
+		t.Fatalf("unexpected tags payload: %+v", payload.Data)
+	}
+}
+
+func TestTaxonomyRenameTagConflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewTaxonomyHandler(fakeTaxonomyService{})
+	verifier := fakeVerifier{claims: &auth.AccessClaims{Role: "admin", RegisteredClaims: jwt.RegisteredClaims{Subject: "a1"}}}
+	r.PATCH("/admin/tags/:id", AuthRequired(verifier), h.RenameTag)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPatch, "/admin/tags/t1", strings.NewReader(`{"name":"taken"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer test")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d", w.Code)
+	}
+}
+
+func TestTaxonomyMergeTagsNotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewTaxonomyHandler(fakeTaxonomyService{})
+	verifier := fakeVerifier{claims: &auth.AccessClaims{Role: "admin", RegisteredClaims: jwt.RegisteredClaims{Subject: "a1"}}}
+	r.POST("/admin/tags/:id/merge", AuthRequired(verifier), h.MergeTags)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/admin/tags/t1/merge", strings.NewReader(`{"target_id":"missing"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer test")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", w.Code)
+	}
+}
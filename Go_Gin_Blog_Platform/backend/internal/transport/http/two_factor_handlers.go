@@ -0,0 +1,124 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/darshvaidya/dynamic-blog-websites/go-gin-blog-platform/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type verifyMFARequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type enableTOTPRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type disableTOTPRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	Code            string `json:"code" binding:"required"`
+}
+
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req verifyMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
+	user, tokenPair, err := h.authService.VerifyMFA(c.Request.Context(), service.VerifyMFAInput{
+		MFAToken:     req.MFAToken,
+		Code:         req.Code,
+		RecoveryCode: req.RecoveryCode,
+		IPAddress:    c.ClientIP(),
+		UserAgent:    c.Request.UserAgent(),
+	})
+	if err != nil {
+		handleAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": user, "tokens": tokenPair})
+}
+
+func (h *AuthHandler) TwoFactorStatus(c *gin.Context) {
+	userID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	status, err := h.authService.TwoFactorStatus(c.Request.Context(), userID)
+	if err != nil {
+		handleAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+func (h *AuthHandler) SetupTOTP(c *gin.Context) {
+	userID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	setup, err := h.authService.SetupTOTP(c.Request.Context(), userID)
+	if err != nil {
+		handleAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, setup)
+}
+
+func (h *AuthHandler) EnableTOTP(c *gin.Context) {
+	var req enableTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
+	userID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	codes, err := h.authService.EnableTOTP(c.Request.Context(), service.EnableTOTPInput{UserID: userID, Code: req.Code})
+	if err != nil {
+		handleAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+func (h *AuthHandler) DisableTOTP(c *gin.Context) {
+	var req disableTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
+	userID, _, ok := currentUserFromContext(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "Authentication context is missing", nil)
+		return
+	}
+
+	if err := h.authService.DisableTOTP(c.Request.Context(), service.DisableTOTPInput{
+		UserID:          userID,
+		CurrentPassword: req.CurrentPassword,
+		Code:            req.Code,
+	}); err != nil {
+		handleAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication has been disabled"})
+}
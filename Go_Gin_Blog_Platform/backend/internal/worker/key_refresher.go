@@ -0,0 +1,38 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type KeyReloader interface {
+	Reload(ctx context.Context) error
+}
+
+// KeyRefresher reloads the JWT signing keys so that a rotation made by another
+// instance, or by the keys command, is picked up without a restart.
+type KeyRefresher struct {
+	logger   *slog.Logger
+	keys     KeyReloader
+	interval time.Duration
+
+	loop loop
+}
+
+func NewKeyRefresher(logger *slog.Logger, keys KeyReloader, interval time.Duration) *KeyRefresher {
+	return &KeyRefresher{logger: logger, keys: keys, interval: interval}
+}
+
+func (r *KeyRefresher) Start(ctx context.Context) {
+	r.loop.start(ctx, r.interval, func(ctx context.Context) {
+		if err := r.keys.Reload(ctx); err != nil && ctx.Err() == nil {
+			// The ring keeps its last good keys, so tokens still verify.
+			r.logger.Error("signing key reload failed", "error", err)
+		}
+	})
+}
+
+func (r *KeyRefresher) Stop(ctx context.Context) error {
+	return r.loop.stop(ctx)
+}
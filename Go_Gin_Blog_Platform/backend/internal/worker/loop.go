@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"context"
+	"time"
+)
+
+// loop runs tick immediately and then every interval until stopped.
+type loop struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (l *loop) start(ctx context.Context, interval time.Duration, tick func(ctx context.Context)) {
+	ctx, l.cancel = context.WithCancel(ctx)
+	l.done = make(chan struct{})
+
+	go func() {
+		defer close(l.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			tick(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// stop cancels the loop and waits for the current tick to return, giving up
+// when ctx expires.
+func (l *loop) stop(ctx context.Context) error {
+	if l.cancel == nil {
+		return nil
+	}
+	l.cancel()
+
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,38 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type PolicyReloader interface {
+	Reload(ctx context.Context) error
+}
+
+// PolicyRefresher reloads role permissions so that an edit made through
+// another instance takes effect here without a restart.
+type PolicyRefresher struct {
+	logger   *slog.Logger
+	policy   PolicyReloader
+	interval time.Duration
+
+	loop loop
+}
+
+func NewPolicyRefresher(logger *slog.Logger, policy PolicyReloader, interval time.Duration) *PolicyRefresher {
+	return &PolicyRefresher{logger: logger, policy: policy, interval: interval}
+}
+
+func (r *PolicyRefresher) Start(ctx context.Context) {
+	r.loop.start(ctx, r.interval, func(ctx context.Context) {
+		if err := r.policy.Reload(ctx); err != nil && ctx.Err() == nil {
+			// The policy keeps its last loaded grants.
+			r.logger.Error("role permission reload failed", "error", err)
+		}
+	})
+}
+
+func (r *PolicyRefresher) Stop(ctx context.Context) error {
+	return r.loop.stop(ctx)
+}
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const publishBatchSize = 100
+
+type DuePostPublisher interface {
+	PublishDue(ctx context.Context, now time.Time, limit int) ([]string, error)
+}
+
+// Publisher periodically flips scheduled posts to published once their
+// publish_at has passed. Every replica may run one; the repository's row
+// locking keeps them from publishing the same post twice.
+type Publisher struct {
+	logger   *slog.Logger
+	posts    DuePostPublisher
+	interval time.Duration
+	now      func() time.Time
+
+	loop loop
+}
+
+func NewPublisher(logger *slog.Logger, posts DuePostPublisher, interval time.Duration) *Publisher {
+	return &Publisher{
+		logger:   logger,
+		posts:    posts,
+		interval: interval,
+		now:      func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Start runs the publisher in the background until Stop is called or ctx is
+// cancelled. The first pass runs immediately.
+func (p *Publisher) Start(ctx context.Context) {
+	p.loop.start(ctx, p.interval, func(ctx context.Context) {
+		if _, err := p.PublishDue(ctx); err != nil && ctx.Err() == nil {
+			p.logger.Error("scheduled publish failed", "error", err)
+		}
+	})
+}
+
+// Stop cancels the background loop and waits for it to exit, giving up when ctx
+// expires.
+func (p *Publisher) Stop(ctx context.Context) error {
+	return p.loop.stop(ctx)
+}
+
+// PublishDue publishes every post that is due, in batches, and returns how many
+// were published.
+func (p *Publisher) PublishDue(ctx context.Context) (int, error) {
+	published := 0
+	for {
+		ids, err := p.posts.PublishDue(ctx, p.now(), publishBatchSize)
+		if err != nil {
+			return published, err
+		}
+		published += len(ids)
+		for _, id := range ids {
+			p.logger.Info("scheduled post published", "post_id", id)
+		}
+		if len(ids) < publishBatchSize {
+			return published, nil
+		}
+	}
+}
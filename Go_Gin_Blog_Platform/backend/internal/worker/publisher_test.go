@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeDuePosts struct {
+	mu      sync.Mutex
+	batches [][]string
+	calls   int
+	lastNow time.Time
+	called  chan struct{}
+}
+
+func (f *fakeDuePosts) PublishDue(_ context.Context, now time.Time, limit int) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls++
+	f.lastNow = now
+	if f.called != nil {
+		select {
+		case f.called <- struct{}{}:
+		default:
+		}
+	}
+	if len(f.batches) == 0 {
+		return nil, nil
+	}
+	batch := f.batches[0]
+	f.batches = f.batches[1:]
+	if len(batch) > limit {
+		return nil, errors.New("batch exceeds limit")
+	}
+	return batch, nil
+}
+
+func batchOf(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("p%d", i)
+	}
+	return ids
+}
+
+func TestPublisherDrainsFullBatches(t *testing.T) {
+	posts := &fakeDuePosts{batches: [][]string{batchOf(publishBatchSize), batchOf(3)}}
+	publisher := NewPublisher(slog.Default(), posts, time.Minute)
+	fixed := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	publisher.now = func() time.Time { return fixed }
+
+	published, err := publisher.PublishDue(context.Background())
+	if err != nil {
+		t.Fatalf("expected publish to succeed: %v", err)
+	}
+	if published != publishBatchSize+3 {
+		t.Fatalf("expected %d published, got %d", publishBatchSize+3, published)
+	}
+	if posts.calls != 2 {
+		t.Fatalf("expected a second batch after a full one, got %d calls", posts.calls)
+	}
+	if !posts.lastNow.Equal(fixed) {
+		t.Fatalf("expected publisher clock to be passed through, got %v", posts.lastNow)
+	}
+}
+
+func TestPublisherStartRunsImmediatelyAndStops(t *testing.T) {
+	posts := &fakeDuePosts{called: make(chan struct{}, 1)}
+	publisher := NewPublisher(slog.Default(), posts, time.Hour)
+
+	publisher.Start(context.Background())
+	select {
+	case <-posts.called:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected publisher to run on start")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := publisher.Stop(ctx); err != nil {
+		t.Fatalf("expected publisher to stop cleanly: %v", err)
+	}
+}
+
+func TestPublisherStopWithoutStart(t *testing.T) {
+	publisher := NewPublisher(slog.Default(), &fakeDuePosts{}, time.Minute)
+	if err := publisher.Stop(context.Background()); err != nil {
+		t.Fatalf("expected stop without start to be a no-op: %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const throttlePruneInterval = time.Hour
+
+type LoginThrottlePruner interface {
+	PruneLoginThrottles(ctx context.Context) (int64, error)
+}
+
+// ThrottlePruner deletes failed sign-in counts that no longer affect lockouts,
+// so guesses at unknown emails and addresses do not pile up forever.
+type ThrottlePruner struct {
+	logger    *slog.Logger
+	throttles LoginThrottlePruner
+
+	loop loop
+}
+
+func NewThrottlePruner(logger *slog.Logger, throttles LoginThrottlePruner) *ThrottlePruner {
+	return &ThrottlePruner{logger: logger, throttles: throttles}
+}
+
+func (p *ThrottlePruner) Start(ctx context.Context) {
+	p.loop.start(ctx, throttlePruneInterval, func(ctx context.Context) {
+		pruned, err := p.throttles.PruneLoginThrottles(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				p.logger.Error("login throttle prune failed", "error", err)
+			}
+			return
+		}
+		if pruned > 0 {
+			p.logger.Info("login throttles pruned", "count", pruned)
+		}
+	})
+}
+
+func (p *ThrottlePruner) Stop(ctx context.Context) error {
+	return p.loop.stop(ctx)
+}
@@ -0,0 +1,56 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const trashPurgeInterval = time.Hour
+
+type TrashedPostPurger interface {
+	PurgeTrashedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// TrashPurger permanently removes posts that have been in the trash for longer
+// than the retention period.
+type TrashPurger struct {
+	logger    *slog.Logger
+	posts     TrashedPostPurger
+	retention time.Duration
+	now       func() time.Time
+
+	loop loop
+}
+
+func NewTrashPurger(logger *slog.Logger, posts TrashedPostPurger, retention time.Duration) *TrashPurger {
+	return &TrashPurger{
+		logger:    logger,
+		posts:     posts,
+		retention: retention,
+		now:       func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (p *TrashPurger) Start(ctx context.Context) {
+	p.loop.start(ctx, trashPurgeInterval, func(ctx context.Context) {
+		if _, err := p.PurgeExpired(ctx); err != nil && ctx.Err() == nil {
+			p.logger.Error("trash purge failed", "error", err)
+		}
+	})
+}
+
+func (p *TrashPurger) Stop(ctx context.Context) error {
+	return p.loop.stop(ctx)
+}
+
+func (p *TrashPurger) PurgeExpired(ctx context.Context) (int64, error) {
+	purged, err := p.posts.PurgeTrashedBefore(ctx, p.now().Add(-p.retention))
+	if err != nil {
+		return 0, err
+	}
+	if purged > 0 {
+		p.logger.Info("trashed posts purged", "count", purged)
+	}
+	return purged, nil
+}
@@ -0,0 +1,35 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+type fakeTrash struct {
+	cutoff time.Time
+}
+
+func (f *fakeTrash) PurgeTrashedBefore(_ context.Context, cutoff time.Time) (int64, error) {
+	f.cutoff = cutoff
+	return 2, nil
+}
+
+func TestTrashPurgerUsesRetentionCutoff(t *testing.T) {
+	trash := &fakeTrash{}
+	purger := NewTrashPurger(slog.Default(), trash, 30*24*time.Hour)
+	fixed := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	purger.now = func() time.Time { return fixed }
+
+	purged, err := purger.PurgeExpired(context.Background())
+	if err != nil {
+		t.Fatalf("expected purge to succeed: %v", err)
+	}
+	if purged != 2 {
+		t.Fatalf("expected purged count to be passed through, got %d", purged)
+	}
+	if want := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC); !trash.cutoff.Equal(want) {
+		t.Fatalf("expected cutoff %v, got %v", want, trash.cutoff)
+	}
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS post_slug_history;
+DROP INDEX IF EXISTS idx_posts_slug_unique;
+ALTER TABLE posts DROP COLUMN IF EXISTS slug;
@@ -0,0 +1,25 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS slug TEXT;
+
+-- Existing rows get a title-derived slug suffixed with part of their id so the
+-- backfill can never collide. Like slugify, hyphens are trimmed from both ends
+-- (again after the length cap), and a title without letters or digits falls
+-- back to "post".
+UPDATE posts
+SET slug = coalesce(
+        nullif(trim(both '-' from left(trim(both '-' from regexp_replace(lower(title), '[^a-z0-9]+', '-', 'g')), 80)), ''),
+        'post'
+    ) || '-' || left(id::text, 8)
+WHERE slug IS NULL;
+
+ALTER TABLE posts ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_posts_slug_unique ON posts(slug);
+
+CREATE TABLE IF NOT EXISTS post_slug_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    slug TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_post_slug_history_slug_unique ON post_slug_history(slug);
+CREATE INDEX IF NOT EXISTS idx_post_slug_history_post ON post_slug_history(post_id);
@@ -0,0 +1,4 @@
+ALTER TABLE posts DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS post_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,7 @@
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_status_check;
+ALTER TABLE posts ADD CONSTRAINT posts_status_check CHECK (status IN ('draft', 'published', 'scheduled'));
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+ALTER TABLE posts ADD CONSTRAINT posts_scheduled_publish_at_check CHECK (status <> 'scheduled' OR publish_at IS NOT NULL);
+
+CREATE INDEX IF NOT EXISTS idx_posts_scheduled_publish_at ON posts(publish_at) WHERE status = 'scheduled';
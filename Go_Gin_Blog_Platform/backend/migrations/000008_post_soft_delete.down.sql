@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_posts_deleted_at;
+
+DELETE FROM posts WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,8 @@
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS family_id UUID;
+
+-- Tokens issued before families existed each start a family of their own.
+UPDATE refresh_tokens SET family_id = id WHERE family_id IS NULL;
+
+ALTER TABLE refresh_tokens ALTER COLUMN family_id SET DEFAULT gen_random_uuid();
+ALTER TABLE refresh_tokens ALTER COLUMN family_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS email_change_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    new_email TEXT NOT NULL,
+    token_hash TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_change_tokens_hash_unique ON email_change_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_email_change_tokens_user_used ON email_change_tokens(user_id, used_at);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS signing_keys (
+    id TEXT PRIMARY KEY,
+    algorithm TEXT NOT NULL CHECK (algorithm IN ('RS256', 'EdDSA')),
+    private_key TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'previous', 'retired')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    rotated_at TIMESTAMPTZ,
+    retired_at TIMESTAMPTZ
+);
+
+-- At most one key signs at a time; this also settles instances racing to
+-- create the first key.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_signing_keys_single_active ON signing_keys(status) WHERE status = 'active';
@@ -0,0 +1,7 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_fkey;
+UPDATE users SET role = 'author' WHERE role IN ('editor', 'contributor');
+UPDATE users SET role = 'reader' WHERE role NOT IN ('admin', 'author', 'reader');
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('admin', 'author', 'reader'));
+
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS roles (
+    name TEXT PRIMARY KEY,
+    built_in BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role TEXT NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission TEXT NOT NULL,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, built_in) VALUES
+    ('admin', TRUE),
+    ('editor', TRUE),
+    ('author', TRUE),
+    ('contributor', TRUE),
+    ('reader', TRUE)
+ON CONFLICT (name) DO NOTHING;
+
+-- admin holds every permission in code; its rows are informational.
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'post.create'),
+    ('admin', 'post.publish'),
+    ('admin', 'post.edit.any'),
+    ('admin', 'post.read.any'),
+    ('admin', 'post.purge'),
+    ('admin', 'comment.moderate'),
+    ('admin', 'taxonomy.manage'),
+    ('admin', 'user.manage'),
+    ('admin', 'user.role.assign'),
+    ('admin', 'role.manage'),
+    ('editor', 'post.create'),
+    ('editor', 'post.publish'),
+    ('editor', 'post.edit.any'),
+    ('editor', 'post.read.any'),
+    ('editor', 'comment.moderate'),
+    ('editor', 'taxonomy.manage'),
+    ('author', 'post.create'),
+    ('author', 'post.publish'),
+    ('contributor', 'post.create')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_fkey FOREIGN KEY (role) REFERENCES roles(name);
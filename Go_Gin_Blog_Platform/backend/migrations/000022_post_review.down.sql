@@ -0,0 +1,14 @@
+DELETE FROM role_permissions WHERE permission = 'post.review';
+
+DROP TABLE IF EXISTS post_review_events;
+
+DROP INDEX IF EXISTS idx_posts_review_queue;
+DROP INDEX IF EXISTS idx_posts_reviewer_id;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS submitted_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS reviewer_id;
+
+UPDATE posts SET status = 'draft' WHERE status IN ('in_review', 'approved');
+
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_status_check;
+ALTER TABLE posts ADD CONSTRAINT posts_status_check CHECK (status IN ('draft', 'published', 'scheduled'));
@@ -0,0 +1,27 @@
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_status_check;
+ALTER TABLE posts ADD CONSTRAINT posts_status_check CHECK (status IN ('draft', 'in_review', 'approved', 'published', 'scheduled'));
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS reviewer_id UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS submitted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_posts_reviewer_id ON posts(reviewer_id);
+CREATE INDEX IF NOT EXISTS idx_posts_review_queue ON posts(submitted_at) WHERE status = 'in_review';
+
+CREATE TABLE IF NOT EXISTS post_review_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action TEXT NOT NULL,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    reviewer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_review_events_post_created ON post_review_events(post_id, created_at);
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'post.review'),
+    ('editor', 'post.review')
+ON CONFLICT DO NOTHING;
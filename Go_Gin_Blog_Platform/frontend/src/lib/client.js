@@ -64,6 +64,19 @@ export function createApiClient(auth) {
       });
     },
 
+    async reviewPost(postId, input) {
+      return doAuthRequest(`/posts/${postId}/review`, {
+        method: 'POST',
+        body: input
+      });
+    },
+
+    async reviewQueue(page = 1, limit = 10, assigned = '') {
+      return doAuthRequest(`/review-queue${queryString({ page, limit, assigned })}`, {
+        method: 'GET'
+      });
+    },
+
     async listUsers(page = 1, limit = 10) {
       return doAuthRequest(`/admin/users${queryString({ page, limit })}`, {
         method: 'GET'
@@ -75,6 +88,18 @@ export function createApiClient(auth) {
         method: 'PATCH',
         body: { role }
       });
+    },
+
+    async listRoles() {
+      return doAuthRequest('/admin/roles', {
+        method: 'GET'
+      });
+    },
+
+    async listAuditEvents(filters = {}, cursor = '', limit = 50) {
+      return doAuthRequest(`/admin/audit${queryString({ ...filters, cursor, limit })}`, {
+        method: 'GET'
+      });
     }
   };
 }
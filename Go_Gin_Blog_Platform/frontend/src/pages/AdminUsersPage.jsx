@@ -2,13 +2,14 @@ import { useCallback, useEffect, useMemo, useState } from 'react';
 import { createApiClient } from '../lib/client';
 import { useAuth } from '../context/AuthContext';
 
-const ROLE_OPTIONS = ['reader', 'author', 'admin'];
+const DEFAULT_ROLES = ['reader', 'contributor', 'author', 'editor', 'admin'];
 
 export default function AdminUsersPage() {
   const auth = useAuth();
   const client = useMemo(() => createApiClient(auth), [auth]);
 
   const [users, setUsers] = useState([]);
+  const [roles, setRoles] = useState(DEFAULT_ROLES);
   const [meta, setMeta] = useState({ page: 1, limit: 10, total_pages: 1, total: 0 });
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState('');
@@ -31,6 +32,20 @@ export default function AdminUsersPage() {
     loadUsers(1, 10);
   }, [loadUsers]);
 
+  useEffect(() => {
+    // Custom roles only show up once the role list loads; the built-in ones
+    // cover callers who cannot manage roles.
+    client
+      .listRoles()
+      .then((payload) => {
+        const names = (payload?.data || []).map((role) => role.name);
+        if (names.length > 0) {
+          setRoles(names);
+        }
+      })
+      .catch(() => {});
+  }, [client]);
+
   const onChangeRole = async (userId, role) => {
     setError('');
     try {
@@ -45,7 +60,7 @@ export default function AdminUsersPage() {
     <section className="stack">
       <div className="section-title">
         <h1>Admin: Users</h1>
-        <p>Manage user roles for reader, author, and admin access.</p>
+        <p>Assign each user a role. What a role may do is set under roles and permissions.</p>
       </div>
 
       <article className="card">
@@ -69,7 +84,7 @@ export default function AdminUsersPage() {
                     <td className="mono">{user.id}</td>
                     <td>
                       <select value={user.role} onChange={(event) => onChangeRole(user.id, event.target.value)}>
-                        {ROLE_OPTIONS.map((role) => (
+                        {roles.map((role) => (
                           <option key={role} value={role}>
                             {role}
                           </option>
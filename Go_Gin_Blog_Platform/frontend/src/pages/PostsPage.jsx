@@ -5,7 +5,7 @@ import { useAuth } from '../context/AuthContext';
 const DEFAULT_DRAFT = {
   title: '',
   content: '',
-  status: 'published'
+  status: 'draft'
 };
 
 export default function PostsPage() {
@@ -21,7 +21,7 @@ export default function PostsPage() {
   const [editingPostId, setEditingPostId] = useState('');
   const [editingDraft, setEditingDraft] = useState(DEFAULT_DRAFT);
 
-  const canWrite = auth.user?.role === 'author' || auth.user?.role === 'admin';
+  const canWrite = ['contributor', 'author', 'editor', 'admin'].includes(auth.user?.role);
 
   const loadPosts = useCallback(async (page = 1, limit = 10) => {
     setLoading(true);
@@ -158,8 +158,9 @@ export default function PostsPage() {
                 value={draft.status}
                 onChange={(event) => setDraft((curr) => ({ ...curr, status: event.target.value }))}
               >
-                <option value="published">published</option>
                 <option value="draft">draft</option>
+                <option value="in_review">submit for review</option>
+                <option value="published">published</option>
               </select>
             </label>
             <button type="submit" disabled={creating}>
@@ -225,6 +226,8 @@ export default function PostsPage() {
                         >
                           <option value="published">published</option>
                           <option value="draft">draft</option>
+                          <option value="in_review">in review</option>
+                          <option value="approved">approved</option>
                         </select>
                       </label>
                       <div className="row-actions">